@@ -0,0 +1,24 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/operator"
+)
+
+func main() {
+
+	docs, err := operator.CRDDocs()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for name, doc := range docs {
+		err = ioutil.WriteFile(filepath.Join("./docs/crds/", name), doc, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
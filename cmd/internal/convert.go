@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/bpm"
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/bpmconverter"
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/converter"
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/withops"
+	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/cmd"
+)
+
+const convertFailedMessage = "convert command failed."
+
+// convertCmd renders a BOSH manifest to the Kubernetes resources the operator
+// would create for it, without a cluster
+var convertCmd = &cobra.Command{
+	Use:   "convert [flags]",
+	Short: "Converts a BOSH manifest to Kubernetes resources",
+	Long: `Converts a BOSH manifest to Kubernetes resources, without a cluster.
+
+This will apply the ops files to the manifest, interpolate the variables from
+a folder, resolve the properties of all instance groups and print the
+QuarksSecrets, QuarksStatefulSets, QuarksJobs, Services and PVCs, which the
+operator would create for the deployment, as YAML.
+
+Explicit variables, which are not in the variables folder, are generated by
+the QuarksSecrets. The command fails for implicit variables, which are not in
+the variables folder.
+
+The job specs have to be extracted to the 'jobs-src' folder of the base
+directory, like the instance-group command expects them. Rendering the BPM
+templates of the jobs requires ruby.
+`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		boshManifestFlagViperBind(cmd.Flags())
+		baseDirFlagViperBind(cmd.Flags())
+		deploymentNameFlagViperBind(cmd.Flags())
+		viper.BindPFlag("ops", cmd.Flags().Lookup("ops"))
+		viper.BindPFlag("variables-dir", cmd.Flags().Lookup("variables-dir"))
+		viper.BindPFlag("output", cmd.Flags().Lookup("output"))
+	},
+
+	RunE: func(_ *cobra.Command, args []string) (err error) {
+		defer func() {
+			if err != nil {
+				time.Sleep(debugGracePeriod)
+			}
+		}()
+
+		log = cmd.Logger()
+		defer log.Sync()
+
+		boshManifestPath, err := boshManifestFlagValidation()
+		if err != nil {
+			return errors.Wrap(err, convertFailedMessage)
+		}
+
+		baseDir, err := baseDirFlagValidation()
+		if err != nil {
+			return errors.Wrap(err, convertFailedMessage)
+		}
+
+		deploymentName, err := deploymentNameFlagValidation()
+		if err != nil {
+			return errors.Wrap(err, convertFailedMessage)
+		}
+
+		namespace := viper.GetString("cf-operator-namespace")
+		if len(namespace) == 0 {
+			return errors.Errorf("%s cf-operator-namespace flag is empty.", convertFailedMessage)
+		}
+
+		boshManifestBytes, err := ioutil.ReadFile(boshManifestPath)
+		if err != nil {
+			return errors.Wrapf(err, "%s Reading file specified in the bosh-manifest-path flag failed. Please check the filepath to continue.", convertFailedMessage)
+		}
+
+		withOpsBytes, err := applyOps(boshManifestBytes, viper.GetStringSlice("ops"))
+		if err != nil {
+			return errors.Wrap(err, convertFailedMessage)
+		}
+
+		withOps, err := manifest.LoadYAML(withOpsBytes)
+		if err != nil {
+			return errors.Wrapf(err, "%s Loading BOSH manifest file failed. Please check the file contents and try again.", convertFailedMessage)
+		}
+
+		err = withOps.ApplyAddons()
+		if err != nil {
+			return errors.Wrapf(err, "%s failed to apply addons.", convertFailedMessage)
+		}
+
+		dns, err := boshdns.NewDNS(deploymentName, *withOps)
+		if err != nil {
+			return errors.Wrapf(err, "%s Loading DNS for BOSH manifest failed.", convertFailedMessage)
+		}
+		withOps.ApplyUpdateBlock(dns)
+
+		quarksSecrets, err := converter.NewVariablesConverter().Variables(deploymentName, namespace, withOps.Variables)
+		if err != nil {
+			return errors.Wrapf(err, "%s failed to generate quarks secrets from manifest.", convertFailedMessage)
+		}
+
+		desiredBytes, err := withOps.Marshal()
+		if err != nil {
+			return errors.Wrapf(err, "%s YAML marshalling manifest with ops failed.", convertFailedMessage)
+		}
+
+		// Variables, which are not in the variables dir, are kept as placeholders
+		if variablesDir := viper.GetString("variables-dir"); variablesDir != "" {
+			desiredBytes, err = manifest.InterpolateVariablesFromDir(desiredBytes, variablesDir)
+			if err != nil {
+				return errors.Wrapf(err, "%s failed to interpolate variables.", convertFailedMessage)
+			}
+		}
+
+		desired, err := manifest.LoadYAML(desiredBytes)
+		if err != nil {
+			return errors.Wrapf(err, "%s Loading desired manifest failed.", convertFailedMessage)
+		}
+
+		// Explicit variables are generated by QuarksSecrets, implicit ones
+		// have to be provided
+		implicitVars, err := desired.ImplicitVariables()
+		if err != nil {
+			return errors.Wrapf(err, "%s failed to list implicit variables.", convertFailedMessage)
+		}
+		if len(implicitVars) > 0 {
+			return errors.Errorf("%s unresolved implicit variables: %s. Please add them to the variables dir.", convertFailedMessage, strings.Join(implicitVars, ", "))
+		}
+
+		bpmConverter := bpmconverter.NewConverter(
+			bpmconverter.NewVolumeFactory(),
+			func(deploymentName string, instanceGroupName string, version string, disableLogSidecar bool, releaseImageProvider manifest.ReleaseImageProvider, bpmConfigs bpm.Configs) bpmconverter.ContainerFactory {
+				return bpmconverter.NewContainerFactory(deploymentName, instanceGroupName, version, disableLogSidecar, releaseImageProvider, bpmConfigs)
+			})
+
+		var objects []interface{}
+		for i := range quarksSecrets {
+			quarksSecrets[i].TypeMeta = typeMeta(qsv1a1.SchemeGroupVersion.String(), qsv1a1.QuarksSecretResourceKind)
+			objects = append(objects, quarksSecrets[i])
+		}
+
+		for _, ig := range desired.InstanceGroups {
+			// The resolver changes the manifest it is given, so every instance group gets its own copy
+			igManifest, err := manifest.LoadYAML(desiredBytes)
+			if err != nil {
+				return errors.Wrapf(err, "%s Loading desired manifest failed.", convertFailedMessage)
+			}
+
+			igr, err := manifest.NewInstanceGroupResolver(afero.NewOsFs(), baseDir, deploymentName, *igManifest, ig.Name, dns)
+			if err != nil {
+				return errors.Wrap(err, convertFailedMessage)
+			}
+
+			err = igr.Resolve(true)
+			if err != nil {
+				return errors.Wrapf(err, "%s failed to resolve instance group '%s'.", convertFailedMessage, ig.Name)
+			}
+
+			bpmInfo, err := igr.BPMInfo()
+			if err != nil {
+				return errors.Wrap(err, convertFailedMessage)
+			}
+
+			resources, err := bpmConverter.Resources(deploymentName, namespace, dns, "1", ig, desired, bpmInfo.Configs, "1")
+			if err != nil {
+				return errors.Wrapf(err, "%s failed to convert instance group '%s'.", convertFailedMessage, ig.Name)
+			}
+
+			for i := range resources.InstanceGroups {
+				resources.InstanceGroups[i].TypeMeta = typeMeta(qstsv1a1.SchemeGroupVersion.String(), qstsv1a1.QuarksStatefulSetResourceKind)
+				objects = append(objects, resources.InstanceGroups[i])
+			}
+			for i := range resources.Errands {
+				resources.Errands[i].TypeMeta = typeMeta(qjv1a1.SchemeGroupVersion.String(), qjv1a1.QuarksJobResourceKind)
+				objects = append(objects, resources.Errands[i])
+			}
+			for i := range resources.Services {
+				resources.Services[i].TypeMeta = typeMeta(corev1.SchemeGroupVersion.String(), "Service")
+				objects = append(objects, resources.Services[i])
+			}
+			for i := range resources.PersistentVolumeClaims {
+				resources.PersistentVolumeClaims[i].TypeMeta = typeMeta(corev1.SchemeGroupVersion.String(), "PersistentVolumeClaim")
+				objects = append(objects, resources.PersistentVolumeClaims[i])
+			}
+		}
+
+		out := io.Writer(os.Stdout)
+		if output := viper.GetString("output"); output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				return errors.Wrapf(err, "%s Creating output file failed.", convertFailedMessage)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		return writeYAMLDocuments(out, objects)
+	},
+}
+
+// applyOps applies the ops files to the manifest, like the with-ops resolver
+// does for a BOSHDeployment
+func applyOps(boshManifestBytes []byte, opsPaths []string) ([]byte, error) {
+	if len(opsPaths) == 0 {
+		return boshManifestBytes, nil
+	}
+
+	interpolator := withops.NewInterpolator()
+	for _, opsPath := range opsPaths {
+		opsBytes, err := ioutil.ReadFile(opsPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Reading ops file '%s' failed", opsPath)
+		}
+		err = interpolator.BuildOps(opsBytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Building ops from '%s' failed", opsPath)
+		}
+	}
+
+	interpolated, err := interpolator.Interpolate(boshManifestBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Applying ops files failed")
+	}
+	return interpolated, nil
+}
+
+// writeYAMLDocuments writes the objects as a multi-document YAML stream
+func writeYAMLDocuments(out io.Writer, objects []interface{}) error {
+	var buf bytes.Buffer
+	for _, obj := range objects {
+		objBytes, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "%s YAML marshalling resource failed.", convertFailedMessage)
+		}
+		buf.WriteString("---\n")
+		buf.Write(objBytes)
+	}
+
+	_, err := buf.WriteTo(out)
+	if err != nil {
+		return errors.Wrapf(err, "%s Writing output failed.", convertFailedMessage)
+	}
+	return nil
+}
+
+func typeMeta(apiVersion, kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: apiVersion, Kind: kind}
+}
+
+func init() {
+	utilCmd.AddCommand(convertCmd)
+
+	pf := convertCmd.Flags()
+	argToEnv := map[string]string{}
+
+	boshManifestFlagCobraSet(pf, argToEnv)
+	baseDirFlagCobraSet(pf, argToEnv)
+	deploymentNameFlagCobraSet(pf, argToEnv)
+
+	pf.StringSlice("ops", []string{}, "paths to ops files, applied in the given order")
+	pf.StringP("variables-dir", "v", "", "path to the variables dir, explicit variables which are not found are not interpolated")
+	pf.StringP("output", "o", "", "path of the file to which the YAML output is written, defaults to STDOUT")
+	argToEnv["ops"] = "OPS"
+	argToEnv["variables-dir"] = "VARIABLES_DIR"
+	argToEnv["output"] = "OUTPUT"
+
+	cmd.AddEnvToUsage(convertCmd, argToEnv)
+}
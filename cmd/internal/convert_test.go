@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	cmd "code.cloudfoundry.org/cf-operator/cmd/internal"
+)
+
+const convertManifest = `---
+name: test
+releases:
+- name: cflinuxfs3
+  version: 0.62.0
+  url: hub.docker.com/cfcontainerization
+  sha1: 6466c44827c3493645ca34b084e7c21de23272b4
+  stemcell:
+    os: opensuse-15.0
+    version: 28.g837c5b3-30.263-7.0.0_233.gde0accd0
+instance_groups:
+- name: rootfs
+  instances: 1
+  jobs:
+  - name: cflinuxfs3-rootfs-setup
+    release: cflinuxfs3
+    properties:
+      cflinuxfs3-rootfs:
+        trusted_certs: ((adminpass))
+      quarks:
+        bpm:
+          processes:
+          - name: rootfs
+            executable: sleep
+            args: ["1000"]
+        ports:
+        - name: rep-server
+          protocol: TCP
+          internal: 1801
+variables:
+- name: adminpass
+  type: password
+`
+
+var _ = Describe("Convert", func() {
+	var (
+		tmpDir       string
+		manifestPath string
+		outFile      string
+	)
+
+	type resource struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+
+	convert := func(manifest string) error {
+		err := ioutil.WriteFile(manifestPath, []byte(manifest), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		root := cmd.NewCFOperatorCommand()
+		root.SetArgs([]string{
+			"util", "convert",
+			"-m", manifestPath,
+			"-b", "../../testing/assets",
+			"-n", "test",
+			"-o", outFile,
+		})
+		return root.Execute()
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "convert")
+		Expect(err).ToNot(HaveOccurred())
+		manifestPath = filepath.Join(tmpDir, "manifest.yml")
+		outFile = filepath.Join(tmpDir, "out.yml")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("renders the resources for the manifest", func() {
+		Expect(convert(convertManifest)).To(Succeed())
+
+		out, err := ioutil.ReadFile(outFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		resources := []string{}
+		for _, doc := range strings.Split(string(out), "---\n") {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			r := resource{}
+			Expect(yaml.Unmarshal([]byte(doc), &r)).To(Succeed())
+			resources = append(resources, r.Kind+"/"+r.Metadata.Name)
+		}
+
+		Expect(resources).To(ConsistOf(
+			"QuarksSecret/test.var-adminpass",
+			"QuarksStatefulSet/test-rootfs",
+			"Service/test-rootfs-0",
+			"Service/test-rootfs",
+		))
+	})
+
+	It("fails for unresolved implicit variables", func() {
+		manifest := strings.Replace(convertManifest, "((adminpass))", "((system_domain))", 1)
+
+		err := convert(manifest)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unresolved implicit variables: system_domain"))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -15,6 +16,7 @@ import (
 	"code.cloudfoundry.org/cf-operator/pkg/bosh/converter"
 	"code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
 	"code.cloudfoundry.org/cf-operator/pkg/bosh/qjobs"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
 	"code.cloudfoundry.org/quarks-utils/pkg/cmd"
 )
@@ -39,6 +41,7 @@ Also calculates and prints the BPM configurations for all BOSH jobs of that inst
 		instanceGroupFlagViperBind(cmd.Flags())
 		outputFilePathFlagViperBind(cmd.Flags())
 		initialRolloutFlagViperBind(cmd.Flags())
+		propertyValidationFlagViperBind(cmd.Flags())
 	},
 
 	RunE: func(_ *cobra.Command, args []string) (err error) {
@@ -81,6 +84,11 @@ Also calculates and prints the BPM configurations for all BOSH jobs of that inst
 			return errors.Wrap(err, igFailedMessage)
 		}
 
+		propertyValidation, err := propertyValidationFlagValidation()
+		if err != nil {
+			return errors.Wrap(err, igFailedMessage)
+		}
+
 		boshManifestBytes, err := ioutil.ReadFile(boshManifestPath)
 		if err != nil {
 			return errors.Wrapf(err, "%s Reading file specified in the bosh-manifest-path flag failed. Please check the filepath to continue.", igFailedMessage)
@@ -112,6 +120,15 @@ Also calculates and prints the BPM configurations for all BOSH jobs of that inst
 			return errors.Wrapf(err, "%s failed to resolve manifest.", igFailedMessage)
 		}
 
+		var propertyWarnings []string
+		if propertyValidation != "" {
+			findings := igr.ValidateProperties()
+			if propertyValidation == bdv1.PropertyValidationStrict && len(findings) > 0 {
+				return errors.Errorf("%s job properties don't match the job specs: %s", igFailedMessage, strings.Join(findings, "; "))
+			}
+			propertyWarnings = findings
+		}
+
 		manifest, err := igr.Manifest()
 		if err != nil {
 			return errors.Wrap(err, igFailedMessage)
@@ -122,6 +139,11 @@ Also calculates and prints the BPM configurations for all BOSH jobs of that inst
 			return errors.Wrapf(err, "%s failed to write link output to file.", igFailedMessage)
 		}
 
+		err = igr.SaveSharedLinks(outputFilePath)
+		if err != nil {
+			return errors.Wrapf(err, "%s failed to write shared link output to file.", igFailedMessage)
+		}
+
 		// write instance group manifest
 		propertiesBytes, err := manifest.Marshal()
 		if err != nil {
@@ -145,6 +167,7 @@ Also calculates and prints the BPM configurations for all BOSH jobs of that inst
 		if err != nil {
 			return errors.Wrap(err, igFailedMessage)
 		}
+		bpmInfo.PropertyWarnings = propertyWarnings
 
 		bpmBytes, err := yaml.Marshal(bpmInfo)
 		if err != nil {
@@ -179,5 +202,6 @@ func init() {
 	instanceGroupFlagCobraSet(pf, argToEnv)
 	outputFilePathFlagCobraSet(pf, argToEnv)
 	initialRolloutFlagCobraSet(pf, argToEnv)
+	propertyValidationFlagCobraSet(pf, argToEnv)
 	cmd.AddEnvToUsage(instanceGroupCmd, argToEnv)
 }
@@ -0,0 +1,42 @@
+package kubectlquarks
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// runErrandCmd triggers an errand, like `bosh run-errand`
+var runErrandCmd = &cobra.Command{
+	Use:   "run-errand ERRAND [flags]",
+	Short: "Triggers an errand of a deployment",
+	Long: `Triggers an errand of a deployment, like 'bosh run-errand'.
+
+This triggers the QuarksJob of the errand. It doesn't wait for the errand to
+finish.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		deploymentName, err := deploymentFlagValidation()
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		cli, err := newCLI()
+		if err != nil {
+			return err
+		}
+
+		err = cli.RunErrand(deploymentName, args[0])
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		fmt.Printf("Triggered errand '%s'\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runErrandCmd)
+}
@@ -0,0 +1,97 @@
+package kubectlquarks
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// instanceGroupsCmd lists the instance groups of a deployment
+var instanceGroupsCmd = &cobra.Command{
+	Use:   "instance-groups [flags]",
+	Short: "Lists the instance groups and errands of a deployment",
+	RunE: func(_ *cobra.Command, args []string) error {
+		deploymentName, err := deploymentFlagValidation()
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		cli, err := newCLI()
+		if err != nil {
+			return err
+		}
+
+		igs, err := cli.InstanceGroups(deploymentName)
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "INSTANCE GROUP\tTYPE\tINSTANCES\tREADY")
+		for _, ig := range igs {
+			if ig.Errand {
+				fmt.Fprintf(w, "%s\terrand\t-\t-\n", ig.Name)
+				continue
+			}
+			fmt.Fprintf(w, "%s\tservice\t%d\t%d\n", ig.Name, ig.Instances, ig.Ready)
+		}
+		return w.Flush()
+	},
+}
+
+// instancesCmd lists the instances of a deployment, like `bosh instances`
+var instancesCmd = &cobra.Command{
+	Use:     "instances [flags]",
+	Aliases: []string{"vms"},
+	Short:   "Lists the instances of a deployment",
+	Long: `Lists the instances of a deployment, like 'bosh instances'.
+
+The index of an instance is calculated from the AZ index and the ordinal of its
+pod. The instance with index 0 is the bootstrap instance.
+`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("instance-group", cmd.Flags().Lookup("instance-group"))
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		deploymentName, err := deploymentFlagValidation()
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		cli, err := newCLI()
+		if err != nil {
+			return err
+		}
+
+		instances, err := cli.Instances(deploymentName, viper.GetString("instance-group"))
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "INSTANCE\tPOD\tSTATE\tREADY\tAZ\tINDEX\tBOOTSTRAP")
+		for _, instance := range instances {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				instance.Name(),
+				instance.Pod,
+				instance.Phase,
+				strconv.FormatBool(instance.Ready),
+				instance.AZ,
+				instance.Index,
+				strconv.FormatBool(instance.Bootstrap),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(instanceGroupsCmd)
+	rootCmd.AddCommand(instancesCmd)
+
+	instancesCmd.Flags().StringP("instance-group", "g", "", "Only list the instances of this instance group")
+}
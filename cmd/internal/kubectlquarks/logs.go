@@ -0,0 +1,72 @@
+package kubectlquarks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// logsCmd downloads the logs of an instance, like `bosh logs`
+var logsCmd = &cobra.Command{
+	Use:   "logs INSTANCE [flags]",
+	Short: "Downloads the logs of an instance",
+	Long: `Downloads the logs of an instance, like 'bosh logs'.
+
+This writes a gzipped tar archive of /var/vcap/sys/log of the instance to the
+directory. Only the logs of the job are archived, if a job is given.
+`,
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("job", cmd.Flags().Lookup("job"))
+		viper.BindPFlag("dir", cmd.Flags().Lookup("dir"))
+	},
+	RunE: func(_ *cobra.Command, args []string) (err error) {
+		deploymentName, err := deploymentFlagValidation()
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		cli, err := newCLI()
+		if err != nil {
+			return err
+		}
+
+		fileName := fmt.Sprintf("%s.%s-%s.tgz",
+			deploymentName,
+			strings.Replace(args[0], "/", ".", -1),
+			time.Now().UTC().Format("20060102-150405"),
+		)
+		path := filepath.Join(viper.GetString("dir"), fileName)
+
+		f, err := os.Create(path)
+		if err != nil {
+			return wrapError(err, "Couldn't create logs archive.")
+		}
+		defer func() {
+			f.Close()
+			if err != nil {
+				os.Remove(path)
+			}
+		}()
+
+		err = cli.Logs(deploymentName, args[0], viper.GetString("job"), f, os.Stderr)
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		fmt.Println(path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().StringP("job", "j", "", "Only download the logs of this job")
+	logsCmd.Flags().String("dir", ".", "Directory to write the logs archive to")
+}
@@ -0,0 +1,47 @@
+package kubectlquarks
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// manifestCmd prints the desired manifest of a deployment, like `bosh manifest`
+var manifestCmd = &cobra.Command{
+	Use:   "manifest [flags]",
+	Short: "Prints the desired manifest of a deployment",
+	Long: `Prints the desired manifest of a deployment, like 'bosh manifest'.
+
+The desired manifest has ops files applied and variables interpolated. The
+values of variables are redacted, unless --show-secrets is passed.
+`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		viper.BindPFlag("show-secrets", cmd.Flags().Lookup("show-secrets"))
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		deploymentName, err := deploymentFlagValidation()
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		cli, err := newCLI()
+		if err != nil {
+			return err
+		}
+
+		manifest, err := cli.Manifest(context.Background(), deploymentName, viper.GetBool("show-secrets"))
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		_, err = os.Stdout.Write(manifest)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+	manifestCmd.Flags().Bool("show-secrets", false, "Print the values of variables instead of redacting them")
+}
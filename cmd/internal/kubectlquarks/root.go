@@ -0,0 +1,111 @@
+package kubectlquarks
+
+import (
+	golog "log"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+	"k8s.io/client-go/tools/clientcmd"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/client/clientset/versioned"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/podexec"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/quarkscli"
+	qjversioned "code.cloudfoundry.org/quarks-job/pkg/kube/client/clientset/versioned"
+	"code.cloudfoundry.org/quarks-utils/pkg/cmd"
+)
+
+func wrapError(err error, msg string) error {
+	return errors.Wrap(err, "kubectl-quarks command failed. "+msg)
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "kubectl-quarks",
+	Short: "kubectl-quarks operates BOSH deployments on Kubernetes like the bosh CLI",
+	Long: `kubectl-quarks operates BOSH deployments on Kubernetes like the bosh CLI.
+
+Install it as 'kubectl-quarks' in the PATH to use it as 'kubectl quarks'.
+`,
+	SilenceUsage: true,
+}
+
+// NewKubectlQuarksCommand returns the `kubectl-quarks` command.
+func NewKubectlQuarksCommand() *cobra.Command {
+	return rootCmd
+}
+
+// Execute the root command
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		golog.Fatal(err)
+		os.Exit(1)
+	}
+}
+
+// newCLI creates the clients for the namespace of the kubeconfig context,
+// unless a namespace is given
+func newCLI() (*quarkscli.CLI, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = viper.GetString("kubeconfig")
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, wrapError(err, "Couldn't load kubeconfig.")
+	}
+
+	namespace := viper.GetString("namespace")
+	if namespace == "" {
+		namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return nil, wrapError(err, "Couldn't get namespace from kubeconfig.")
+		}
+	}
+
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, wrapError(err, "Couldn't create kube client.")
+	}
+	versionedClientSet, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, wrapError(err, "Couldn't create quarks client.")
+	}
+	quarksJobClientSet, err := qjversioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, wrapError(err, "Couldn't create quarks job client.")
+	}
+	podExec, err := podexec.NewPodExec(restConfig)
+	if err != nil {
+		return nil, wrapError(err, "")
+	}
+
+	return quarkscli.NewCLI(namespace, clientSet, versionedClientSet, quarksJobClientSet, podExec), nil
+}
+
+func deploymentFlagValidation() (string, error) {
+	deploymentName := viper.GetString("deployment")
+	if len(deploymentName) == 0 {
+		return "", errors.New("deployment flag is empty")
+	}
+	return deploymentName, nil
+}
+
+func init() {
+	pf := rootCmd.PersistentFlags()
+
+	argToEnv := map[string]string{}
+
+	pf.String("kubeconfig", "", "Path to a kubeconfig, defaults to the kubectl configuration")
+	pf.StringP("namespace", "n", "", "Namespace of the deployment, defaults to the namespace of the kubeconfig context")
+	pf.StringP("deployment", "d", "", "Name of the BOSHDeployment")
+	argToEnv["deployment"] = "BOSH_DEPLOYMENT"
+
+	for _, name := range []string{"kubeconfig", "namespace", "deployment"} {
+		viper.BindPFlag(name, pf.Lookup(name))
+	}
+
+	cmd.AddEnvToUsage(rootCmd, argToEnv)
+}
@@ -0,0 +1,67 @@
+package kubectlquarks
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// sshCmd opens a shell in a job container of an instance, like `bosh ssh`
+var sshCmd = &cobra.Command{
+	Use:   "ssh INSTANCE [flags] [-- COMMAND [args...]]",
+	Short: "Runs a shell or a command in a job container of an instance",
+	Long: `Runs a shell or a command in a job container of an instance, like 'bosh ssh'.
+
+The instance is given as 'instance-group/index'. The first job container of the
+instance is used, unless a job is given.
+`,
+	Args: cobra.MinimumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		viper.BindPFlag("job", cmd.Flags().Lookup("job"))
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		deploymentName, err := deploymentFlagValidation()
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		cli, err := newCLI()
+		if err != nil {
+			return err
+		}
+
+		options := remotecommand.StreamOptions{
+			Stdin:  os.Stdin,
+			Stdout: os.Stdout,
+			Stderr: os.Stderr,
+		}
+
+		// Interactive shells need a raw terminal, like `kubectl exec -it`
+		fd := int(os.Stdin.Fd())
+		if terminal.IsTerminal(fd) {
+			state, err := terminal.MakeRaw(fd)
+			if err != nil {
+				return wrapError(err, "Couldn't set up terminal.")
+			}
+			defer terminal.Restore(fd, state)
+
+			options.Stderr = nil
+			options.Tty = true
+		}
+
+		err = cli.SSH(deploymentName, args[0], viper.GetString("job"), args[1:], options)
+		if err != nil {
+			return wrapError(err, "")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+
+	sshCmd.Flags().StringP("job", "j", "", "Name of the job, whose container is used")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	golog "log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -16,9 +17,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	"code.cloudfoundry.org/cf-operator/pkg/kube/director"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/operator"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorimage"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
 	"code.cloudfoundry.org/cf-operator/version"
 	"code.cloudfoundry.org/quarks-utils/pkg/cmd"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
@@ -64,18 +70,58 @@ var rootCmd = &cobra.Command{
 		}
 
 		cmd.OperatorNamespace(cfg, log, "cf-operator-namespace")
-		cmd.WatchNamespace(cfg, log)
-		if cfg.Namespace == "" || cfg.OperatorNamespace == "" {
-			return wrapError(errors.New("both namespaces must be defined"), "")
+		watchnamespaces.SetNamespaces(strings.Split(viper.GetString("watch-namespaces"), ","))
+		err = watchnamespaces.SetSelector(viper.GetString("watch-namespace-selector"))
+		if err != nil {
+			return wrapError(err, "")
 		}
-		if cfg.Namespace == cfg.OperatorNamespace {
-			return wrapError(errors.New("watched namespace cannot be the same as the operators namespace"), "")
+		if watchnamespaces.Enabled() {
+			if cfg.OperatorNamespace == "" {
+				return wrapError(errors.New("the operator namespace must be defined"), "")
+			}
+			for _, namespace := range watchnamespaces.Namespaces() {
+				if namespace == cfg.OperatorNamespace {
+					return wrapError(errors.New("watched namespaces cannot contain the operators namespace"), "")
+				}
+			}
+		} else {
+			cmd.WatchNamespace(cfg, log)
+			if cfg.Namespace == "" || cfg.OperatorNamespace == "" {
+				return wrapError(errors.New("both namespaces must be defined"), "")
+			}
+			if cfg.Namespace == cfg.OperatorNamespace {
+				return wrapError(errors.New("watched namespace cannot be the same as the operators namespace"), "")
+			}
 		}
 
 		boshdns.SetBoshDNSDockerImage(viper.GetString("bosh-dns-docker-image"))
 		boshdns.SetClusterDomain(viper.GetString("cluster-domain"))
+		tracing.SetEnabled(viper.GetBool("tracing"))
+
+		ratelimit.SetDefaults(ratelimit.Options{
+			BaseDelay: viper.GetDuration("rate-limit-base-delay"),
+			MaxDelay:  viper.GetDuration("rate-limit-max-delay"),
+			QPS:       viper.GetFloat64("rate-limit-qps"),
+			Burst:     viper.GetInt("rate-limit-burst"),
+		})
+		err = ratelimit.SetOverrides(viper.GetString("rate-limits"))
+		if err != nil {
+			return wrapError(err, "")
+		}
+
+		operatorconfig.SetDefaults(map[string]string{
+			operatorconfig.KeyDockerImageOrg:        viper.GetString("docker-image-org"),
+			operatorconfig.KeyDockerImageRepository: viper.GetString("docker-image-repository"),
+			operatorconfig.KeyDockerImageTag:        viper.GetString("docker-image-tag"),
+			operatorconfig.KeyDockerImagePullPolicy: viper.GetString("docker-image-pull-policy"),
+			operatorconfig.KeyRateLimits:            viper.GetString("rate-limits"),
+		})
 
-		log.Infof("Starting cf-operator %s with namespace %s", version.Version, cfg.Namespace)
+		if watchnamespaces.Enabled() {
+			log.Infof("Starting cf-operator %s with namespaces '%s' and namespace selector '%s'", version.Version, strings.Join(watchnamespaces.Namespaces(), ","), viper.GetString("watch-namespace-selector"))
+		} else {
+			log.Infof("Starting cf-operator %s with namespace %s", version.Version, cfg.Namespace)
+		}
 		log.Infof("cf-operator docker image: %s", config.GetOperatorDockerImage())
 
 		serviceHost := viper.GetString("operator-webhook-service-host")
@@ -103,17 +149,68 @@ var rootCmd = &cobra.Command{
 			return wrapError(err, "Couldn't apply CRDs.")
 		}
 
-		mgr, err := operator.NewManager(ctx, cfg, restConfig, manager.Options{
-			Namespace:          cfg.Namespace,
-			MetricsBindAddress: "0",
-			LeaderElection:     false,
-			Port:               managerPort,
-			Host:               "0.0.0.0",
+		options := manager.Options{
+			Namespace:              cfg.Namespace,
+			MetricsBindAddress:     viper.GetString("metrics-bind-address"),
+			HealthProbeBindAddress: viper.GetString("health-probe-bind-address"),
+			LeaderElection:         false,
+			Port:                   managerPort,
+			Host:                   "0.0.0.0",
+		}
+		if watchnamespaces.Enabled() {
+			// Only a list of namespaces restricts the cache, a selector can
+			// match any namespace
+			namespaces := []string{}
+			if viper.GetString("watch-namespace-selector") == "" {
+				namespaces = watchnamespaces.Namespaces()
+			}
+			options.NewCache = operator.NewWatchNamespacesCache(cfg.OperatorNamespace, namespaces)
+		}
+
+		mgr, err := operator.NewManager(ctx, cfg, restConfig, options, operator.LeaderElectionOptions{
+			Enabled:       viper.GetBool("leader-election"),
+			Namespace:     viper.GetString("leader-election-namespace"),
+			ID:            viper.GetString("leader-election-id"),
+			LeaseDuration: viper.GetDuration("leader-election-lease-duration"),
+			RenewDeadline: viper.GetDuration("leader-election-renew-deadline"),
+			RetryPeriod:   viper.GetDuration("leader-election-retry-period"),
 		})
 		if err != nil {
 			return wrapError(err, "Failed to create new manager.")
 		}
 
+		if address := viper.GetString("director-api-address"); address != "" {
+			if watchnamespaces.Enabled() {
+				return wrapError(errors.New("the director API emulation requires a single watched namespace"), "")
+			}
+
+			server, err := director.NewServerForConfig(ctx, cfg.Namespace, restConfig, director.Options{
+				Address:  address,
+				CertFile: viper.GetString("director-api-tls-cert"),
+				KeyFile:  viper.GetString("director-api-tls-key"),
+				Username: viper.GetString("director-api-username"),
+				Password: viper.GetString("director-api-password"),
+			})
+			if err != nil {
+				return wrapError(err, "Failed to create director API.")
+			}
+
+			err = mgr.Add(server)
+			if err != nil {
+				return wrapError(err, "Failed to add director API to manager.")
+			}
+		}
+
+		if endpoint := viper.GetString("tracing-endpoint"); endpoint != "" && tracing.Enabled() {
+			exporter := tracing.NewOTLPExporter(ctx, endpoint)
+			tracing.SetExporter(exporter)
+
+			err = mgr.Add(exporter)
+			if err != nil {
+				return wrapError(err, "Failed to add tracing exporter to manager.")
+			}
+		}
+
 		ctxlog.Info(ctx, "Waiting for configurations to be applied into a BOSHDeployment resource...")
 
 		err = mgr.Start(signals.SetupSignalHandler())
@@ -153,9 +250,31 @@ func init() {
 
 	pf.StringP("bosh-dns-docker-image", "", "coredns/coredns:1.6.3", "The docker image used for emulating bosh DNS (a CoreDNS image)")
 	pf.String("cluster-domain", "cluster.local", "The Kubernetes cluster domain")
+	pf.String("health-probe-bind-address", ":8081", "Address on which the liveness probe '/healthz' and the readiness probe '/readyz' are served, disabled if '0'")
 	pf.Int("max-boshdeployment-workers", 1, "Maximum number of workers concurrently running BOSHDeployment controller")
 	pf.Int("max-quarks-secret-workers", 5, "Maximum number of workers concurrently running QuarksSecret controller")
 	pf.Int("max-quarks-statefulset-workers", 1, "Maximum number of workers concurrently running QuarksStatefulSet controller")
+	pf.String("director-api-address", "", "Address on which the BOSH director API emulation listens, e.g. ':25555'. Disabled if empty")
+	pf.String("director-api-username", "admin", "Username for the BOSH director API emulation")
+	pf.String("director-api-password", "", "Password for the BOSH director API emulation, required if the API is enabled")
+	pf.String("director-api-tls-cert", "", "Path to the TLS certificate of the BOSH director API emulation, required unless it listens on localhost")
+	pf.String("director-api-tls-key", "", "Path to the TLS key of the BOSH director API emulation")
+	pf.Bool("leader-election", false, "Enable leader election, so only one of several operator replicas runs the controllers")
+	pf.String("leader-election-id", "cf-operator-lock", "Name of the Lease used for leader election")
+	pf.String("leader-election-namespace", "", "Namespace of the Lease used for leader election, defaults to the operator namespace")
+	pf.Duration("leader-election-lease-duration", 15*time.Second, "Duration non-leader replicas wait before they try to acquire an expired Lease")
+	pf.Duration("leader-election-renew-deadline", 10*time.Second, "Duration the leader retries to renew the Lease before giving up leadership")
+	pf.Duration("leader-election-retry-period", 2*time.Second, "Duration between tries to acquire or renew the Lease")
+	pf.String("metrics-bind-address", ":60000", "Address on which the Prometheus metrics are served, disabled if '0'")
+	pf.Duration("rate-limit-base-delay", ratelimit.DefaultOptions.BaseDelay, "Delay before a failed reconcile is retried, doubles with every further failure of the same resource")
+	pf.Duration("rate-limit-max-delay", ratelimit.DefaultOptions.MaxDelay, "Maximum delay before a failed reconcile is retried")
+	pf.Float64("rate-limit-qps", ratelimit.DefaultOptions.QPS, "Rate of retried reconciles per controller")
+	pf.Int("rate-limit-burst", ratelimit.DefaultOptions.Burst, "Number of retried reconciles per controller, which can exceed the rate")
+	pf.String("rate-limits", "", "Comma separated rate limits of single controllers, which override the defaults, e.g. 'boshdeployment-controller.max-delay=5m,bpm-controller.qps=5'")
+	pf.Bool("tracing", false, "Propagate the trace context of a BOSHDeployment to the generated resources and record spans of their reconciles")
+	pf.String("tracing-endpoint", "", "OTLP/HTTP endpoint of an OpenTelemetry collector, the spans are exported to, e.g. 'http://otel-collector:4318'")
+	pf.String("watch-namespaces", "", "Comma separated list of namespaces to act on, instead of the watch namespace")
+	pf.String("watch-namespace-selector", "", "Label selector for namespaces to act on, instead of the watch namespace, e.g. 'cf-operator/watch=true'")
 	pf.StringP("operator-webhook-service-host", "w", "", "Hostname/IP under which the webhook server can be reached from the cluster")
 	pf.StringP("operator-webhook-service-port", "p", "2999", "Port the webhook server listens on")
 	pf.BoolP("operator-webhook-use-service-reference", "x", false, "If true the webhook service is targeted using a service reference instead of a URL")
@@ -163,24 +282,68 @@ func init() {
 	for _, name := range []string{
 		"bosh-dns-docker-image",
 		"cluster-domain",
+		"director-api-address",
+		"director-api-username",
+		"director-api-password",
+		"director-api-tls-cert",
+		"director-api-tls-key",
+		"health-probe-bind-address",
+		"leader-election",
+		"leader-election-id",
+		"leader-election-namespace",
+		"leader-election-lease-duration",
+		"leader-election-renew-deadline",
+		"leader-election-retry-period",
 		"max-boshdeployment-workers",
 		"max-quarks-secret-workers",
 		"max-quarks-statefulset-workers",
+		"metrics-bind-address",
 		"operator-webhook-service-host",
 		"operator-webhook-service-port",
 		"operator-webhook-use-service-reference",
+		"rate-limit-base-delay",
+		"rate-limit-max-delay",
+		"rate-limit-qps",
+		"rate-limit-burst",
+		"rate-limits",
+		"tracing",
+		"tracing-endpoint",
+		"watch-namespaces",
+		"watch-namespace-selector",
 	} {
 		viper.BindPFlag(name, pf.Lookup(name))
 	}
 
 	argToEnv["bosh-dns-docker-image"] = "BOSH_DNS_DOCKER_IMAGE"
 	argToEnv["cluster-domain"] = "CLUSTER_DOMAIN"
+	argToEnv["director-api-address"] = "DIRECTOR_API_ADDRESS"
+	argToEnv["director-api-username"] = "DIRECTOR_API_USERNAME"
+	argToEnv["director-api-password"] = "DIRECTOR_API_PASSWORD"
+	argToEnv["director-api-tls-cert"] = "DIRECTOR_API_TLS_CERT"
+	argToEnv["director-api-tls-key"] = "DIRECTOR_API_TLS_KEY"
+	argToEnv["health-probe-bind-address"] = "HEALTH_PROBE_BIND_ADDRESS"
+	argToEnv["leader-election"] = "LEADER_ELECTION"
+	argToEnv["leader-election-id"] = "LEADER_ELECTION_ID"
+	argToEnv["leader-election-namespace"] = "LEADER_ELECTION_NAMESPACE"
+	argToEnv["leader-election-lease-duration"] = "LEADER_ELECTION_LEASE_DURATION"
+	argToEnv["leader-election-renew-deadline"] = "LEADER_ELECTION_RENEW_DEADLINE"
+	argToEnv["leader-election-retry-period"] = "LEADER_ELECTION_RETRY_PERIOD"
 	argToEnv["max-boshdeployment-workers"] = "MAX_BOSHDEPLOYMENT_WORKERS"
 	argToEnv["max-quarks-secret-workers"] = "MAX_QUARKS_SECRET_WORKERS"
 	argToEnv["max-quarks-statefulset-workers"] = "MAX_QUARKS_STATEFULSET_WORKERS"
+	argToEnv["metrics-bind-address"] = "METRICS_BIND_ADDRESS"
 	argToEnv["operator-webhook-service-host"] = "CF_OPERATOR_WEBHOOK_SERVICE_HOST"
 	argToEnv["operator-webhook-service-port"] = "CF_OPERATOR_WEBHOOK_SERVICE_PORT"
 	argToEnv["operator-webhook-use-service-reference"] = "CF_OPERATOR_WEBHOOK_USE_SERVICE_REFERENCE"
+	argToEnv["rate-limit-base-delay"] = "RATE_LIMIT_BASE_DELAY"
+	argToEnv["rate-limit-max-delay"] = "RATE_LIMIT_MAX_DELAY"
+	argToEnv["rate-limit-qps"] = "RATE_LIMIT_QPS"
+	argToEnv["rate-limit-burst"] = "RATE_LIMIT_BURST"
+	argToEnv["rate-limits"] = "RATE_LIMITS"
+	argToEnv["tracing"] = "TRACING"
+	argToEnv["tracing-endpoint"] = "TRACING_ENDPOINT"
+	argToEnv["watch-namespaces"] = "WATCH_NAMESPACES"
+	argToEnv["watch-namespace-selector"] = "WATCH_NAMESPACE_SELECTOR"
 
 	// Add env variables to help
 	cmd.AddEnvToUsage(rootCmd, argToEnv)
@@ -5,6 +5,8 @@ import (
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 )
 
 // UtilCmd represents the util subcommand
@@ -112,3 +114,21 @@ func initialRolloutFlagCobraSet(pf *flag.FlagSet, argToEnv map[string]string) {
 func initialRolloutFlagViperBind(pf *flag.FlagSet) {
 	viper.BindPFlag("initial-rollout", pf.Lookup("initial-rollout"))
 }
+
+func propertyValidationFlagValidation() (string, error) {
+	mode := viper.GetString("property-validation")
+	switch mode {
+	case "", bdv1.PropertyValidationWarn, bdv1.PropertyValidationStrict:
+		return mode, nil
+	}
+	return "", errors.Errorf("property-validation flag must be '%s' or '%s', got '%s'", bdv1.PropertyValidationWarn, bdv1.PropertyValidationStrict, mode)
+}
+
+func propertyValidationFlagCobraSet(pf *flag.FlagSet, argToEnv map[string]string) {
+	pf.StringP("property-validation", "", "", "Validate job properties against the job specs, either 'warn' or 'strict'. Disabled if empty.")
+	argToEnv["property-validation"] = "PROPERTY_VALIDATION"
+}
+
+func propertyValidationFlagViperBind(pf *flag.FlagSet) {
+	viper.BindPFlag("property-validation", pf.Lookup("property-validation"))
+}
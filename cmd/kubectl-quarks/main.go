@@ -0,0 +1,7 @@
+package main
+
+import cmd "code.cloudfoundry.org/cf-operator/cmd/internal/kubectlquarks"
+
+func main() {
+	cmd.Execute()
+}
@@ -1,11 +1,13 @@
 package containerrun
 
 import (
+	"net"
 	"os"
 	"os/exec"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
 
 	pkg "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun"
 )
@@ -14,14 +16,24 @@ import (
 func NewContainerRunCmd(
 	run pkg.CmdRun,
 	runner pkg.Runner,
-	conditionRunner pkg.Runner,
+	newConditionRunner func(interval time.Duration) pkg.Runner,
 	commandChecker pkg.Checker,
+	rlimitSetter *pkg.RlimitSetter,
 	stdio pkg.Stdio,
 ) *cobra.Command {
 	var postStartCommandName string
 	var postStartCommandArgs []string
 	var postStartConditionCommandName string
 	var postStartConditionCommandArgs []string
+	var postStartConditionInterval time.Duration
+	var postStartOptions pkg.PostStartOptions
+	var maxRestarts int
+	var restartBackoff time.Duration
+	var restartMaxBackoff time.Duration
+	var restartCountFile string
+	var statusAddress string
+	var openFilesLimit uint64
+	var processesLimit uint64
 
 	cmd := &cobra.Command{
 		Use:           "container-run",
@@ -29,6 +41,44 @@ func NewContainerRunCmd(
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if err := postStartOptions.Validate(); err != nil {
+				return err
+			}
+
+			if openFilesLimit > 0 || processesLimit > 0 {
+				rlimits := pkg.Rlimits{
+					OpenFiles: openFilesLimit,
+					Processes: processesLimit,
+				}
+				if err := rlimitSetter.Set(rlimits); err != nil {
+					return err
+				}
+			}
+
+			var supervisor *pkg.Supervisor
+			if maxRestarts > 0 {
+				supervisor = pkg.NewSupervisor(
+					maxRestarts,
+					restartBackoff,
+					restartMaxBackoff,
+					restartCountFile,
+					time.Sleep,
+					time.Now,
+				)
+			}
+			var statusListener net.Listener
+			if statusAddress != "" {
+				statusListener, err = pkg.Listen(statusAddress)
+				if err != nil {
+					return err
+				}
+			}
+
+			var conditionRunner pkg.Runner
+			if postStartConditionCommandName != "" {
+				conditionRunner = newConditionRunner(postStartConditionInterval)
+			}
+
 			return run(
 				runner,
 				conditionRunner,
@@ -39,6 +89,9 @@ func NewContainerRunCmd(
 				postStartCommandArgs,
 				postStartConditionCommandName,
 				postStartConditionCommandArgs,
+				postStartOptions,
+				supervisor,
+				statusListener,
 			)
 		},
 	}
@@ -47,6 +100,17 @@ func NewContainerRunCmd(
 	cmd.Flags().StringArrayVar(&postStartCommandArgs, "post-start-arg", []string{}, "a post-start command arg")
 	cmd.Flags().StringVar(&postStartConditionCommandName, "post-start-condition-name", "", "the post-start condition command name")
 	cmd.Flags().StringArrayVar(&postStartConditionCommandArgs, "post-start-condition-arg", []string{}, "a post-start condition command arg")
+	cmd.Flags().DurationVar(&postStartConditionInterval, "post-start-condition-interval", pkg.DefaultConditionInterval, "the time between two runs of the post-start condition")
+	cmd.Flags().DurationVar(&postStartOptions.Timeout, "post-start-timeout", pkg.DefaultPostStartTimeout, "the time post-start, including its condition, may take")
+	cmd.Flags().StringVar(&postStartOptions.FailurePolicy, "post-start-failure-policy", pkg.PostStartFail, "what to do when post-start fails: fail the container, ignore the failure or retry post-start")
+	cmd.Flags().IntVar(&postStartOptions.Retries, "post-start-retries", 0, "how often post-start is retried with the retry failure policy")
+	cmd.Flags().IntVar(&maxRestarts, "max-restarts", 0, "restart the failed command up to this many times in a row, 0 disables supervision")
+	cmd.Flags().DurationVar(&restartBackoff, "restart-backoff", time.Second, "the delay before the first restart, doubled for every consecutive restart")
+	cmd.Flags().DurationVar(&restartMaxBackoff, "restart-max-backoff", time.Minute, "the maximum delay between restarts")
+	cmd.Flags().StringVar(&restartCountFile, "restart-count-file", "", "the file to write the restart count to")
+	cmd.Flags().StringVar(&statusAddress, "status-address", "", "serve the status endpoint on this TCP address or, when it starts with a slash, the status and control endpoint on this unix socket")
+	cmd.Flags().Uint64Var(&openFilesLimit, "open-files-limit", 0, "the maximum number of open files (RLIMIT_NOFILE) for the command")
+	cmd.Flags().Uint64Var(&processesLimit, "processes-limit", 0, "the maximum number of processes (RLIMIT_NPROC) for the command")
 
 	return cmd
 }
@@ -54,11 +118,14 @@ func NewContainerRunCmd(
 // NewDefaultContainerRunCmd constructs a new container-run command with the default dependencies.
 func NewDefaultContainerRunCmd() *cobra.Command {
 	runner := pkg.NewContainerRunner()
-	conditionRunner := pkg.NewConditionRunner(time.Sleep, exec.CommandContext)
+	newConditionRunner := func(interval time.Duration) pkg.Runner {
+		return pkg.NewConditionRunner(interval, time.Sleep, exec.CommandContext)
+	}
 	commandChecker := pkg.NewCommandChecker(os.Stat, exec.LookPath)
+	rlimitSetter := pkg.NewRlimitSetter(unix.Getrlimit, unix.Setrlimit)
 	stdio := pkg.Stdio{
 		Out: os.Stdout,
 		Err: os.Stderr,
 	}
-	return NewContainerRunCmd(pkg.Run, runner, conditionRunner, commandChecker, stdio)
+	return NewContainerRunCmd(pkg.Run, runner, newConditionRunner, commandChecker, rlimitSetter, stdio)
 }
@@ -2,6 +2,7 @@ package containerrun_test
 
 import (
 	"fmt"
+	"net"
 	"os"
 
 	. "github.com/onsi/ginkgo"
@@ -13,7 +14,7 @@ import (
 
 var _ = Describe("NewContainerRunCmd", func() {
 	It("constructs a new command", func() {
-		cmd := NewContainerRunCmd(nil, nil, nil, nil, pkg.Stdio{})
+		cmd := NewContainerRunCmd(nil, nil, nil, nil, nil, pkg.Stdio{})
 		Expect(cmd).ToNot(Equal(nil))
 	})
 
@@ -29,10 +30,13 @@ var _ = Describe("NewContainerRunCmd", func() {
 			_ []string,
 			_ string,
 			_ []string,
+			_ pkg.PostStartOptions,
+			_ *pkg.Supervisor,
+			_ net.Listener,
 		) error {
 			return expectedErr
 		}
-		cmd := NewContainerRunCmd(run, nil, nil, nil, pkg.Stdio{})
+		cmd := NewContainerRunCmd(run, nil, nil, nil, nil, pkg.Stdio{})
 		origArgs := os.Args[:]
 		os.Args = os.Args[:1]
 		err := cmd.Execute()
@@ -51,10 +55,13 @@ var _ = Describe("NewContainerRunCmd", func() {
 			_ []string,
 			_ string,
 			_ []string,
+			_ pkg.PostStartOptions,
+			_ *pkg.Supervisor,
+			_ net.Listener,
 		) error {
 			return nil
 		}
-		cmd := NewContainerRunCmd(run, nil, nil, nil, pkg.Stdio{})
+		cmd := NewContainerRunCmd(run, nil, nil, nil, nil, pkg.Stdio{})
 		origArgs := os.Args[:]
 		os.Args = os.Args[:1]
 		err := cmd.Execute()
@@ -7,7 +7,8 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -17,10 +18,20 @@ import (
 )
 
 const (
-	postStartTimeout   = time.Minute * 15
-	conditionSleepTime = time.Second * 3
+	// DefaultPostStartTimeout is the default time post-start, including its
+	// condition, may take.
+	DefaultPostStartTimeout = time.Minute * 15
+	// DefaultConditionInterval is the default time between two runs of the
+	// post-start condition.
+	DefaultConditionInterval = time.Second * 3
+	// DefaultKillTimeout is the time processes get to exit after SIGTERM,
+	// before they are killed.
+	DefaultKillTimeout = time.Second * 30
 )
 
+// terminationSignals are the signals which terminate the container.
+var terminationSignals = []os.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT}
+
 // CmdRun represents the signature for the top-level Run command.
 type CmdRun func(
 	runner Runner,
@@ -32,6 +43,9 @@ type CmdRun func(
 	postStartCommandArgs []string,
 	postStartConditionCommandName string,
 	postStartConditionCommandArgs []string,
+	postStartOptions PostStartOptions,
+	supervisor *Supervisor,
+	statusListener net.Listener,
 ) error
 
 // Run implements the logic for the container-run CLI command.
@@ -45,13 +59,16 @@ func Run(
 	postStartCommandArgs []string,
 	postStartConditionCommandName string,
 	postStartConditionCommandArgs []string,
+	postStartOptions PostStartOptions,
+	supervisor *Supervisor,
+	statusListener net.Listener,
 ) error {
 	if len(args) == 0 {
 		err := fmt.Errorf("a command is required")
 		return &runErr{err}
 	}
 
-	done := make(chan struct{}, 1)
+	exited := make(chan error, 1)
 	errors := make(chan error)
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs)
@@ -66,63 +83,127 @@ func Run(
 		return &runErr{err}
 	}
 	processRegistry.Register(process)
+	status := NewStatus()
 
 	if postStartCommandName != "" {
 		if commandChecker.Check(postStartCommandName) {
-			go func() {
-				ctx, cancel := context.WithTimeout(context.Background(), postStartTimeout)
-				defer cancel()
-
-				if postStartConditionCommandName != "" {
-					conditionCommand := Command{
-						Name: postStartConditionCommandName,
-						Arg:  postStartConditionCommandArgs,
-					}
-					conditionStdio := Stdio{
-						Out: ioutil.Discard,
-						Err: ioutil.Discard,
-					}
-					if _, err := conditionRunner.RunContext(ctx, conditionCommand, conditionStdio); err != nil {
-						errors <- err
-						return
-					}
-				}
-				postStartCommand := Command{
+			postStart := &postStart{
+				runner:          runner,
+				conditionRunner: conditionRunner,
+				stdio:           stdio,
+				processRegistry: processRegistry,
+				command: Command{
 					Name: postStartCommandName,
 					Arg:  postStartCommandArgs,
+				},
+				options: postStartOptions,
+			}
+			if postStartConditionCommandName != "" {
+				postStart.condition = &Command{
+					Name: postStartConditionCommandName,
+					Arg:  postStartConditionCommandArgs,
 				}
-				postStartProcess, err := runner.RunContext(ctx, postStartCommand, stdio)
-				if err != nil {
-					errors <- err
-					return
-				}
-				processRegistry.Register(postStartProcess)
-				if err := postStartProcess.Wait(); err != nil {
+			}
+
+			status.setPostStart(PostStartPending)
+			go func() {
+				if err := postStart.run(); err != nil {
+					if postStartOptions.FailurePolicy == PostStartIgnore {
+						status.setPostStartError(PostStartIgnored, err)
+						logf(stdio, "container-run: ignoring %v\n", err)
+						return
+					}
+					status.setPostStartError(PostStartFailed, err)
 					errors <- err
 					return
 				}
+				status.setPostStart(PostStartSucceeded)
 			}()
 		}
 	}
 
+	if supervisor != nil {
+		// Don't restart the process when the container is terminating.
+		stops := make(chan os.Signal, 1)
+		signal.Notify(stops, terminationSignals...)
+		go func() {
+			<-stops
+			supervisor.Stop()
+		}()
+	}
+
+	var controller *Controller
+	terminate := make(chan os.Signal, 1)
+	if statusListener != nil {
+		controller = NewController(runner, command, stdio, processRegistry, supervisor, status, DefaultKillTimeout)
+		signal.Notify(terminate, terminationSignals...)
+
+		// Anyone who can reach a TCP address could stop the process, so
+		// control commands are only accepted on unix sockets.
+		handler := NewStatusHandler(status, nil)
+		if IsUnixSocket(statusListener) {
+			handler = NewStatusHandler(status, controller)
+		}
+		server := &http.Server{Handler: handler}
+		go func() {
+			if err := server.Serve(statusListener); err != nil && err != http.ErrServerClosed {
+				errors <- fmt.Errorf("failed to serve status endpoint: %v", err)
+			}
+		}()
+		defer server.Close()
+	}
+
 	go func() {
-		if err := process.Wait(); err != nil {
-			errors <- err
-			return
+		var err error
+		switch {
+		case controller != nil:
+			err = controller.Run(process, terminate)
+		case supervisor != nil:
+			err = supervisor.Supervise(runner, command, stdio, process, processRegistry)
+		default:
+			err = process.Wait()
 		}
-		done <- struct{}{}
+		exited <- err
 	}()
 
 	go processRegistry.HandleSignals(sigs, errors)
 
 	select {
-	case <-done:
+	case err := <-exited:
+		if err != nil {
+			return &runErr{err}
+		}
 		return nil
 	case err := <-errors:
+		// Don't leave the main process behind, it's neither restarted nor
+		// started again.
+		if supervisor != nil {
+			supervisor.Stop()
+		}
+		select {
+		case terminate <- syscall.SIGTERM:
+		default:
+		}
+		stopProcesses(processRegistry, exited, DefaultKillTimeout, stdio)
 		return &runErr{err}
 	}
 }
 
+// stopProcesses sends SIGTERM to the registered processes and kills them, if
+// the main process didn't exit within the timeout.
+func stopProcesses(processRegistry *ProcessRegistry, exited <-chan error, timeout time.Duration, stdio Stdio) {
+	processRegistry.SignalAll(syscall.SIGTERM)
+	select {
+	case <-exited:
+		return
+	case <-time.After(timeout):
+	}
+
+	logf(stdio, "container-run: processes didn't exit within %s, killing them\n", timeout)
+	processRegistry.SignalAll(syscall.SIGKILL)
+	<-exited
+}
+
 type runErr struct {
 	err error
 }
@@ -186,16 +267,19 @@ func (cr *ContainerRunner) run(
 // ConditionRunner satisfies the Runner interface. It represents a runner for a post-start
 // pre-condition.
 type ConditionRunner struct {
+	interval           time.Duration
 	sleep              func(time.Duration)
 	execCommandContext func(context.Context, string, ...string) *exec.Cmd
 }
 
 // NewConditionRunner constructs a new ConditionRunner.
 func NewConditionRunner(
+	interval time.Duration,
 	sleep func(time.Duration),
 	execCommandContext func(context.Context, string, ...string) *exec.Cmd,
 ) *ConditionRunner {
 	return &ConditionRunner{
+		interval:           interval,
 		sleep:              sleep,
 		execCommandContext: execCommandContext,
 	}
@@ -217,7 +301,7 @@ func (cr *ConditionRunner) RunContext(
 	_ Stdio,
 ) (Process, error) {
 	for {
-		cr.sleep(conditionSleepTime)
+		cr.sleep(cr.interval)
 		cmd := cr.execCommandContext(ctx, command.Name, command.Arg...)
 		if err := cmd.Run(); err != nil {
 			if err := ctx.Err(); err == context.DeadlineExceeded {
@@ -306,6 +390,19 @@ func (pr *ProcessRegistry) Register(p Process) int {
 	return len(pr.processes)
 }
 
+// Unregister removes a process from the registry and returns how many processes are registered.
+func (pr *ProcessRegistry) Unregister(p Process) int {
+	pr.Lock()
+	defer pr.Unlock()
+	for i, registered := range pr.processes {
+		if registered == p {
+			pr.processes = append(pr.processes[:i], pr.processes[i+1:]...)
+			break
+		}
+	}
+	return len(pr.processes)
+}
+
 // SignalAll sends a signal to all registered processes.
 func (pr *ProcessRegistry) SignalAll(sig os.Signal) []error {
 	pr.Lock()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/exec"
 	"sync"
@@ -49,7 +50,7 @@ var _ = Describe("Run", func() {
 	})
 
 	It("fails when args is empty", func() {
-		err := Run(nil, nil, nil, stdio, []string{}, "", []string{}, "", []string{})
+		err := Run(nil, nil, nil, stdio, []string{}, "", []string{}, "", []string{}, PostStartOptions{}, nil, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal("failed to run container: a command is required"))
 	})
@@ -60,7 +61,7 @@ var _ = Describe("Run", func() {
 			Run(command, stdio).
 			Return(nil, fmt.Errorf(`¯\_(ツ)_/¯`)).
 			Times(1)
-		err := Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{})
+		err := Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{}, PostStartOptions{}, nil, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal(`failed to run container: ¯\_(ツ)_/¯`))
 	})
@@ -80,7 +81,7 @@ var _ = Describe("Run", func() {
 			Run(command, stdio).
 			Return(process, nil).
 			Times(1)
-		err := Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{})
+		err := Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{}, PostStartOptions{}, nil, nil)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal(`failed to run container: ¯\_(ツ)_/¯`))
 	})
@@ -100,7 +101,7 @@ var _ = Describe("Run", func() {
 			Run(command, stdio).
 			Return(process, nil).
 			Times(1)
-		err := Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{})
+		err := Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{}, PostStartOptions{}, nil, nil)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -124,7 +125,7 @@ var _ = Describe("Run", func() {
 			Check(postStart.Name).
 			Return(false).
 			Times(1)
-		err := Run(runner, nil, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{})
+		err := Run(runner, nil, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{}, PostStartOptions{}, nil, nil)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -159,9 +160,9 @@ var _ = Describe("Run", func() {
 				Return(true).
 				Times(1)
 			conditionRunner := NewMockRunner(ctrl)
-			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{})
+			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{}, PostStartOptions{}, nil, nil)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal(fmt.Errorf("failed to run container: %v", expectedErr).Error()))
+			Expect(err.Error()).To(Equal(fmt.Errorf("failed to run container: post-start %s failed: %v", "command", expectedErr).Error()))
 		})
 
 		It("fails when post-start Wait fails", func() {
@@ -203,9 +204,9 @@ var _ = Describe("Run", func() {
 				Return(true).
 				Times(1)
 			conditionRunner := NewMockRunner(ctrl)
-			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{})
+			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{}, PostStartOptions{}, nil, nil)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal(fmt.Errorf("failed to run container: %v", expectedErr).Error()))
+			Expect(err.Error()).To(Equal(fmt.Errorf("failed to run container: post-start %s failed: %v", "command", expectedErr).Error()))
 		})
 
 		It("succeeds when main and post-start commands succeed", func() {
@@ -252,11 +253,55 @@ var _ = Describe("Run", func() {
 				Return(true).
 				Times(1)
 			conditionRunner := NewMockRunner(ctrl)
-			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{})
+			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, "", []string{}, PostStartOptions{}, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
 
+	Context("With status endpoint", func() {
+		It("serves the status of the processes", func() {
+			exit := make(chan struct{})
+			process := NewMockProcess(ctrl)
+			process.EXPECT().
+				Wait().
+				Do(func() { <-exit }).
+				Return(nil).
+				Times(1)
+			process.EXPECT().
+				Signal(gomock.Any()).
+				Return(nil).
+				AnyTimes()
+			runner := NewMockRunner(ctrl)
+			runner.EXPECT().
+				Run(command, stdio).
+				Return(process, nil).
+				Times(1)
+			listener, err := Listen("127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+
+			runErr := make(chan error, 1)
+			go func() {
+				runErr <- Run(runner, nil, nil, stdio, commandLine, "", []string{}, "", []string{}, PostStartOptions{}, nil, listener)
+			}()
+
+			resp, err := http.Get(fmt.Sprintf("http://%s/status", listener.Addr()))
+			Expect(err).ToNot(HaveOccurred())
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(MatchJSON(`{"process":"running","post_start":"none","restarts":0}`))
+
+			// Control commands are only served on unix sockets.
+			resp, err = http.Post(fmt.Sprintf("http://%s/stop", listener.Addr()), "", nil)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+			close(exit)
+			Eventually(runErr).Should(Receive(BeNil()))
+		})
+	})
+
 	Context("With post-start condition", func() {
 		It("fails when the condition fails", func() {
 			expectedErr := fmt.Errorf(`¯\_(ツ)_/¯`)
@@ -286,9 +331,9 @@ var _ = Describe("Run", func() {
 				RunContext(gomock.Any(), postStartCondition, gomock.Any()).
 				Return(nil, expectedErr).
 				Times(1)
-			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, postStartCondition.Name, postStartCondition.Arg)
+			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, postStartCondition.Name, postStartCondition.Arg, PostStartOptions{}, nil, nil)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal(fmt.Errorf("failed to run container: %v", expectedErr).Error()))
+			Expect(err.Error()).To(Equal(fmt.Errorf("failed to run container: post-start %s failed: %v", "condition", expectedErr).Error()))
 		})
 
 		It("succeeds when main and post-start commands succeed", func() {
@@ -343,7 +388,7 @@ var _ = Describe("Run", func() {
 				}).
 				Return(nil, nil).
 				Times(1)
-			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, postStartCondition.Name, postStartCondition.Arg)
+			err := Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, postStartCondition.Name, postStartCondition.Arg, PostStartOptions{}, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -366,6 +411,17 @@ var _ = Describe("ProcessRegistry", func() {
 		})
 	})
 
+	Context("Unregister", func() {
+		It("unregisters only the given process", func() {
+			pr := NewProcessRegistry()
+			p := &ContainerProcess{}
+			pr.Register(&ContainerProcess{})
+			pr.Register(p)
+			Expect(pr.Unregister(p)).To(Equal(1))
+			Expect(pr.Unregister(p)).To(Equal(1))
+		})
+	})
+
 	Context("SignalAll", func() {
 		var ctrl *gomock.Controller
 
@@ -766,14 +822,14 @@ var _ = Describe("ContainerRunner", func() {
 var _ = Describe("ConditionRunner", func() {
 	Context("NewConditionRunner", func() {
 		It("constructs a new ConditionRunner", func() {
-			cr := NewConditionRunner(nil, nil)
+			cr := NewConditionRunner(DefaultConditionInterval, nil, nil)
 			Expect(cr).ToNot(BeNil())
 		})
 	})
 
 	Context("Run", func() {
 		It("is not implemented", func() {
-			cr := NewConditionRunner(nil, nil)
+			cr := NewConditionRunner(DefaultConditionInterval, nil, nil)
 			Expect(func() {
 				cr.Run(Command{}, Stdio{})
 			}).To(Panic())
@@ -814,7 +870,7 @@ var _ = Describe("ConditionRunner", func() {
 				Return(failCmd).
 				Times(2)
 
-			cr := NewConditionRunner(func(time.Duration) {}, cc.CommandContext)
+			cr := NewConditionRunner(DefaultConditionInterval, func(time.Duration) {}, cc.CommandContext)
 			p, err := cr.RunContext(ctx, cmd, Stdio{})
 			Expect(err).To(Equal(context.DeadlineExceeded))
 			Expect(p).To(BeNil())
@@ -830,10 +886,26 @@ var _ = Describe("ConditionRunner", func() {
 				Return(succeedCmd).
 				Times(1)
 
-			cr := NewConditionRunner(func(time.Duration) {}, cc.CommandContext)
+			cr := NewConditionRunner(DefaultConditionInterval, func(time.Duration) {}, cc.CommandContext)
 			p, err := cr.RunContext(ctx, cmd, Stdio{})
 			Expect(err).To(BeNil())
 			Expect(p).To(BeNil())
 		})
+
+		It("sleeps for the interval before running the condition", func() {
+			ctx := context.Background()
+			cmd := Command{Name: "echo"}
+			cc := NewMockExecCommandContext(ctrl)
+			cc.EXPECT().
+				CommandContext(ctx, cmd.Name).
+				Return(exec.CommandContext(ctx, cmd.Name)).
+				Times(1)
+
+			slept := []time.Duration{}
+			cr := NewConditionRunner(time.Millisecond, func(d time.Duration) { slept = append(slept, d) }, cc.CommandContext)
+			_, err := cr.RunContext(ctx, cmd, Stdio{})
+			Expect(err).To(BeNil())
+			Expect(slept).To(Equal([]time.Duration{time.Millisecond}))
+		})
 	})
 })
@@ -0,0 +1,176 @@
+package containerrun
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// The commands accepted by the Controller, like `monit stop`, `monit start`
+// and `monit restart`.
+const (
+	ControlStop    = "stop"
+	ControlStart   = "start"
+	ControlRestart = "restart"
+)
+
+// Controller runs the main process and handles the stop, start and restart
+// commands of the status endpoint. Failed processes are restarted by the
+// supervisor, if there is one. Processes, which don't exit within the kill
+// timeout after SIGTERM, are killed.
+type Controller struct {
+	runner          Runner
+	command         Command
+	stdio           Stdio
+	processRegistry *ProcessRegistry
+	supervisor      *Supervisor
+	status          *Status
+	killTimeout     time.Duration
+	commands        chan string
+}
+
+// NewController constructs a new Controller. The supervisor is optional.
+func NewController(
+	runner Runner,
+	command Command,
+	stdio Stdio,
+	processRegistry *ProcessRegistry,
+	supervisor *Supervisor,
+	status *Status,
+	killTimeout time.Duration,
+) *Controller {
+	return &Controller{
+		runner:          runner,
+		command:         command,
+		stdio:           stdio,
+		processRegistry: processRegistry,
+		supervisor:      supervisor,
+		status:          status,
+		killTimeout:     killTimeout,
+		commands:        make(chan string, 1),
+	}
+}
+
+// Command queues a stop, start or restart command for the main process.
+func (c *Controller) Command(command string) error {
+	switch command {
+	case ControlStop, ControlStart, ControlRestart:
+	default:
+		return fmt.Errorf("unknown command '%s'", command)
+	}
+
+	select {
+	case c.commands <- command:
+		return nil
+	default:
+		return fmt.Errorf("another command is pending")
+	}
+}
+
+// Run waits for the process and handles the commands. It returns when the
+// process succeeds, fails for good, or when the container is terminated while
+// the process is stopped.
+func (c *Controller) Run(process Process, terminate <-chan os.Signal) error {
+	now := time.Now
+	if c.supervisor != nil {
+		now = c.supervisor.now
+	}
+
+	started := now()
+	for {
+		c.status.setProcess(ProcessRunning)
+		exited := make(chan error, 1)
+		go func(process Process) {
+			exited <- process.Wait()
+		}(process)
+
+		requested, err := c.wait(process, exited)
+		c.processRegistry.Unregister(process)
+
+		restartedAfterFailure := false
+		switch {
+		case requested == ControlStop:
+			c.status.setProcess(ProcessStopped)
+			if !c.waitForStart(terminate) {
+				return nil
+			}
+		case requested == ControlRestart:
+		case err == nil:
+			c.status.setProcess(ProcessSucceeded)
+			return nil
+		case c.supervisor != nil:
+			backoff, failErr := c.supervisor.nextRestart(err, started, c.stdio)
+			if failErr != nil {
+				c.status.setProcess(ProcessFailed)
+				return failErr
+			}
+			c.status.setProcess(ProcessRestarting)
+			c.supervisor.sleep(backoff)
+			if c.supervisor.Stopped() {
+				c.status.setProcess(ProcessFailed)
+				return err
+			}
+			restartedAfterFailure = true
+		default:
+			c.status.setProcess(ProcessFailed)
+			return err
+		}
+
+		process, err = c.runner.Run(c.command, c.stdio)
+		if err != nil {
+			c.status.setProcess(ProcessFailed)
+			return err
+		}
+		c.processRegistry.Register(process)
+		started = now()
+		if restartedAfterFailure {
+			c.supervisor.restarted(c.stdio)
+			c.status.setRestarts(c.supervisor.RestartCount())
+		}
+	}
+}
+
+// wait waits for the process to exit. Stop and restart commands terminate the
+// process, they are returned together with the process' exit error.
+func (c *Controller) wait(process Process, exited <-chan error) (string, error) {
+	requested := ""
+	var kill <-chan time.Time
+	for {
+		select {
+		case err := <-exited:
+			return requested, err
+		case <-kill:
+			kill = nil
+			logf(c.stdio, "container-run: process didn't exit within %s, killing it\n", c.killTimeout)
+			if err := process.Signal(syscall.SIGKILL); err != nil {
+				logf(c.stdio, "container-run: %v\n", err)
+			}
+		case command := <-c.commands:
+			if command == ControlStart || requested != "" {
+				continue
+			}
+			requested = command
+			c.status.setProcess(ProcessStopping)
+			if err := process.Signal(syscall.SIGTERM); err != nil {
+				logf(c.stdio, "container-run: %v\n", err)
+			}
+			kill = time.After(c.killTimeout)
+		}
+	}
+}
+
+// waitForStart waits for a start or restart command. It returns false if the
+// container is terminated first.
+func (c *Controller) waitForStart(terminate <-chan os.Signal) bool {
+	for {
+		select {
+		case <-terminate:
+			return false
+		case command := <-c.commands:
+			if command != ControlStop {
+				return true
+			}
+		}
+	}
+}
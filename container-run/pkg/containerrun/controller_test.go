@@ -0,0 +1,209 @@
+package containerrun_test
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun"
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun/mocks"
+)
+
+var _ = Describe("Controller", func() {
+	command := Command{
+		Name: "bash",
+		Arg:  []string{"-c", "sleep 3600"},
+	}
+
+	var (
+		ctrl      *gomock.Controller
+		stdio     Stdio
+		registry  *ProcessRegistry
+		status    *Status
+		terminate chan os.Signal
+		runErr    chan error
+	)
+
+	// runningProcess returns a process which runs until it receives SIGTERM.
+	runningProcess := func() *MockProcess {
+		stopped := make(chan struct{})
+		process := NewMockProcess(ctrl)
+		process.EXPECT().
+			Signal(syscall.SIGTERM).
+			Do(func(os.Signal) { close(stopped) }).
+			Return(nil).
+			Times(1)
+		process.EXPECT().
+			Wait().
+			DoAndReturn(func() error {
+				<-stopped
+				return fmt.Errorf("signal: terminated")
+			}).
+			Times(1)
+		return process
+	}
+
+	exitingProcess := func(err error) *MockProcess {
+		process := NewMockProcess(ctrl)
+		process.EXPECT().
+			Wait().
+			Return(err).
+			Times(1)
+		return process
+	}
+
+	run := func(c *Controller, process Process) {
+		go func() {
+			runErr <- c.Run(process, terminate)
+		}()
+	}
+
+	processState := func() string {
+		return status.Report().Process
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		stdio = Stdio{}
+		registry = NewProcessRegistry()
+		status = NewStatus()
+		terminate = make(chan os.Signal, 1)
+		runErr = make(chan error, 1)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("Command", func() {
+		It("fails for unknown commands", func() {
+			c := NewController(nil, command, stdio, registry, nil, status, time.Minute)
+			err := c.Command("unmonitor")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("unknown command 'unmonitor'"))
+		})
+
+		It("fails when another command is pending", func() {
+			c := NewController(nil, command, stdio, registry, nil, status, time.Minute)
+			Expect(c.Command(ControlStop)).To(Succeed())
+			err := c.Command(ControlStart)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("another command is pending"))
+		})
+	})
+
+	Context("Run", func() {
+		It("succeeds when the process succeeds", func() {
+			c := NewController(nil, command, stdio, registry, nil, status, time.Minute)
+			Expect(c.Run(exitingProcess(nil), terminate)).To(Succeed())
+			Expect(processState()).To(Equal(ProcessSucceeded))
+		})
+
+		It("fails when the process fails without supervisor", func() {
+			c := NewController(nil, command, stdio, registry, nil, status, time.Minute)
+			err := c.Run(exitingProcess(fmt.Errorf(`¯\_(ツ)_/¯`)), terminate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`¯\_(ツ)_/¯`))
+			Expect(processState()).To(Equal(ProcessFailed))
+		})
+
+		It("stops and starts the process", func() {
+			runner := NewMockRunner(ctrl)
+			runner.EXPECT().
+				Run(command, stdio).
+				Return(exitingProcess(nil), nil).
+				Times(1)
+			c := NewController(runner, command, stdio, registry, nil, status, time.Minute)
+			run(c, runningProcess())
+
+			Expect(c.Command(ControlStop)).To(Succeed())
+			Eventually(processState).Should(Equal(ProcessStopped))
+			Consistently(runErr).ShouldNot(Receive())
+
+			Expect(c.Command(ControlStart)).To(Succeed())
+			Eventually(runErr).Should(Receive(BeNil()))
+			Expect(processState()).To(Equal(ProcessSucceeded))
+		})
+
+		It("restarts the process", func() {
+			runner := NewMockRunner(ctrl)
+			runner.EXPECT().
+				Run(command, stdio).
+				Return(exitingProcess(nil), nil).
+				Times(1)
+			c := NewController(runner, command, stdio, registry, nil, status, time.Minute)
+			run(c, runningProcess())
+
+			Expect(c.Command(ControlRestart)).To(Succeed())
+			Eventually(runErr).Should(Receive(BeNil()))
+			Expect(status.Report().Restarts).To(Equal(0))
+		})
+
+		It("kills the process when it doesn't exit after SIGTERM", func() {
+			killed := make(chan struct{})
+			process := NewMockProcess(ctrl)
+			process.EXPECT().
+				Signal(syscall.SIGTERM).
+				Return(nil).
+				Times(1)
+			process.EXPECT().
+				Signal(syscall.SIGKILL).
+				Do(func(os.Signal) { close(killed) }).
+				Return(nil).
+				Times(1)
+			process.EXPECT().
+				Wait().
+				DoAndReturn(func() error {
+					<-killed
+					return fmt.Errorf("signal: killed")
+				}).
+				Times(1)
+			c := NewController(nil, command, stdio, registry, nil, status, 10*time.Millisecond)
+			run(c, process)
+
+			Expect(c.Command(ControlStop)).To(Succeed())
+			Eventually(processState).Should(Equal(ProcessStopped))
+
+			terminate <- syscall.SIGTERM
+			Eventually(runErr).Should(Receive(BeNil()))
+		})
+
+		It("returns when the container is terminated while the process is stopped", func() {
+			c := NewController(nil, command, stdio, registry, nil, status, time.Minute)
+			run(c, runningProcess())
+
+			Expect(c.Command(ControlStop)).To(Succeed())
+			Eventually(processState).Should(Equal(ProcessStopped))
+
+			terminate <- syscall.SIGTERM
+			Eventually(runErr).Should(Receive(BeNil()))
+		})
+
+		It("restarts the failed process with the supervisor", func() {
+			runner := NewMockRunner(ctrl)
+			runner.EXPECT().
+				Run(command, stdio).
+				Return(exitingProcess(nil), nil).
+				Times(1)
+			supervisor := NewSupervisor(1, time.Second, time.Minute, "", func(time.Duration) {}, time.Now)
+			c := NewController(runner, command, stdio, registry, supervisor, status, time.Minute)
+			Expect(c.Run(exitingProcess(fmt.Errorf("exit status 1")), terminate)).To(Succeed())
+			Expect(status.Report().Restarts).To(Equal(1))
+		})
+
+		It("fails when the supervisor gives up", func() {
+			supervisor := NewSupervisor(0, time.Second, time.Minute, "", func(time.Duration) {}, time.Now)
+			c := NewController(nil, command, stdio, registry, supervisor, status, time.Minute)
+			err := c.Run(exitingProcess(fmt.Errorf("exit status 1")), terminate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("giving up after 0 restarts: exit status 1"))
+			Expect(processState()).To(Equal(ProcessFailed))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package containerrun
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// The policies for a failed post-start.
+const (
+	// PostStartFail fails the container. It's the default.
+	PostStartFail = "fail"
+	// PostStartIgnore keeps the container running.
+	PostStartIgnore = "ignore"
+	// PostStartRetry runs post-start again, before failing the container.
+	PostStartRetry = "retry"
+)
+
+// PostStartOptions configures how post-start is run. The zero value runs
+// post-start once with the default timeout and fails the container when it
+// fails.
+type PostStartOptions struct {
+	Timeout       time.Duration
+	FailurePolicy string
+	Retries       int
+}
+
+// Validate checks the failure policy.
+func (o PostStartOptions) Validate() error {
+	switch o.FailurePolicy {
+	case "", PostStartFail, PostStartIgnore, PostStartRetry:
+	default:
+		return fmt.Errorf("invalid post-start failure policy '%s'", o.FailurePolicy)
+	}
+	if o.Retries < 0 {
+		return fmt.Errorf("invalid post-start retries %d", o.Retries)
+	}
+	return nil
+}
+
+// The phases of post-start.
+const (
+	postStartPhaseCondition = "condition"
+	postStartPhaseCommand   = "command"
+)
+
+// PostStartError reports the phase in which post-start failed.
+type PostStartError struct {
+	Phase string
+	Err   error
+}
+
+func (e *PostStartError) Error() string {
+	return fmt.Sprintf("post-start %s failed: %v", e.Phase, e.Err)
+}
+
+// postStart runs the post-start command, once its condition succeeds.
+type postStart struct {
+	runner          Runner
+	conditionRunner Runner
+	stdio           Stdio
+	processRegistry *ProcessRegistry
+	command         Command
+	condition       *Command
+	options         PostStartOptions
+}
+
+// run runs post-start and retries it, if the failure policy says so.
+func (ps *postStart) run() error {
+	attempts := 1
+	if ps.options.FailurePolicy == PostStartRetry {
+		attempts += ps.options.Retries
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = ps.runOnce(); err == nil {
+			return nil
+		}
+		if attempt < attempts {
+			logf(ps.stdio, "container-run: %v, retrying (%d/%d)\n", err, attempt, ps.options.Retries)
+		}
+	}
+	return err
+}
+
+func (ps *postStart) runOnce() error {
+	timeout := ps.options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPostStartTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if ps.condition != nil {
+		conditionStdio := Stdio{
+			Out: ioutil.Discard,
+			Err: ioutil.Discard,
+		}
+		if _, err := ps.conditionRunner.RunContext(ctx, *ps.condition, conditionStdio); err != nil {
+			return ps.error(ctx, postStartPhaseCondition, timeout, err)
+		}
+	}
+
+	process, err := ps.runner.RunContext(ctx, ps.command, ps.stdio)
+	if err != nil {
+		return ps.error(ctx, postStartPhaseCommand, timeout, err)
+	}
+	ps.processRegistry.Register(process)
+	defer ps.processRegistry.Unregister(process)
+	if err := process.Wait(); err != nil {
+		return ps.error(ctx, postStartPhaseCommand, timeout, err)
+	}
+	return nil
+}
+
+// error wraps the error of a phase, replacing errors caused by the timeout.
+func (ps *postStart) error(ctx context.Context, phase string, timeout time.Duration, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	return &PostStartError{Phase: phase, Err: err}
+}
@@ -0,0 +1,173 @@
+package containerrun_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun"
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun/mocks"
+)
+
+var _ = Describe("PostStartOptions", func() {
+	It("accepts the known failure policies", func() {
+		for _, policy := range []string{"", PostStartFail, PostStartIgnore, PostStartRetry} {
+			Expect(PostStartOptions{FailurePolicy: policy}.Validate()).To(Succeed())
+		}
+	})
+
+	It("rejects unknown failure policies", func() {
+		err := PostStartOptions{FailurePolicy: "panic"}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("invalid post-start failure policy 'panic'"))
+	})
+
+	It("rejects negative retries", func() {
+		err := PostStartOptions{FailurePolicy: PostStartRetry, Retries: -1}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("invalid post-start retries -1"))
+	})
+})
+
+var _ = Describe("Run with post-start options", func() {
+	commandLine := []string{"bash", "-c", "echo foo"}
+	command := Command{
+		Name: commandLine[0],
+		Arg:  commandLine[1:],
+	}
+	postStart := Command{Name: "/var/vcap/jobs/foo/bin/post-start"}
+	postStartCondition := Command{Name: "fake_health_check"}
+	stdio := Stdio{}
+
+	var (
+		ctrl    *gomock.Controller
+		runner  *MockRunner
+		checker *MockChecker
+	)
+
+	mainProcess := func(runFor time.Duration) *MockProcess {
+		process := NewMockProcess(ctrl)
+		process.EXPECT().
+			Wait().
+			Do(func() { time.Sleep(runFor) }).
+			Return(nil).
+			Times(1)
+		process.EXPECT().
+			Signal(gomock.Any()).
+			Return(nil).
+			AnyTimes()
+		return process
+	}
+
+	run := func(conditionRunner Runner, condition Command, options PostStartOptions) error {
+		return Run(runner, conditionRunner, checker, stdio, commandLine, postStart.Name, postStart.Arg, condition.Name, condition.Arg, options, nil, nil)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		runner = NewMockRunner(ctrl)
+		checker = NewMockChecker(ctrl)
+		checker.EXPECT().
+			Check(postStart.Name).
+			Return(true).
+			Times(1)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("keeps the container running when the failure is ignored", func() {
+		runner.EXPECT().
+			Run(command, stdio).
+			Return(mainProcess(100*time.Millisecond), nil).
+			Times(1)
+		runner.EXPECT().
+			RunContext(gomock.Any(), postStart, stdio).
+			Return(nil, fmt.Errorf(`¯\_(ツ)_/¯`)).
+			Times(1)
+		err := run(nil, Command{}, PostStartOptions{FailurePolicy: PostStartIgnore})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("retries post-start and fails once the retries are used up", func() {
+		terminated := make(chan struct{})
+		process := NewMockProcess(ctrl)
+		var once sync.Once
+		process.EXPECT().
+			Signal(gomock.Any()).
+			Do(func(sig os.Signal) {
+				if sig == syscall.SIGTERM {
+					once.Do(func() { close(terminated) })
+				}
+			}).
+			Return(nil).
+			AnyTimes()
+		process.EXPECT().
+			Wait().
+			Do(func() { <-terminated }).
+			Return(fmt.Errorf("terminated")).
+			Times(1)
+		runner.EXPECT().
+			Run(command, stdio).
+			Return(process, nil).
+			Times(1)
+		runner.EXPECT().
+			RunContext(gomock.Any(), postStart, stdio).
+			Return(nil, fmt.Errorf(`¯\_(ツ)_/¯`)).
+			Times(3)
+		err := run(nil, Command{}, PostStartOptions{FailurePolicy: PostStartRetry, Retries: 2})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`failed to run container: post-start command failed: ¯\_(ツ)_/¯`))
+	})
+
+	It("succeeds when a retry of post-start succeeds", func() {
+		postStartProcess := NewMockProcess(ctrl)
+		postStartProcess.EXPECT().
+			Wait().
+			Return(nil).
+			Times(1)
+		runner.EXPECT().
+			Run(command, stdio).
+			Return(mainProcess(100*time.Millisecond), nil).
+			Times(1)
+		gomock.InOrder(
+			runner.EXPECT().
+				RunContext(gomock.Any(), postStart, stdio).
+				Return(nil, fmt.Errorf(`¯\_(ツ)_/¯`)).
+				Times(1),
+			runner.EXPECT().
+				RunContext(gomock.Any(), postStart, stdio).
+				Return(postStartProcess, nil).
+				Times(1),
+		)
+		err := run(nil, Command{}, PostStartOptions{FailurePolicy: PostStartRetry, Retries: 2})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("reports the phase which timed out", func() {
+		runner.EXPECT().
+			Run(command, stdio).
+			Return(mainProcess(time.Second), nil).
+			Times(1)
+		conditionRunner := NewMockRunner(ctrl)
+		conditionRunner.EXPECT().
+			RunContext(gomock.Any(), postStartCondition, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ Command, _ Stdio) (Process, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}).
+			Times(1)
+		err := run(conditionRunner, postStartCondition, PostStartOptions{Timeout: 10 * time.Millisecond})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("failed to run container: post-start condition failed: timed out after 10ms"))
+	})
+})
@@ -0,0 +1,62 @@
+package containerrun
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// Rlimits represents the resource limits for the commands run by container-run.
+// A zero value leaves the limit unchanged.
+type Rlimits struct {
+	OpenFiles uint64
+	Processes uint64
+}
+
+// RlimitSetter sets the resource limits of the container-run process, which are
+// inherited by the commands it runs.
+type RlimitSetter struct {
+	getrlimit func(resource int, rlim *unix.Rlimit) error
+	setrlimit func(resource int, rlim *unix.Rlimit) error
+}
+
+// NewRlimitSetter constructs a new RlimitSetter.
+func NewRlimitSetter(
+	getrlimit func(resource int, rlim *unix.Rlimit) error,
+	setrlimit func(resource int, rlim *unix.Rlimit) error,
+) *RlimitSetter {
+	return &RlimitSetter{
+		getrlimit: getrlimit,
+		setrlimit: setrlimit,
+	}
+}
+
+// Set sets both the soft and the hard limits, like BPM does.
+func (rs *RlimitSetter) Set(rlimits Rlimits) error {
+	if err := rs.set("open files", unix.RLIMIT_NOFILE, rlimits.OpenFiles); err != nil {
+		return err
+	}
+	return rs.set("processes", unix.RLIMIT_NPROC, rlimits.Processes)
+}
+
+func (rs *RlimitSetter) set(name string, resource int, limit uint64) error {
+	if limit == 0 {
+		return nil
+	}
+
+	current := unix.Rlimit{}
+	if err := rs.getrlimit(resource, &current); err != nil {
+		return fmt.Errorf("failed to get %s limit: %v", name, err)
+	}
+
+	if err := rs.setrlimit(resource, &unix.Rlimit{Cur: limit, Max: limit}); err != nil {
+		if err == unix.EPERM && limit > current.Max {
+			return fmt.Errorf(
+				"failed to set %s limit to %d: raising it above the hard limit of %d requires the SYS_RESOURCE capability",
+				name, limit, current.Max,
+			)
+		}
+		return fmt.Errorf("failed to set %s limit to %d: %v", name, limit, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package containerrun_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun"
+)
+
+var _ = Describe("RlimitSetter", func() {
+	var (
+		set       map[int]unix.Rlimit
+		getrlimit func(int, *unix.Rlimit) error
+		setrlimit func(int, *unix.Rlimit) error
+	)
+
+	BeforeEach(func() {
+		set = map[int]unix.Rlimit{}
+		getrlimit = func(resource int, rlim *unix.Rlimit) error {
+			rlim.Cur = 1024
+			rlim.Max = 4096
+			return nil
+		}
+		setrlimit = func(resource int, rlim *unix.Rlimit) error {
+			set[resource] = *rlim
+			return nil
+		}
+	})
+
+	It("doesn't change unset limits", func() {
+		rs := NewRlimitSetter(getrlimit, setrlimit)
+		Expect(rs.Set(Rlimits{})).To(Succeed())
+		Expect(set).To(BeEmpty())
+	})
+
+	It("sets the soft and hard limits", func() {
+		rs := NewRlimitSetter(getrlimit, setrlimit)
+		Expect(rs.Set(Rlimits{OpenFiles: 2048, Processes: 100})).To(Succeed())
+		Expect(set).To(Equal(map[int]unix.Rlimit{
+			unix.RLIMIT_NOFILE: {Cur: 2048, Max: 2048},
+			unix.RLIMIT_NPROC:  {Cur: 100, Max: 100},
+		}))
+	})
+
+	It("fails when the current limit can't be read", func() {
+		getrlimit = func(int, *unix.Rlimit) error {
+			return fmt.Errorf(`¯\_(ツ)_/¯`)
+		}
+		rs := NewRlimitSetter(getrlimit, setrlimit)
+		err := rs.Set(Rlimits{OpenFiles: 2048})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`failed to get open files limit: ¯\_(ツ)_/¯`))
+	})
+
+	It("explains the missing privilege when raising the hard limit is not permitted", func() {
+		setrlimit = func(int, *unix.Rlimit) error {
+			return unix.EPERM
+		}
+		rs := NewRlimitSetter(getrlimit, setrlimit)
+		err := rs.Set(Rlimits{Processes: 8192})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("failed to set processes limit to 8192: raising it above the hard limit of 4096 requires the SYS_RESOURCE capability"))
+	})
+
+	It("fails when setting the limit fails", func() {
+		setrlimit = func(int, *unix.Rlimit) error {
+			return unix.EINVAL
+		}
+		rs := NewRlimitSetter(getrlimit, setrlimit)
+		err := rs.Set(Rlimits{OpenFiles: 2048})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("failed to set open files limit to 2048: invalid argument"))
+	})
+})
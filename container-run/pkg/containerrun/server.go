@@ -0,0 +1,88 @@
+package containerrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Listen listens on the address of the status endpoint. Addresses starting
+// with a slash are paths of unix sockets, all others are TCP addresses.
+func Listen(address string) (net.Listener, error) {
+	network := "tcp"
+	if strings.HasPrefix(address, "/") {
+		network = "unix"
+		// Remove the socket of a previous run of the container.
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale status socket: %v", err)
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on status address: %v", err)
+	}
+	return listener, nil
+}
+
+// IsUnixSocket returns true, if the listener listens on a unix socket.
+func IsUnixSocket(listener net.Listener) bool {
+	return listener.Addr().Network() == "unix"
+}
+
+// NewStatusHandler returns the handler of the status endpoint. GET /status
+// returns the StatusReport as JSON, GET /ready succeeds if the process is
+// running and post-start succeeded. POST /stop, /start and /restart control
+// the main process, like monit does. They are only served with a controller.
+// The endpoint has no authentication, access to the control commands is
+// limited by serving them on unix sockets only.
+func NewStatusHandler(status *Status, controller *Controller) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(status.Report()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !status.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ready")
+	})
+
+	if controller == nil {
+		return mux
+	}
+
+	for _, command := range []string{ControlStop, ControlStart, ControlRestart} {
+		command := command
+		mux.HandleFunc("/"+command, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if err := controller.Command(command); err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		})
+	}
+
+	return mux
+}
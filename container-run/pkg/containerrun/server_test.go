@@ -0,0 +1,118 @@
+package containerrun_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun"
+)
+
+var _ = Describe("Listen", func() {
+	It("listens on a TCP address", func() {
+		listener, err := Listen("127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		defer listener.Close()
+		Expect(listener.Addr().Network()).To(Equal("tcp"))
+	})
+
+	It("listens on a unix socket and removes a stale one", func() {
+		dir, err := ioutil.TempDir("", "container-run")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		socket := filepath.Join(dir, "container-run.sock")
+		Expect(ioutil.WriteFile(socket, []byte{}, 0644)).To(Succeed())
+
+		listener, err := Listen(socket)
+		Expect(err).ToNot(HaveOccurred())
+		defer listener.Close()
+		Expect(listener.Addr().Network()).To(Equal("unix"))
+	})
+
+	It("fails on an invalid address", func() {
+		_, err := Listen("invalid")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to listen on status address"))
+	})
+})
+
+var _ = Describe("NewStatusHandler", func() {
+	var (
+		server     *httptest.Server
+		controller *Controller
+	)
+
+	BeforeEach(func() {
+		status := NewStatus()
+		controller = NewController(nil, Command{}, Stdio{}, NewProcessRegistry(), nil, status, time.Minute)
+		server = httptest.NewServer(NewStatusHandler(status, controller))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("reports the status", func() {
+		resp, err := http.Get(server.URL + "/status")
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		report := StatusReport{}
+		Expect(json.NewDecoder(resp.Body).Decode(&report)).To(Succeed())
+		Expect(report).To(Equal(StatusReport{
+			Process:   ProcessRunning,
+			PostStart: PostStartNone,
+			Restarts:  0,
+		}))
+	})
+
+	It("reports readiness", func() {
+		resp, err := http.Get(server.URL + "/ready")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("accepts control commands", func() {
+		resp, err := http.Post(server.URL+"/stop", "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+		resp, err = http.Post(server.URL+"/start", "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+	})
+
+	It("doesn't serve control commands without controller", func() {
+		noControl := httptest.NewServer(NewStatusHandler(NewStatus(), nil))
+		defer noControl.Close()
+
+		resp, err := http.Post(noControl.URL+"/stop", "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("rejects wrong methods", func() {
+		resp, err := http.Get(server.URL + "/restart")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+
+		resp, err = http.Post(server.URL+"/status", "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
@@ -0,0 +1,93 @@
+package containerrun
+
+import (
+	"sync"
+)
+
+// The states of the main process.
+const (
+	ProcessRunning    = "running"
+	ProcessStopping   = "stopping"
+	ProcessStopped    = "stopped"
+	ProcessRestarting = "restarting"
+	ProcessSucceeded  = "succeeded"
+	ProcessFailed     = "failed"
+)
+
+// The states of the post-start command.
+const (
+	PostStartNone      = "none"
+	PostStartPending   = "pending"
+	PostStartSucceeded = "succeeded"
+	PostStartFailed    = "failed"
+	// PostStartIgnored is a failed post-start, which was ignored because of
+	// the failure policy.
+	PostStartIgnored = "ignored"
+)
+
+// StatusReport is the state of the processes, as reported by the status endpoint.
+type StatusReport struct {
+	Process        string `json:"process"`
+	PostStart      string `json:"post_start"`
+	PostStartError string `json:"post_start_error,omitempty"`
+	Restarts       int    `json:"restarts"`
+}
+
+// Status keeps track of the state of the processes run by container-run.
+type Status struct {
+	report StatusReport
+	sync.Mutex
+}
+
+// NewStatus constructs a new Status.
+func NewStatus() *Status {
+	return &Status{
+		report: StatusReport{
+			Process:   ProcessRunning,
+			PostStart: PostStartNone,
+		},
+	}
+}
+
+// Report returns the current state.
+func (s *Status) Report() StatusReport {
+	s.Lock()
+	defer s.Unlock()
+	return s.report
+}
+
+// Ready returns true if the main process is running and the post-start
+// command, if any, succeeded or its failure was ignored.
+func (s *Status) Ready() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.report.Process == ProcessRunning &&
+		(s.report.PostStart == PostStartNone ||
+			s.report.PostStart == PostStartSucceeded ||
+			s.report.PostStart == PostStartIgnored)
+}
+
+func (s *Status) setProcess(state string) {
+	s.Lock()
+	defer s.Unlock()
+	s.report.Process = state
+}
+
+func (s *Status) setPostStart(state string) {
+	s.Lock()
+	defer s.Unlock()
+	s.report.PostStart = state
+}
+
+func (s *Status) setPostStartError(state string, err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.report.PostStart = state
+	s.report.PostStartError = err.Error()
+}
+
+func (s *Status) setRestarts(restarts int) {
+	s.Lock()
+	defer s.Unlock()
+	s.report.Restarts = restarts
+}
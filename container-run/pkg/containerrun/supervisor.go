@@ -0,0 +1,165 @@
+package containerrun
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// supervisionStableAfter is the time a restarted process has to run, before
+// its earlier failures are forgiven. It's the same period kubelet uses to reset
+// the crash loop backoff of containers.
+const supervisionStableAfter = time.Minute * 10
+
+// Supervisor restarts the main process when it fails, like monit does for
+// BOSH jobs. Restarts are delayed by an exponential backoff. Once the limit of
+// consecutive restarts is reached, the process failure is returned and the
+// container fails.
+type Supervisor struct {
+	maxRestarts      int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	restartCountFile string
+	sleep            func(time.Duration)
+	now              func() time.Time
+
+	// failures counts the consecutive restarts, it's only used by the goroutine
+	// waiting for the process.
+	failures int
+	restarts int64
+	stopped  int32
+}
+
+// NewSupervisor constructs a new Supervisor. The restart count is written to
+// restartCountFile after each restart, unless it's empty.
+func NewSupervisor(
+	maxRestarts int,
+	initialBackoff time.Duration,
+	maxBackoff time.Duration,
+	restartCountFile string,
+	sleep func(time.Duration),
+	now func() time.Time,
+) *Supervisor {
+	return &Supervisor{
+		maxRestarts:      maxRestarts,
+		initialBackoff:   initialBackoff,
+		maxBackoff:       maxBackoff,
+		restartCountFile: restartCountFile,
+		sleep:            sleep,
+		now:              now,
+	}
+}
+
+// RestartCount returns how often the process was restarted.
+func (s *Supervisor) RestartCount() int {
+	return int(atomic.LoadInt64(&s.restarts))
+}
+
+// Stop stops restarting the process, e.g. because the container is terminating.
+func (s *Supervisor) Stop() {
+	atomic.StoreInt32(&s.stopped, 1)
+}
+
+// Stopped returns true if the supervisor doesn't restart the process anymore.
+func (s *Supervisor) Stopped() bool {
+	return atomic.LoadInt32(&s.stopped) == 1
+}
+
+// Supervise waits for the process and restarts it with the runner when it
+// fails. It returns when the process succeeds, the supervisor is stopped or the
+// restart limit is reached.
+func (s *Supervisor) Supervise(
+	runner Runner,
+	command Command,
+	stdio Stdio,
+	process Process,
+	processRegistry *ProcessRegistry,
+) error {
+	started := s.now()
+	for {
+		err := process.Wait()
+		processRegistry.Unregister(process)
+		if err == nil {
+			return nil
+		}
+
+		backoff, failErr := s.nextRestart(err, started, stdio)
+		if failErr != nil {
+			return failErr
+		}
+		s.sleep(backoff)
+		if s.Stopped() {
+			return err
+		}
+
+		process, err = runner.Run(command, stdio)
+		if err != nil {
+			return err
+		}
+		processRegistry.Register(process)
+		started = s.now()
+		s.restarted(stdio)
+	}
+}
+
+// nextRestart decides if a process, which was started at the given time and
+// failed with err, is restarted. It returns the delay before the restart, or
+// the error to fail the container with.
+func (s *Supervisor) nextRestart(err error, started time.Time, stdio Stdio) (time.Duration, error) {
+	if s.Stopped() {
+		return 0, err
+	}
+
+	if s.now().Sub(started) >= supervisionStableAfter {
+		s.failures = 0
+	}
+	if s.failures >= s.maxRestarts {
+		return 0, fmt.Errorf("giving up after %d restarts: %v", s.failures, err)
+	}
+
+	backoff := s.backoff(s.failures)
+	logf(stdio, "container-run: %v, restarting in %s (%d/%d)\n", err, backoff, s.failures+1, s.maxRestarts)
+	return backoff, nil
+}
+
+// restarted records a restart of the failed process.
+func (s *Supervisor) restarted(stdio Stdio) {
+	s.failures++
+	restarts := atomic.AddInt64(&s.restarts, 1)
+	if err := s.writeRestartCount(restarts); err != nil {
+		logf(stdio, "container-run: %v\n", err)
+	}
+}
+
+// backoff doubles the initial backoff for every consecutive failure, up to
+// the max backoff.
+func (s *Supervisor) backoff(failures int) time.Duration {
+	backoff := s.initialBackoff
+	for i := 0; i < failures && backoff < s.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > s.maxBackoff {
+		return s.maxBackoff
+	}
+	return backoff
+}
+
+func (s *Supervisor) writeRestartCount(restarts int64) error {
+	if s.restartCountFile == "" {
+		return nil
+	}
+	if err := ioutil.WriteFile(s.restartCountFile, []byte(strconv.FormatInt(restarts, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to write restart count: %v", err)
+	}
+	return nil
+}
+
+// logf writes a message to the STDERR of the stdio, if there is one.
+func logf(stdio Stdio, format string, a ...interface{}) {
+	if stdio.Err == nil {
+		return
+	}
+	fmt.Fprintf(stdio.Err, format, a...)
+}
@@ -0,0 +1,160 @@
+package containerrun_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun"
+	. "code.cloudfoundry.org/cf-operator/container-run/pkg/containerrun/mocks"
+)
+
+var _ = Describe("Supervisor", func() {
+	command := Command{
+		Name: "bash",
+		Arg:  []string{"-c", "echo foo"},
+	}
+
+	var (
+		ctrl     *gomock.Controller
+		stdio    Stdio
+		stderr   *bytes.Buffer
+		sleeps   []time.Duration
+		sleep    func(time.Duration)
+		now      time.Time
+		registry *ProcessRegistry
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		stderr = &bytes.Buffer{}
+		stdio = Stdio{Err: stderr}
+		sleeps = []time.Duration{}
+		sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+		now = time.Now()
+		registry = NewProcessRegistry()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	failingProcess := func() *MockProcess {
+		process := NewMockProcess(ctrl)
+		process.EXPECT().
+			Wait().
+			Return(fmt.Errorf("exit status 1")).
+			Times(1)
+		return process
+	}
+
+	It("succeeds without restarts when the process succeeds", func() {
+		process := NewMockProcess(ctrl)
+		process.EXPECT().
+			Wait().
+			Return(nil).
+			Times(1)
+		s := NewSupervisor(3, time.Second, time.Minute, "", sleep, time.Now)
+		err := s.Supervise(nil, command, stdio, process, registry)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.RestartCount()).To(Equal(0))
+	})
+
+	It("restarts the failed process with exponential backoff until the limit is reached", func() {
+		runner := NewMockRunner(ctrl)
+		gomock.InOrder(
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+		)
+		s := NewSupervisor(3, time.Second, 3*time.Second, "", sleep, time.Now)
+		err := s.Supervise(runner, command, stdio, failingProcess(), registry)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("giving up after 3 restarts: exit status 1"))
+		Expect(s.RestartCount()).To(Equal(3))
+		Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}))
+		Expect(stderr.String()).To(ContainSubstring("container-run: exit status 1, restarting in 1s (1/3)"))
+	})
+
+	It("succeeds when a restarted process succeeds", func() {
+		process := NewMockProcess(ctrl)
+		process.EXPECT().
+			Wait().
+			Return(nil).
+			Times(1)
+		runner := NewMockRunner(ctrl)
+		runner.EXPECT().Run(command, stdio).Return(process, nil).Times(1)
+		s := NewSupervisor(3, time.Second, time.Minute, "", sleep, time.Now)
+		err := s.Supervise(runner, command, stdio, failingProcess(), registry)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.RestartCount()).To(Equal(1))
+	})
+
+	It("resets the backoff when the process ran long enough", func() {
+		// The second process runs for an hour before it fails.
+		offsets := []time.Duration{0, time.Second, time.Second, time.Hour, time.Hour, time.Hour + time.Second}
+		clock := func() time.Time {
+			offset := offsets[0]
+			offsets = offsets[1:]
+			return now.Add(offset)
+		}
+		runner := NewMockRunner(ctrl)
+		gomock.InOrder(
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+		)
+		s := NewSupervisor(1, time.Second, time.Minute, "", sleep, clock)
+		err := s.Supervise(runner, command, stdio, failingProcess(), registry)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("giving up after 1 restarts: exit status 1"))
+		Expect(s.RestartCount()).To(Equal(2))
+		Expect(sleeps).To(Equal([]time.Duration{time.Second, time.Second}))
+	})
+
+	It("doesn't restart the process when stopped", func() {
+		s := NewSupervisor(3, time.Second, time.Minute, "", sleep, time.Now)
+		s.Stop()
+		err := s.Supervise(nil, command, stdio, failingProcess(), registry)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("exit status 1"))
+		Expect(s.Stopped()).To(BeTrue())
+		Expect(s.RestartCount()).To(Equal(0))
+	})
+
+	It("fails when the process can't be restarted", func() {
+		runner := NewMockRunner(ctrl)
+		runner.EXPECT().Run(command, stdio).Return(nil, fmt.Errorf(`¯\_(ツ)_/¯`)).Times(1)
+		s := NewSupervisor(3, time.Second, time.Minute, "", sleep, time.Now)
+		err := s.Supervise(runner, command, stdio, failingProcess(), registry)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`¯\_(ツ)_/¯`))
+	})
+
+	It("writes the restart count to a file", func() {
+		dir, err := ioutil.TempDir("", "supervisor")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "restarts")
+
+		runner := NewMockRunner(ctrl)
+		gomock.InOrder(
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+			runner.EXPECT().Run(command, stdio).Return(failingProcess(), nil).Times(1),
+		)
+		s := NewSupervisor(2, time.Second, time.Minute, file, sleep, time.Now)
+		err = s.Supervise(runner, command, stdio, failingProcess(), registry)
+		Expect(err).To(HaveOccurred())
+
+		count, err := ioutil.ReadFile(file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(count)).To(Equal("2"))
+	})
+})
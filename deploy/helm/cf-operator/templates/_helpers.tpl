@@ -48,3 +48,122 @@ Name of the role of cf-operator.
 {{- define "cf-operator.role-name" -}}
 {{- printf "%s-%s" .Chart.Name .Release.Namespace | trunc 63 | trimSuffix "-" -}}
 {{- end -}}
+
+{{/*
+Non-empty, if the operator watches several namespaces instead of global.operator.watchNamespace.
+*/}}
+{{- define "cf-operator.watch-namespaces" -}}
+{{- if or .Values.operator.watchNamespaces .Values.operator.watchNamespaceSelector -}}
+true
+{{- end -}}
+{{- end -}}
+
+{{/*
+RBAC rules of cf-operator in the watched namespaces.
+*/}}
+{{- define "cf-operator.watch-namespace-rules" -}}
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
+  - update
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - update
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - delete
+  - get
+  - list
+  - update
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - services
+  - configmaps
+  verbs:
+  - create
+  - get
+  - list
+  - update
+  - watch
+- apiGroups:
+  - ""
+  resources:
+  - pods/exec
+  verbs:
+  - create
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  verbs:
+  - get
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  - statefulsets
+  verbs:
+  - create
+  - get
+  - list
+  - update
+  - watch
+- apiGroups:
+  - apps
+  resources:
+  - replicasets
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - quarks.cloudfoundry.org
+  resources:
+  - quarksjobs
+  verbs:
+  - create
+  - get
+  - list
+  - update
+  - watch
+- apiGroups:
+  - quarks.cloudfoundry.org
+  resources:
+  - boshdeployments
+  - quarksstatefulsets
+  - quarkssecrets
+  verbs:
+  - create
+  - delete
+  - get
+  - list
+  - patch
+  - update
+  - watch
+- apiGroups:
+  - quarks.cloudfoundry.org
+  resources:
+  - boshdeployments/status
+  - quarkssecrets/status
+  - quarksstatefulsets/status
+  verbs:
+  - create
+  - patch
+  - update
+{{- end -}}
@@ -15,14 +15,25 @@ var _ = Describe("variable-interpolation", func() {
 	var (
 		manifestPath string
 		varsDir      string
+		outputPath   string
 	)
 
+	BeforeEach(func() {
+		tmpDir, err := ioutil.TempDir("", "variable-interpolation")
+		Expect(err).ToNot(HaveOccurred())
+		outputPath = filepath.Join(tmpDir, "output.json")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(filepath.Dir(outputPath))).To(Succeed())
+	})
+
 	act := func(manifestPath, varsDir string) (session *gexec.Session, err error) {
 		args := []string{
 			"util", "variable-interpolation",
 			"-m", manifestPath,
 			"-v", varsDir,
-			"--output-file-path", filepath.Join(assetPath, "output.json")}
+			"--output-file-path", outputPath}
 		cmd := exec.Command(cliPath, args...)
 		session, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 		return
@@ -42,7 +53,7 @@ var _ = Describe("variable-interpolation", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Eventually(session).Should(gexec.Exit(0))
 
-			dataBytes, err := ioutil.ReadFile(filepath.Join(assetPath, "output.json"))
+			dataBytes, err := ioutil.ReadFile(outputPath)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(string(dataBytes)).To(Equal(`{"manifest.yaml":"director_uuid: |\n  fake-password\ninstance_groups:\n- azs: null\n  env:\n    bosh:\n      agent:\n        settings: {}\n      ipv6:\n        enable: false\n  instances: 0\n  jobs: null\n  name: |\n    baz\n  properties:\n    quarks: {}\n  stemcell: \"\"\n  vm_resources: null\n- azs: null\n  env:\n    bosh:\n      agent:\n        settings: {}\n      ipv6:\n        enable: false\n  instances: 0\n  jobs: null\n  name: |\n    foo\n  properties:\n    quarks: {}\n  stemcell: \"\"\n  vm_resources: null\n- azs: null\n  env:\n    bosh:\n      agent:\n        settings: {}\n      ipv6:\n        enable: false\n  instances: 0\n  jobs: null\n  name: |\n    bar\n  properties:\n    quarks: {}\n  stemcell: \"\"\n  vm_resources: null\n"}`))
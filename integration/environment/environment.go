@@ -46,10 +46,8 @@ func NewEnvironment(kubeConfig *rest.Config) *Environment {
 			Namespace:  utils.GetNamespaceName(namespaceID),
 			KubeConfig: kubeConfig,
 			Config: &config.Config{
-				CtxTimeOut:           10 * time.Second,
-				MeltdownDuration:     10 * time.Second,
-				MeltdownRequeueAfter: 1 * time.Second,
-				Fs:                   afero.NewOsFs(),
+				CtxTimeOut: 10 * time.Second,
+				Fs:         afero.NewOsFs(),
 			},
 		},
 		Machine: Machine{
@@ -108,7 +108,7 @@ func (e *Environment) setupCFOperator() (manager.Manager, error) {
 		LeaderElection:     false,
 		Port:               int(e.Config.WebhookServerPort),
 		Host:               "0.0.0.0",
-	})
+	}, operator.LeaderElectionOptions{})
 
 	return mgr, err
 }
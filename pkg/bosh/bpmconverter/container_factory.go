@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +34,9 @@ const (
 
 	// EnvLogsDir is the path from where to tail file logs.
 	EnvLogsDir = "LOGS_DIR"
+
+	// LogsContainerName is the name of the sidecar, which tails the job logs.
+	LogsContainerName = "logs"
 )
 
 // ContainerFactoryImpl is a concrete implementation of ContainerFactor.
@@ -227,17 +232,27 @@ func (c *ContainerFactoryImpl) JobsToContainers(
 			// process container.
 			var postStart postStart
 			if processIndex == 0 {
-				conditionProperty := job.Properties.Quarks.PostStart.Condition
+				postStartProperty := job.Properties.Quarks.PostStart
+				conditionProperty := postStartProperty.Condition
 				if conditionProperty != nil && conditionProperty.Exec != nil && len(conditionProperty.Exec.Command) > 0 {
 					postStart.condition = &containerrun.Command{
 						Name: conditionProperty.Exec.Command[0],
 						Arg:  conditionProperty.Exec.Command[1:],
 					}
+					postStart.conditionInterval = time.Duration(conditionProperty.PeriodSeconds) * time.Second
 				}
 
 				postStart.command = &containerrun.Command{
 					Name: filepath.Join(VolumeJobsDirMountPath, job.Name, "bin", "post-start"),
 				}
+				postStart.timeout = time.Duration(postStartProperty.TimeoutSeconds) * time.Second
+				postStart.failurePolicy = postStartProperty.FailurePolicy
+				postStart.retries = postStartProperty.Retries
+
+				options := containerrun.PostStartOptions{FailurePolicy: postStart.failurePolicy, Retries: postStart.retries}
+				if err := options.Validate(); err != nil {
+					return nil, errors.Wrapf(err, "invalid post-start of bosh job '%s'", job.Name)
+				}
 			}
 
 			container := bpmProcessContainer(
@@ -270,7 +285,7 @@ func (c *ContainerFactoryImpl) JobsToContainers(
 // logsTailerContainer is a container that tails all logs in /var/vcap/sys/log.
 func logsTailerContainer(instanceGroupName string) corev1.Container {
 	return corev1.Container{
-		Name:            "logs",
+		Name:            LogsContainerName,
 		Image:           operatorimage.GetOperatorDockerImage(),
 		ImagePullPolicy: operatorimage.GetOperatorImagePullPolicy(),
 		VolumeMounts:    []corev1.VolumeMount{*sysDirVolumeMount()},
@@ -487,6 +502,10 @@ func bpmPreStartInitContainer(
 
 type postStart struct {
 	command, condition *containerrun.Command
+	conditionInterval  time.Duration
+	timeout            time.Duration
+	failurePolicy      string
+	retries            int
 }
 
 func bpmProcessContainer(
@@ -649,7 +668,25 @@ func generateBPMCommand(
 			for _, arg := range postStart.condition.Arg {
 				args = append(args, "--post-start-condition-arg", arg)
 			}
+			if postStart.conditionInterval > 0 {
+				args = append(args, "--post-start-condition-interval", postStart.conditionInterval.String())
+			}
+		}
+		if postStart.timeout > 0 {
+			args = append(args, "--post-start-timeout", postStart.timeout.String())
 		}
+		if postStart.failurePolicy != "" {
+			args = append(args, "--post-start-failure-policy", postStart.failurePolicy)
+		}
+		if postStart.retries > 0 {
+			args = append(args, "--post-start-retries", strconv.Itoa(postStart.retries))
+		}
+	}
+	if process.Limits.OpenFiles > 0 {
+		args = append(args, "--open-files-limit", strconv.Itoa(process.Limits.OpenFiles))
+	}
+	if process.Limits.Processes > 0 {
+		args = append(args, "--processes-limit", strconv.Itoa(process.Limits.Processes))
 	}
 	args = append(args, "--")
 	args = append(args, process.Executable)
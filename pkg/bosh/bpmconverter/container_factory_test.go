@@ -407,6 +407,35 @@ var _ = Describe("ContainerFactory", func() {
 			Expect(containers[0].Resources.Limits.Memory().String()).To(Equal("5G"))
 		})
 
+		It("passes open files and processes limits from bpm config to container-run", func() {
+			jobs = []bdm.Job{
+				{Name: "fake-job"},
+			}
+
+			bpmConfigs["fake-job"] = bpm.Config{
+				Processes: []bpm.Process{
+					{
+						Name:       "fake-job",
+						Executable: "/var/vcap/packages/fake-job/bin/fake-job",
+						Limits:     bpm.Limits{OpenFiles: 100000, Processes: 1000},
+					},
+				},
+			}
+			containers, err := act()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containers[0].Args).To(Equal([]string{
+				"/var/vcap/all-releases/container-run/container-run",
+				"--post-start-name",
+				"/var/vcap/jobs/fake-job/bin/post-start",
+				"--open-files-limit",
+				"100000",
+				"--processes-limit",
+				"1000",
+				"--",
+				"/var/vcap/packages/fake-job/bin/fake-job",
+			}))
+		})
+
 		It("doesn't add invalid k8s resource limits from bpm config", func() {
 			jobs = []bdm.Job{
 				{Name: "fake-job"},
@@ -480,6 +509,81 @@ var _ = Describe("ContainerFactory", func() {
 					"--",
 					""))
 			})
+
+			It("passes the post-start timeout, condition period and failure policy", func() {
+				jobs = []bdm.Job{
+					bdm.Job{
+						Name: "fake-job",
+						Properties: bdm.JobProperties{
+							Quarks: bdm.Quarks{
+								PostStart: bdm.PostStart{
+									Condition: &bdm.PostStartCondition{
+										Exec: &corev1.ExecAction{
+											Command: []string{"fake_health_check"},
+										},
+										PeriodSeconds: 10,
+									},
+									TimeoutSeconds: 300,
+									FailurePolicy:  "retry",
+									Retries:        2,
+								},
+							},
+						},
+					},
+				}
+
+				containers, err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(containers[0].Args).To(Equal([]string{
+					"/var/vcap/all-releases/container-run/container-run",
+					"--post-start-name",
+					"/var/vcap/jobs/fake-job/bin/post-start",
+					"--post-start-condition-name",
+					"fake_health_check",
+					"--post-start-condition-interval",
+					"10s",
+					"--post-start-timeout",
+					"5m0s",
+					"--post-start-failure-policy",
+					"retry",
+					"--post-start-retries",
+					"2",
+					"--",
+					"",
+				}))
+			})
+			It("fails for an unknown post-start failure policy", func() {
+				jobs = []bdm.Job{
+					bdm.Job{
+						Name: "fake-job",
+						Properties: bdm.JobProperties{
+							Quarks: bdm.Quarks{
+								PostStart: bdm.PostStart{FailurePolicy: "retyr"},
+							},
+						},
+					},
+				}
+
+				_, err := act()
+				Expect(err).To(MatchError("invalid post-start of bosh job 'fake-job': invalid post-start failure policy 'retyr'"))
+			})
+
+			It("fails for negative post-start retries", func() {
+				jobs = []bdm.Job{
+					bdm.Job{
+						Name: "fake-job",
+						Properties: bdm.JobProperties{
+							Quarks: bdm.Quarks{
+								PostStart: bdm.PostStart{FailurePolicy: "retry", Retries: -1},
+							},
+						},
+					},
+				}
+
+				_, err := act()
+				Expect(err).To(MatchError("invalid post-start of bosh job 'fake-job': invalid post-start retries -1"))
+			})
 		})
 
 		Context("with logging sidecar container", func() {
@@ -27,7 +27,6 @@ var (
 
 // BPMConverter converts BPM information to kubernetes resources
 type BPMConverter struct {
-	namespace               string
 	volumeFactory           VolumeFactory
 	newContainerFactoryFunc NewContainerFactoryFunc
 }
@@ -57,9 +56,8 @@ type DomainNameService interface {
 }
 
 // NewConverter returns a new converter
-func NewConverter(namespace string, volumeFactory VolumeFactory, newContainerFactoryFunc NewContainerFactoryFunc) *BPMConverter {
+func NewConverter(volumeFactory VolumeFactory, newContainerFactoryFunc NewContainerFactoryFunc) *BPMConverter {
 	return &BPMConverter{
-		namespace:               namespace,
 		volumeFactory:           volumeFactory,
 		newContainerFactoryFunc: newContainerFactoryFunc,
 	}
@@ -75,11 +73,11 @@ type Resources struct {
 
 // Resources uses BOSH Process Manager information to create k8s container specs from single BOSH instance group.
 // It returns quarks stateful sets, services and quarks jobs.
-func (kc *BPMConverter) Resources(manifestName string, dns DomainNameService, qStsVersion string, instanceGroup *bdm.InstanceGroup, releaseImageProvider bdm.ReleaseImageProvider, bpmConfigs bpm.Configs, igResolvedSecretVersion string) (*Resources, error) {
+func (kc *BPMConverter) Resources(manifestName string, namespace string, dns DomainNameService, qStsVersion string, instanceGroup *bdm.InstanceGroup, releaseImageProvider bdm.ReleaseImageProvider, bpmConfigs bpm.Configs, igResolvedSecretVersion string) (*Resources, error) {
 	instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Set(manifestName, instanceGroup.Name, qStsVersion)
 
-	defaultDisks := kc.volumeFactory.GenerateDefaultDisks(manifestName, instanceGroup.Name, igResolvedSecretVersion, kc.namespace)
-	bpmDisks, err := kc.volumeFactory.GenerateBPMDisks(manifestName, instanceGroup, bpmConfigs, kc.namespace)
+	defaultDisks := kc.volumeFactory.GenerateDefaultDisks(manifestName, instanceGroup.Name, igResolvedSecretVersion, namespace)
+	bpmDisks, err := kc.volumeFactory.GenerateBPMDisks(manifestName, instanceGroup, bpmConfigs, namespace)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Generate of BPM disks failed for manifest name %s, instance group %s.", manifestName, instanceGroup.Name)
 	}
@@ -101,19 +99,19 @@ func (kc *BPMConverter) Resources(manifestName string, dns DomainNameService, qS
 
 	switch instanceGroup.LifeCycle {
 	case bdm.IGTypeService, "":
-		convertedExtStatefulSet, err := kc.serviceToQuarksStatefulSet(cfac, manifestName, dns, instanceGroup, defaultDisks, bpmDisks)
+		convertedExtStatefulSet, err := kc.serviceToQuarksStatefulSet(cfac, manifestName, namespace, dns, instanceGroup, defaultDisks, bpmDisks)
 		if err != nil {
 			return nil, err
 		}
 
-		services := kc.serviceToKubeServices(manifestName, dns, instanceGroup, &convertedExtStatefulSet)
+		services := kc.serviceToKubeServices(manifestName, namespace, dns, instanceGroup, &convertedExtStatefulSet)
 		if len(services) != 0 {
 			res.Services = append(res.Services, services...)
 		}
 
 		res.InstanceGroups = append(res.InstanceGroups, convertedExtStatefulSet)
 	case bdm.IGTypeErrand, bdm.IGTypeAutoErrand:
-		convertedQJob, err := kc.errandToQuarksJob(cfac, manifestName, dns, instanceGroup, defaultDisks, bpmDisks)
+		convertedQJob, err := kc.errandToQuarksJob(cfac, manifestName, namespace, dns, instanceGroup, defaultDisks, bpmDisks)
 		if err != nil {
 			return nil, err
 		}
@@ -128,6 +126,7 @@ func (kc *BPMConverter) Resources(manifestName string, dns DomainNameService, qS
 func (kc *BPMConverter) serviceToQuarksStatefulSet(
 	cfac ContainerFactory,
 	manifestName string,
+	namespace string,
 	dns DomainNameService,
 	instanceGroup *bdm.InstanceGroup,
 	defaultDisks disk.BPMResourceDisks,
@@ -164,7 +163,7 @@ func (kc *BPMConverter) serviceToQuarksStatefulSet(
 	extSts := qstsv1a1.QuarksStatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        instanceGroup.QuarksStatefulSetName(manifestName),
-			Namespace:   kc.namespace,
+			Namespace:   namespace,
 			Labels:      instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Labels,
 			Annotations: instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Annotations,
 		},
@@ -208,7 +207,7 @@ func (kc *BPMConverter) serviceToQuarksStatefulSet(
 	}
 
 	spec := &extSts.Spec.Template.Spec.Template.Spec
-	spec.DNSPolicy, spec.DNSConfig, err = dns.DNSSetting(kc.namespace)
+	spec.DNSPolicy, spec.DNSConfig, err = dns.DNSSetting(namespace)
 	if err != nil {
 		return qstsv1a1.QuarksStatefulSet{}, err
 	}
@@ -229,7 +228,7 @@ func (kc *BPMConverter) serviceToQuarksStatefulSet(
 }
 
 // serviceToKubeServices will generate Services which expose ports for InstanceGroup's jobs
-func (kc *BPMConverter) serviceToKubeServices(manifestName string, dns DomainNameService, instanceGroup *bdm.InstanceGroup, qSts *qstsv1a1.QuarksStatefulSet) []corev1.Service {
+func (kc *BPMConverter) serviceToKubeServices(manifestName string, namespace string, dns DomainNameService, instanceGroup *bdm.InstanceGroup, qSts *qstsv1a1.QuarksStatefulSet) []corev1.Service {
 	var services []corev1.Service
 	// Collect ports to be exposed for each job
 	ports := instanceGroup.ServicePorts()
@@ -249,6 +248,7 @@ func (kc *BPMConverter) serviceToKubeServices(manifestName string, dns DomainNam
 			services = kc.generateServices(services,
 				*instanceGroup,
 				manifestName,
+				namespace,
 				azIndex,
 				activePassiveModel,
 				ports)
@@ -257,6 +257,7 @@ func (kc *BPMConverter) serviceToKubeServices(manifestName string, dns DomainNam
 		services = kc.generateServices(services,
 			*instanceGroup,
 			manifestName,
+			namespace,
 			-1,
 			activePassiveModel,
 			ports)
@@ -273,7 +274,7 @@ func (kc *BPMConverter) serviceToKubeServices(manifestName string, dns DomainNam
 	headlessService := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        headlessServiceName,
-			Namespace:   kc.namespace,
+			Namespace:   namespace,
 			Labels:      instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Labels,
 			Annotations: instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Annotations,
 		},
@@ -296,6 +297,7 @@ func (kc *BPMConverter) serviceToKubeServices(manifestName string, dns DomainNam
 func (kc *BPMConverter) errandToQuarksJob(
 	cfac ContainerFactory,
 	manifestName string,
+	namespace string,
 	dns DomainNameService,
 	instanceGroup *bdm.InstanceGroup,
 	defaultDisks disk.BPMResourceDisks,
@@ -330,7 +332,7 @@ func (kc *BPMConverter) errandToQuarksJob(
 	qJob := qjv1a1.QuarksJob{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        fmt.Sprintf("%s-%s", manifestName, instanceGroup.Name),
-			Namespace:   kc.namespace,
+			Namespace:   namespace,
 			Labels:      instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Labels,
 			Annotations: instanceGroup.Env.AgentEnvBoshConfig.Agent.Settings.Annotations,
 		},
@@ -362,7 +364,7 @@ func (kc *BPMConverter) errandToQuarksJob(
 		},
 	}
 
-	qJob.Spec.Template.Spec.Template.Spec.DNSPolicy, qJob.Spec.Template.Spec.Template.Spec.DNSConfig, err = dns.DNSSetting(kc.namespace)
+	qJob.Spec.Template.Spec.Template.Spec.DNSPolicy, qJob.Spec.Template.Spec.Template.Spec.DNSConfig, err = dns.DNSSetting(namespace)
 
 	if err != nil {
 		return qjv1a1.QuarksJob{}, err
@@ -390,6 +392,7 @@ func (kc *BPMConverter) errandToQuarksJob(
 func (kc *BPMConverter) generateServices(services []corev1.Service,
 	instanceGroup bdm.InstanceGroup,
 	manifestName string,
+	namespace string,
 	azIndex int,
 	activePassiveModel bool,
 	ports []corev1.ServicePort) []corev1.Service {
@@ -413,7 +416,7 @@ func (kc *BPMConverter) generateServices(services []corev1.Service,
 		services = append(services, corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      instanceGroup.IndexedServiceName(manifestName, i, azIndex),
-				Namespace: kc.namespace,
+				Namespace: namespace,
 				Labels:    serviceLabels(azIndex, i, false),
 			},
 			Spec: corev1.ServiceSpec{
@@ -40,12 +40,11 @@ var _ = Describe("BPM Converter", func() {
 	Context("Resources", func() {
 		act := func(bpmConfigs bpm.Configs, instanceGroup *manifest.InstanceGroup) (*bpmconverter.Resources, error) {
 			c := bpmconverter.NewConverter(
-				"foo",
 				volumeFactory,
 				func(manifestName string, instanceGroupName string, version string, disableLogSidecar bool, releaseImageProvider bdm.ReleaseImageProvider, bpmConfigs bpm.Configs) bpmconverter.ContainerFactory {
 					return containerFactory
 				})
-			resources, err := c.Resources(deploymentName, dns, "1", instanceGroup, m, bpmConfigs, "1")
+			resources, err := c.Resources(deploymentName, "foo", dns, "1", instanceGroup, m, bpmConfigs, "1")
 			return resources, err
 		}
 
@@ -10,7 +10,7 @@ type matcher func(*InstanceGroup, *AddOnPlacementRules) (bool, error)
 
 // jobMatch matches stemcell rules for addon placement
 func (m *Manifest) stemcellMatch(instanceGroup *InstanceGroup, rules *AddOnPlacementRules) (bool, error) {
-	if instanceGroup == nil || rules == nil {
+	if instanceGroup == nil || rules == nil || len(rules.Stemcell) == 0 {
 		return false, nil
 	}
 
@@ -106,3 +106,13 @@ func (m *Manifest) addOnPlacementMatch(placementType string, instanceGroup *Inst
 
 	return matchResult, nil
 }
+
+// empty returns true if the rules don't have any criteria to match instance groups
+func (r *AddOnPlacementRules) empty() bool {
+	return len(r.Stemcell) == 0 &&
+		len(r.Deployments) == 0 &&
+		len(r.Jobs) == 0 &&
+		len(r.InstanceGroup) == 0 &&
+		len(r.Networks) == 0 &&
+		len(r.Teams) == 0
+}
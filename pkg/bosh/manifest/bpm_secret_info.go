@@ -11,6 +11,8 @@ type BPMInfo struct {
 	InstanceGroup BPMInstanceGroup `json:"instance_group,omitempty"`
 	Configs       bpm.Configs      `json:"configs,omitempty"`
 	Variables     []Variable       `json:"variables,omitempty"`
+	// PropertyWarnings are the findings of the job property validation in warn mode
+	PropertyWarnings []string `json:"property_warnings,omitempty"`
 }
 
 // BPMInstanceGroup is a custom instance group spec
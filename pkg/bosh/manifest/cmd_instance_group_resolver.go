@@ -178,6 +178,40 @@ func (igr *InstanceGroupResolver) SaveLinks(path string) error {
 	return nil
 }
 
+// SaveSharedLinks writes shared-links.json with the links of this instance
+// group, which are provided with `shared: true`. Each link is persisted as a
+// secret, so other deployments can consume it.
+func (igr *InstanceGroupResolver) SaveSharedLinks(path string) error {
+	path = filepath.Join(path, SharedLinksFilename)
+	igName := igr.instanceGroup.Name
+
+	var result = map[string]string{}
+	for id, link := range igr.jobProviderLinks.shared[igName] {
+		linkBytes, err := json.Marshal(link)
+		if err != nil {
+			return errors.Wrapf(err, "JSON marshalling failed for ig '%s' shared link '%s'", igName, id)
+		}
+
+		jsonBytes, err := json.Marshal(map[string]string{SharedLinkSecretKey: string(linkBytes)})
+		if err != nil {
+			return errors.Wrapf(err, "JSON marshalling failed for ig '%s' shared link '%s'", igName, id)
+		}
+		result[id] = string(jsonBytes)
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return errors.Wrapf(err, "JSON marshalling failed for ig '%s' shared links", igName)
+	}
+
+	err = afero.WriteFile(igr.fs, path, jsonBytes, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to write JSON to a output file '%s'", path)
+	}
+
+	return nil
+}
+
 // CollectQuarksLinks collects all links from a directory specified by path
 func (igr *InstanceGroupResolver) CollectQuarksLinks(linksPath string) error {
 	exist, err := afero.DirExists(igr.fs, linksPath)
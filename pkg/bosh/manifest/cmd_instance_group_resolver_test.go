@@ -348,6 +348,29 @@ var _ = Describe("InstanceGroupResolver", func() {
 						"nats-nutty_nuts": `{"nats.password":"changeme","nats.port":"4222","nats.user":"admin"}`,
 					}))
 				})
+
+				It("stores the shared links of the instance group in a file", func() {
+					resolve()
+					err = igr.SaveSharedLinks("/mnt/quarks")
+					Expect(err).ToNot(HaveOccurred())
+
+					content := fileContentOf("/mnt/quarks/shared-links.json")
+					Expect(content).To(HaveKey("nats-nutty_nuts"))
+
+					var secretData map[string]string
+					Expect(json.Unmarshal([]byte(content["nats-nutty_nuts"]), &secretData)).ToNot(HaveOccurred())
+
+					var link SharedLink
+					Expect(json.Unmarshal([]byte(secretData["link"]), &link)).ToNot(HaveOccurred())
+					Expect(link.Name).To(Equal("nutty_nuts"))
+					Expect(link.Type).To(Equal("nats"))
+					Expect(link.Instances).To(HaveLen(2))
+					Expect(link.Properties).To(Equal(map[string]string{
+						"nats.password": "changeme",
+						"nats.port":     "4222",
+						"nats.user":     "admin",
+					}))
+				})
 			})
 		})
 
@@ -15,11 +15,34 @@ import (
 
 // InterpolateVariables reads explicit secrets from a folder and writes an interpolated manifest to the output.json file in /mnt/quarks volume mount.
 func InterpolateVariables(log *zap.SugaredLogger, boshManifestBytes []byte, variablesDir string, outputFilePath string) error {
+	yamlBytes, err := InterpolateVariablesFromDir(boshManifestBytes, variablesDir)
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.Marshal(map[string]string{
+		DesiredManifestKeyName: string(yamlBytes),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not marshal json output")
+	}
+
+	err = ioutil.WriteFile(outputFilePath, jsonBytes, 0644)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// InterpolateVariablesFromDir reads explicit secrets from a folder and returns the interpolated manifest as YAML.
+// Each directory is a variable and each of its files is a field of that variable.
+func InterpolateVariablesFromDir(boshManifestBytes []byte, variablesDir string) ([]byte, error) {
 	var vars []boshtpl.Variables
 
 	variables, err := ioutil.ReadDir(variablesDir)
 	if err != nil {
-		return errors.Wrapf(err, "could not read variables directory")
+		return nil, errors.Wrapf(err, "could not read variables directory")
 	}
 
 	for _, variable := range variables {
@@ -54,7 +77,7 @@ func InterpolateVariables(log *zap.SugaredLogger, boshManifestBytes []byte, vari
 				return nil
 			})
 			if err != nil {
-				return errors.Wrapf(err, "could not read directory  %s", variable.Name())
+				return nil, errors.Wrapf(err, "could not read directory  %s", variable.Name())
 			}
 
 			vars = append(vars, staticVars)
@@ -73,32 +96,20 @@ func InterpolateVariables(log *zap.SugaredLogger, boshManifestBytes []byte, vari
 
 	yamlBytes, err := tpl.Evaluate(multiVars, op, evalOpts)
 	if err != nil {
-		return errors.Wrapf(err, "could not evaluate variables")
+		return nil, errors.Wrapf(err, "could not evaluate variables")
 	}
 
 	m, err := LoadYAML(yamlBytes)
 	if err != nil {
-		return errors.Wrapf(err, "could not evaluate variables")
+		return nil, errors.Wrapf(err, "could not evaluate variables")
 	}
 
 	yamlBytes, err = m.Marshal()
 	if err != nil {
-		return errors.Wrapf(err, "could not evaluate variables")
-	}
-
-	jsonBytes, err := json.Marshal(map[string]string{
-		DesiredManifestKeyName: string(yamlBytes),
-	})
-	if err != nil {
-		return errors.Wrapf(err, "could not marshal json output")
-	}
-
-	err = ioutil.WriteFile(outputFilePath, jsonBytes, 0644)
-	if err != nil {
-		return err
+		return nil, errors.Wrapf(err, "could not evaluate variables")
 	}
 
-	return nil
+	return yamlBytes, nil
 }
 
 func mergeStaticVar(staticVar interface{}, field string, value string) interface{} {
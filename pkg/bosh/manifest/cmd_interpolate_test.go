@@ -2,6 +2,7 @@ package manifest_test
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"go.uber.org/zap"
@@ -19,9 +20,14 @@ var _ = Describe("InterpolateVariables", func() {
 		baseManifest   []byte
 		varDir         string
 		log            *zap.SugaredLogger
+		tmpDir         string
 		outputFilePath string
 	)
 	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "interpolate")
+		Expect(err).ToNot(HaveOccurred())
+
 		_, log = helper.NewTestLogger()
 		baseManifest = []byte(`
 ---
@@ -32,8 +38,11 @@ instance_groups:
 - name: ((value2.key3))
 `)
 		varDir = filepath.Join(assetPath, "vars")
-		outputFilePath = filepath.Join(assetPath, "output.json")
+		outputFilePath = filepath.Join(tmpDir, "output.json")
+	})
 
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
 	})
 
 	It("returns interpolated manifest", func() {
@@ -53,4 +62,17 @@ instance_groups:
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("could not read variables directory"))
 	})
+
+	Context("when not writing an output file", func() {
+		It("returns the interpolated manifest", func() {
+			yamlBytes, err := InterpolateVariablesFromDir(baseManifest, varDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			m, err := LoadYAML(yamlBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.DirectorUUID).To(Equal("fake-password\n"))
+			Expect(m.InstanceGroups).To(HaveLen(3))
+			Expect(m.InstanceGroups[0].Name).To(Equal("baz\n"))
+		})
+	})
 })
@@ -74,12 +74,20 @@ type PreRenderScripts struct {
 // PostStart allows post-start specifics to be passed through the manifest.
 type PostStart struct {
 	Condition *PostStartCondition `json:"condition,omitempty"`
+	// TimeoutSeconds is the time post-start, including its condition, may take. Defaults to 15 minutes.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// FailurePolicy is either "fail" (default), "ignore" or "retry".
+	FailurePolicy string `json:"failure_policy,omitempty"`
+	// Retries is the number of retries for the "retry" failure policy.
+	Retries int `json:"retries,omitempty"`
 }
 
 // PostStartCondition represents the condition that should succeed in order to execute the
 // post-start script. It's often set to be the same as the readiness probe of a job.
 type PostStartCondition struct {
 	Exec *corev1.ExecAction `json:"exec,omitempty"`
+	// PeriodSeconds is the time between two runs of the condition. Defaults to 3 seconds.
+	PeriodSeconds int `json:"period_seconds,omitempty"`
 }
 
 // QuarksLink represents the links to share/discover information between BOSH and Kube Native components
@@ -88,3 +96,26 @@ type QuarksLink struct {
 	Address   string        `json:"address,omitempty"`
 	Instances []JobInstance `json:"instances,omitempty"`
 }
+
+const (
+	// SharedLinksFilename is the name of the output file for shared links of an instance group
+	SharedLinksFilename = "shared-links.json"
+	// SharedLinkSecretKey is the key of the JSON encoded SharedLink in a shared link secret
+	SharedLinkSecretKey = "link"
+)
+
+// SharedLink is a link provided with `shared: true`, which can be consumed by
+// other deployments. The properties are flattened, like in link secrets.
+type SharedLink struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Address    string            `json:"address,omitempty"`
+	Instances  []JobInstance     `json:"instances,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// CrossDeploymentLink is a link consumed from another deployment via `deployment:`
+type CrossDeploymentLink struct {
+	Deployment string
+	Provider   string
+}
@@ -0,0 +1,84 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateProperties compares the properties of the job with the properties
+// declared in its job spec. It returns a finding for each property, which
+// isn't declared in the spec, and for each declared property, which has
+// neither a default nor a value.
+func (js JobSpec) ValidateProperties(job Job) []string {
+	findings := []string{}
+
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		if _, ok := js.Properties[path]; ok {
+			return
+		}
+
+		children := map[string]interface{}{}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			children = v
+		case map[interface{}]interface{}:
+			for key, child := range v {
+				children[fmt.Sprintf("%v", key)] = child
+			}
+		}
+
+		if len(children) == 0 {
+			if !js.isPropertyPrefix(path) {
+				findings = append(findings, fmt.Sprintf("job '%s': property '%s' is not declared in the job spec", job.Name, path))
+			}
+			return
+		}
+
+		for key, child := range children {
+			walk(path+"."+key, child)
+		}
+	}
+
+	for key, value := range job.Properties.Properties {
+		walk(key, value)
+	}
+
+	for name, property := range js.Properties {
+		if property.Default != nil {
+			continue
+		}
+		if _, ok := job.Property(name); !ok {
+			findings = append(findings, fmt.Sprintf("job '%s': property '%s' has no default and no value", job.Name, name))
+		}
+	}
+
+	sort.Strings(findings)
+	return findings
+}
+
+// isPropertyPrefix returns true if path is the parent of a declared property
+func (js JobSpec) isPropertyPrefix(path string) bool {
+	for name := range js.Properties {
+		if strings.HasPrefix(name, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateProperties validates the job properties of the instance group
+// against the job specs, see JobSpec.ValidateProperties. It has to be
+// called after Resolve, which loads the job specs.
+func (igr *InstanceGroupResolver) ValidateProperties() []string {
+	findings := []string{}
+	for _, job := range igr.instanceGroup.Jobs {
+		spec, ok := igr.jobReleaseSpecs[job.Release][job.Name]
+		if !ok {
+			continue
+		}
+		findings = append(findings, spec.ValidateProperties(job)...)
+	}
+	return findings
+}
@@ -0,0 +1,69 @@
+package manifest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	. "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+)
+
+var _ = Describe("JobSpec", func() {
+	Describe("ValidateProperties", func() {
+		var (
+			spec JobSpec
+			job  Job
+		)
+
+		BeforeEach(func() {
+			err := yaml.Unmarshal([]byte(`---
+name: nats
+properties:
+  nats.user:
+    description: "Username"
+  nats.password:
+    description: "Password"
+  nats.port:
+    default: 4222
+  nats.tls.enabled:
+    default: false
+  nats.config:
+    default: {}
+`), &spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = yaml.Unmarshal([]byte(`---
+name: nats
+release: nats
+properties:
+  nats:
+    user: admin
+    password: secret
+    tls: {}
+    config:
+      debug: true
+`), &job)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("accepts properties declared in the spec", func() {
+			Expect(spec.ValidateProperties(job)).To(BeEmpty())
+		})
+
+		It("reports misspelled properties", func() {
+			job.Properties.Properties["nats"] = map[string]interface{}{
+				"user":    "admin",
+				"pasword": "secret",
+				"tls":     map[string]interface{}{"enable": true},
+			}
+			job.Properties.Properties["nast"] = map[string]interface{}{"port": 4223}
+
+			Expect(spec.ValidateProperties(job)).To(Equal([]string{
+				"job 'nats': property 'nast.port' is not declared in the job spec",
+				"job 'nats': property 'nats.password' has no default and no value",
+				"job 'nats': property 'nats.pasword' is not declared in the job spec",
+				"job 'nats': property 'nats.tls.enable' is not declared in the job spec",
+			}))
+		})
+	})
+})
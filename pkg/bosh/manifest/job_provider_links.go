@@ -11,12 +11,15 @@ import (
 type jobProviderLinks struct {
 	links          map[string]map[string]JobLink
 	instanceGroups map[string]map[string]JobLinkProperties
+	// shared links, which other deployments can consume, by instance group
+	shared map[string]map[string]SharedLink
 }
 
 func newJobProviderLinks() jobProviderLinks {
 	return jobProviderLinks{
 		links:          map[string]map[string]JobLink{},
 		instanceGroups: map[string]map[string]JobLinkProperties{},
+		shared:         map[string]map[string]SharedLink{},
 	}
 }
 
@@ -49,6 +52,7 @@ func (jpl jobProviderLinks) Add(igName string, job Job, spec JobSpec, jobsInstan
 		}
 		linkName := link.Name
 		linkType := link.Type
+		shared := false
 
 		// instance_group.job can override the link name through the
 		// instance_group.job.provides, via the "as" key
@@ -59,6 +63,7 @@ func (jpl jobProviderLinks) Add(igName string, job Job, spec JobSpec, jobsInstan
 					if overrideLinkName, ok := value["as"]; ok {
 						linkName = fmt.Sprintf("%v", overrideLinkName)
 					}
+					shared, _ = value["shared"].(bool)
 				case string:
 					// As defined in the BOSH documentation, an explicit value of "nil" for
 					// the provider means the link is "blocked"
@@ -102,6 +107,19 @@ func (jpl jobProviderLinks) Add(igName string, job Job, spec JobSpec, jobsInstan
 			jpl.instanceGroups[igName] = map[string]JobLinkProperties{}
 		}
 		jpl.instanceGroups[igName][names.QuarksLinkSecretKey(linkType, linkName)] = properties
+
+		if shared {
+			if _, ok := jpl.shared[igName]; !ok {
+				jpl.shared[igName] = map[string]SharedLink{}
+			}
+			jpl.shared[igName][names.QuarksLinkSecretKey(linkType, linkName)] = SharedLink{
+				Name:       linkName,
+				Type:       linkType,
+				Address:    linkAddress,
+				Instances:  jobsInstances,
+				Properties: flattenForSecretData(properties),
+			}
+		}
 	}
 	return nil
 }
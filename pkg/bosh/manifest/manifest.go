@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -39,6 +40,7 @@ type Feature struct {
 	RandomizeAzPlacement *bool `json:"randomize_az_placement,omitempty"`
 	UseDNSAddresses      *bool `json:"use_dns_addresses,omitempty"`
 	UseTmpfsJobConfig    *bool `json:"use_tmpfs_job_config,omitempty"`
+	PostDeploy           *bool `json:"post_deploy,omitempty"`
 }
 
 // AuthType from BOSH deployment manifest
@@ -420,7 +422,8 @@ func (m *Manifest) GetJobOS(instanceGroupName, jobName string) (string, error) {
 	return "", fmt.Errorf("release '%s' not found", job.Release)
 }
 
-// ImplicitVariables returns a list of all implicit variables in a manifest
+// ImplicitVariables returns a list of all references to implicit variables
+// in a manifest, e.g. 'name', 'name/key' or 'name.key.nested'
 func (m *Manifest) ImplicitVariables() ([]string, error) {
 	varMap := make(map[string]bool)
 
@@ -431,30 +434,28 @@ func (m *Manifest) ImplicitVariables() ([]string, error) {
 
 	rawManifest := string(manifestBytes)
 
-	// Collect all variables
+	// Collect all variables, except the explicit ones, including their subfields, e.g. ca.private_key
 	varRegexp := regexp.MustCompile(`\(\((!?[-/\.\w\pL]+)\)\)`)
 	for _, match := range varRegexp.FindAllStringSubmatch(rawManifest, -1) {
-		main := match[1]
-		if !strings.Contains(main, "/") {
-			// Remove subfields from explicit vars, e.g. ca.private_key -> ca
+		reference := match[1]
+		if m.hasVariable(reference) {
+			continue
+		}
+		if !strings.Contains(reference, "/") {
 			fieldRegexp := regexp.MustCompile(`[^\.]+`)
-			main = fieldRegexp.FindString(match[1])
+			if m.hasVariable(fieldRegexp.FindString(reference)) {
+				continue
+			}
 		}
 
-		varMap[main] = true
-	}
-
-	// Remove the explicit ones
-	for _, v := range m.Variables {
-		varMap[v.Name] = false
+		varMap[reference] = true
 	}
 
 	names := []string{}
-	for k, v := range varMap {
-		if v {
-			names = append(names, k)
-		}
+	for k := range varMap {
+		names = append(names, k)
 	}
+	sort.Strings(names)
 
 	return names, nil
 }
@@ -501,6 +502,14 @@ func (m *Manifest) ApplyAddons() error {
 	return nil
 }
 
+// PostDeployEnabled returns true, unless running post-deploy scripts was disabled in the features block
+func (m *Manifest) PostDeployEnabled() bool {
+	if m.Features == nil || m.Features.PostDeploy == nil {
+		return true
+	}
+	return *m.Features.PostDeploy
+}
+
 // PropagateGlobalUpdateBlockToIGs copies the update block to all instance groups
 func (m *Manifest) PropagateGlobalUpdateBlockToIGs() {
 	for _, ig := range m.InstanceGroups {
@@ -522,13 +531,14 @@ func (m *Manifest) PropagateGlobalUpdateBlockToIGs() {
 
 // ListMissingProviders returns a list of missing providers from the manifest
 func (m *Manifest) ListMissingProviders() map[string]bool {
-	provideAsNames := map[string]bool{}
+	provideAsNames := m.ListProviders()
 	consumeFromNames := map[string]bool{}
 
 	for _, ig := range m.InstanceGroups {
 		for _, job := range ig.Jobs {
-			provideAsNames = listProviderNames(job.Provides, "as")
-			consumeFromNames = listProviderNames(job.Consumes, "from")
+			for name := range listProviderNames(job.Consumes, "from") {
+				consumeFromNames[name] = false
+			}
 		}
 	}
 
@@ -542,6 +552,66 @@ func (m *Manifest) ListMissingProviders() map[string]bool {
 	return consumeFromNames
 }
 
+// ListProviders returns the names of the links provided in the manifest
+func (m *Manifest) ListProviders() map[string]bool {
+	provideAsNames := map[string]bool{}
+
+	for _, ig := range m.InstanceGroups {
+		for _, job := range ig.Jobs {
+			// Links are provided by their name, unless renamed by 'as'
+			for name, property := range job.Provides {
+				if p, ok := property.(map[string]interface{}); ok {
+					if as, _ := p["as"].(string); as != "" {
+						name = as
+					}
+				}
+				provideAsNames[name] = false
+			}
+		}
+	}
+
+	return provideAsNames
+}
+
+// ListCrossDeploymentLinks returns the links consumed from other deployments
+func (m *Manifest) ListCrossDeploymentLinks() []CrossDeploymentLink {
+	found := map[CrossDeploymentLink]bool{}
+	links := []CrossDeploymentLink{}
+
+	for _, ig := range m.InstanceGroups {
+		for _, job := range ig.Jobs {
+			for name, property := range job.Consumes {
+				p, ok := property.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				deployment, _ := p["deployment"].(string)
+				if len(deployment) == 0 {
+					continue
+				}
+
+				link := CrossDeploymentLink{
+					Deployment: deployment,
+					Provider:   getProviderNameFromConsumer(job, name),
+				}
+				if !found[link] {
+					found[link] = true
+					links = append(links, link)
+				}
+			}
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].Deployment != links[j].Deployment {
+			return links[i].Deployment < links[j].Deployment
+		}
+		return links[i].Provider < links[j].Provider
+	})
+
+	return links
+}
+
 // listProviderNames returns a map containing provider names from job provides and consumes
 func listProviderNames(providerProperties map[string]interface{}, providerKey string) map[string]bool {
 	providerNames := map[string]bool{}
@@ -551,6 +621,10 @@ func listProviderNames(providerProperties map[string]interface{}, providerKey st
 		if !ok {
 			continue
 		}
+		// Links from other deployments are listed by ListCrossDeploymentLinks
+		if _, ok := p["deployment"]; ok {
+			continue
+		}
 		nameVal, ok := p[providerKey]
 		if !ok {
 			continue
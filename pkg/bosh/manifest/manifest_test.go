@@ -1473,5 +1473,69 @@ var _ = Describe("Manifest", func() {
 				}))
 			})
 		})
+
+		Describe("ListCrossDeploymentLinks", func() {
+			BeforeEach(func() {
+				manifest, err = LoadYAML([]byte(`---
+instance_groups:
+- name: api
+  jobs:
+  - name: cloud_controller_ng
+    release: capi
+    consumes:
+      database: {from: mysql, deployment: db}
+      nats: {from: nats}
+  - name: routing-api
+    release: routing
+    consumes:
+      database: {from: mysql, deployment: db}
+      cache: {deployment: redis}
+`))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the links consumed from other deployments", func() {
+				Expect(manifest.ListCrossDeploymentLinks()).To(Equal([]CrossDeploymentLink{
+					{Deployment: "db", Provider: "mysql"},
+					{Deployment: "redis", Provider: "cache"},
+				}))
+			})
+
+			It("doesn't list them as missing providers", func() {
+				Expect(manifest.ListMissingProviders()).ToNot(HaveKey("mysql"))
+			})
+		})
+
+		Describe("ImplicitVariables", func() {
+			BeforeEach(func() {
+				manifest, err = LoadYAML([]byte(`---
+instance_groups:
+- name: nats
+  jobs:
+  - name: nats
+    release: nats
+    properties:
+      port: ((nats.port))
+      ca: ((ssl/ca))
+      cert: ((ssl/cert.pem))
+      domain: ((system_domain))
+      url: 'https://api.((system_domain))'
+      private_key: ((ca.private_key))
+variables:
+- name: ca
+  type: certificate
+`))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the references of undeclared variables", func() {
+				Expect(manifest.ImplicitVariables()).To(Equal([]string{
+					"nats.port",
+					"ssl/ca",
+					"ssl/cert.pem",
+					"system_domain",
+				}))
+			})
+		})
 	})
 })
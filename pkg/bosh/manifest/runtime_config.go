@@ -0,0 +1,154 @@
+package manifest
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// RuntimeConfig is a BOSH runtime config. Its releases, addons and variables
+// are merged into all deployment manifests.
+type RuntimeConfig struct {
+	Releases  []*Release        `json:"releases,omitempty"`
+	AddOns    []*AddOn          `json:"addons,omitempty"`
+	Variables []Variable        `json:"variables,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// LoadRuntimeConfigYAML returns a new BOSH runtime config from a yaml representation
+func LoadRuntimeConfigYAML(data []byte) (*RuntimeConfig, error) {
+	rc := &RuntimeConfig{}
+	err := yaml.Unmarshal(data, rc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal BOSH runtime config %s", string(data))
+	}
+
+	return rc, nil
+}
+
+// ApplyRuntimeConfig merges the runtime config into the manifest. Releases,
+// variables and tags of the manifest take precedence over the ones with the
+// same name from the runtime config. Addons are added, if their deployment
+// placement rules match the deployment.
+func (m *Manifest) ApplyRuntimeConfig(deploymentName string, rc *RuntimeConfig) {
+	for _, release := range rc.Releases {
+		if !lookUpJobRelease(m.Releases, release.Name) {
+			m.Releases = append(m.Releases, release)
+		}
+	}
+
+	for _, variable := range rc.Variables {
+		if !m.hasVariable(variable.Name) {
+			m.Variables = append(m.Variables, variable)
+		}
+	}
+
+	for key, value := range rc.Tags {
+		if m.Tags == nil {
+			m.Tags = map[string]string{}
+		}
+		if _, ok := m.Tags[key]; !ok {
+			m.Tags[key] = value
+		}
+	}
+
+	for _, addon := range rc.AddOns {
+		if addon, ok := addOnDeploymentMatch(deploymentName, addon); ok {
+			// Like in BOSH, runtime config addons without include rules are
+			// placed on all instance groups
+			m.includeAllInstanceGroups(addon)
+			m.AddOns = append(m.AddOns, addon)
+		}
+	}
+}
+
+// ApplyAddOnDeploymentRules removes addons, which are not placed on the
+// deployment by their `deployments` include and exclude rules. Addons, which
+// are only included by their `deployments` rule, are placed on all instance
+// groups.
+func (m *Manifest) ApplyAddOnDeploymentRules(deploymentName string) {
+	if len(m.AddOns) == 0 {
+		return
+	}
+
+	addons := []*AddOn{}
+	for _, addon := range m.AddOns {
+		includesDeployments := addon.Include != nil && len(addon.Include.Deployments) > 0
+		if addon, ok := addOnDeploymentMatch(deploymentName, addon); ok {
+			if includesDeployments {
+				m.includeAllInstanceGroups(addon)
+			}
+			addons = append(addons, addon)
+		}
+	}
+	m.AddOns = addons
+}
+
+// includeAllInstanceGroups lists all instance groups in the include rules of
+// the addon, if the rules don't select instance groups otherwise. The addon
+// placement matchers don't include any instance group for empty rules.
+func (m *Manifest) includeAllInstanceGroups(addon *AddOn) {
+	if addon.Include == nil {
+		addon.Include = &AddOnPlacementRules{}
+	}
+	if !addon.Include.empty() {
+		return
+	}
+
+	for _, ig := range m.InstanceGroups {
+		addon.Include.InstanceGroup = append(addon.Include.InstanceGroup, ig.Name)
+	}
+}
+
+func (m *Manifest) hasVariable(name string) bool {
+	for _, v := range m.Variables {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addOnDeploymentMatch returns true if the addon should be placed on the
+// deployment. The returned copy of the addon has no deployment rules, as the
+// instance group placement rules don't know about deployments.
+func addOnDeploymentMatch(deploymentName string, addon *AddOn) (*AddOn, bool) {
+	c := *addon
+	if addon.Include != nil {
+		include := *addon.Include
+		c.Include = &include
+	}
+	if addon.Exclude != nil {
+		exclude := *addon.Exclude
+		c.Exclude = &exclude
+	}
+
+	if c.Include != nil && len(c.Include.Deployments) > 0 {
+		if !contains(c.Include.Deployments, deploymentName) {
+			return nil, false
+		}
+		c.Include.Deployments = nil
+	}
+
+	if c.Exclude != nil && len(c.Exclude.Deployments) > 0 {
+		excluded := contains(c.Exclude.Deployments, deploymentName)
+		c.Exclude.Deployments = nil
+		// Other exclude rules narrow down the instance groups within the deployment
+		if excluded && c.Exclude.empty() {
+			return nil, false
+		}
+		if !excluded {
+			c.Exclude = nil
+		}
+	}
+
+	return &c, true
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package manifest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+)
+
+var _ = Describe("RuntimeConfig", func() {
+	var (
+		manifest *Manifest
+		rc       *RuntimeConfig
+	)
+
+	jobNames := func(ig *InstanceGroup) []string {
+		names := []string{}
+		for _, job := range ig.Jobs {
+			names = append(names, job.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		var err error
+		manifest, err = LoadYAML([]byte(`---
+releases:
+- name: redis
+  version: 36.15.0
+instance_groups:
+- name: redis
+  instances: 1
+  jobs:
+  - name: redis-server
+    release: redis
+- name: worker
+  instances: 1
+  jobs:
+  - name: redis-server
+    release: redis
+variables:
+- name: password
+  type: password
+tags:
+  team: foo
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		rc, err = LoadRuntimeConfigYAML([]byte(`---
+releases:
+- name: redis
+  version: 1.0.0
+- name: os-conf
+  version: 20.0.0
+variables:
+- name: password
+  type: certificate
+- name: syslog_ca
+  type: certificate
+tags:
+  team: bar
+  env: prod
+addons:
+- name: everywhere
+  include:
+    deployments: [foo, bar]
+  jobs:
+  - name: syslog
+    release: os-conf
+- name: other
+  include:
+    deployments: [bar]
+  jobs:
+  - name: other
+    release: os-conf
+- name: not-on-workers
+  exclude:
+    deployments: [foo]
+    instance_groups: [worker]
+  jobs:
+  - name: monitor
+    release: os-conf
+- name: not-on-foo
+  exclude:
+    deployments: [foo]
+  jobs:
+  - name: excluded
+    release: os-conf
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("merges releases, variables and tags, keeping the ones from the manifest", func() {
+		manifest.ApplyRuntimeConfig("foo", rc)
+
+		Expect(manifest.Releases).To(HaveLen(2))
+		Expect(manifest.Releases[0].Version).To(Equal("36.15.0"))
+		Expect(manifest.Releases[1].Name).To(Equal("os-conf"))
+
+		Expect(manifest.Variables).To(HaveLen(2))
+		Expect(manifest.Variables[0].Type).To(Equal("password"))
+		Expect(manifest.Variables[1].Name).To(Equal("syslog_ca"))
+
+		Expect(manifest.Tags).To(Equal(map[string]string{"team": "foo", "env": "prod"}))
+	})
+
+	It("applies addons according to their deployment placement rules", func() {
+		manifest.ApplyRuntimeConfig("foo", rc)
+		Expect(manifest.AddOns).To(HaveLen(2))
+
+		err := manifest.ApplyAddons()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames(manifest.InstanceGroups[0])).To(Equal([]string{"redis-server", "syslog", "monitor"}))
+		Expect(jobNames(manifest.InstanceGroups[1])).To(Equal([]string{"redis-server", "syslog"}))
+	})
+
+	It("doesn't apply exclude rules of other deployments", func() {
+		manifest.ApplyRuntimeConfig("bar", rc)
+		Expect(manifest.AddOns).To(HaveLen(4))
+
+		err := manifest.ApplyAddons()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames(manifest.InstanceGroups[1])).To(Equal([]string{"redis-server", "syslog", "other", "monitor", "excluded"}))
+	})
+
+	It("doesn't change the addons of the runtime config", func() {
+		manifest.ApplyRuntimeConfig("foo", rc)
+
+		Expect(rc.AddOns[0].Include.Deployments).To(Equal([]string{"foo", "bar"}))
+		Expect(rc.AddOns[0].Include.InstanceGroup).To(BeEmpty())
+		Expect(rc.AddOns[2].Include).To(BeNil())
+		Expect(rc.AddOns[2].Exclude.Deployments).To(Equal([]string{"foo"}))
+	})
+
+	It("doesn't place addons of the manifest without include rules", func() {
+		manifest.AddOns = []*AddOn{
+			{Name: "nowhere", Jobs: []AddOnJob{{Name: "syslog", Release: "os-conf"}}},
+		}
+		manifest.ApplyAddOnDeploymentRules("foo")
+		Expect(manifest.AddOns).To(HaveLen(1))
+
+		err := manifest.ApplyAddons()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames(manifest.InstanceGroups[0])).To(Equal([]string{"redis-server"}))
+		Expect(jobNames(manifest.InstanceGroups[1])).To(Equal([]string{"redis-server"}))
+	})
+
+	It("places addons of the manifest, which are only included by deployment, on all instance groups", func() {
+		manifest.AddOns = rc.AddOns[:1]
+		manifest.ApplyAddOnDeploymentRules("foo")
+
+		err := manifest.ApplyAddons()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jobNames(manifest.InstanceGroups[0])).To(Equal([]string{"redis-server", "syslog"}))
+		Expect(jobNames(manifest.InstanceGroups[1])).To(Equal([]string{"redis-server", "syslog"}))
+	})
+
+	It("filters addons of the manifest by deployment", func() {
+		manifest.AddOns = rc.AddOns
+		manifest.ApplyAddOnDeploymentRules("baz")
+
+		Expect(manifest.AddOns).To(HaveLen(2))
+		Expect(manifest.AddOns[0].Name).To(Equal("not-on-workers"))
+		Expect(manifest.AddOns[1].Name).To(Equal("not-on-foo"))
+	})
+})
@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationErrors is a list of semantic errors found in a manifest
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks the manifest for mistakes, which would otherwise only show
+// up when rendering the instance groups. Link providers are not checked, as
+// they can also be provided by secrets, see ListMissingProviders.
+func (m *Manifest) Validate() ValidationErrors {
+	errs := ValidationErrors{}
+
+	releases := map[string]bool{}
+	for _, release := range m.Releases {
+		releases[release.Name] = true
+	}
+
+	stemcells := map[string]bool{}
+	for _, stemcell := range m.Stemcells {
+		stemcells[stemcell.Alias] = true
+	}
+
+	igNames := map[string]bool{}
+	for _, ig := range m.InstanceGroups {
+		if igNames[ig.Name] {
+			errs = append(errs, fmt.Errorf("instance group '%s' is declared more than once", ig.Name))
+		}
+		igNames[ig.Name] = true
+
+		if ig.Stemcell != "" && !stemcells[ig.Stemcell] {
+			errs = append(errs, fmt.Errorf("instance group '%s' uses unknown stemcell alias '%s'", ig.Name, ig.Stemcell))
+		}
+
+		for _, job := range ig.Jobs {
+			if !releases[job.Release] {
+				errs = append(errs, fmt.Errorf("job '%s' in instance group '%s' references undeclared release '%s'", job.Name, ig.Name, job.Release))
+			}
+		}
+	}
+
+	varNames := map[string]bool{}
+	for _, v := range m.Variables {
+		if varNames[v.Name] {
+			errs = append(errs, fmt.Errorf("variable '%s' is declared more than once", v.Name))
+		}
+		varNames[v.Name] = true
+
+		if v.Type == "certificate" && v.Options == nil {
+			errs = append(errs, fmt.Errorf("certificate variable '%s' has no options", v.Name))
+		}
+	}
+
+	return errs
+}
+
+// ListConsumersWithoutProvider returns a description of each consumer,
+// whose provider is neither in the manifest nor in the given list of
+// providers from other sources
+func (m *Manifest) ListConsumersWithoutProvider(providers map[string]bool) []string {
+	missing := m.ListMissingProviders()
+
+	consumers := []string{}
+	for _, ig := range m.InstanceGroups {
+		for _, job := range ig.Jobs {
+			for name, property := range job.Consumes {
+				p, ok := property.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if _, ok := p["deployment"]; ok {
+					continue
+				}
+				from, _ := p["from"].(string)
+				if _, ok := missing[from]; !ok || providers[from] {
+					continue
+				}
+				consumers = append(consumers, fmt.Sprintf("link '%s' of job '%s' in instance group '%s' is consumed from unknown provider '%s'", name, job.Name, ig.Name, from))
+			}
+		}
+	}
+	sort.Strings(consumers)
+
+	return consumers
+}
@@ -0,0 +1,87 @@
+package manifest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+)
+
+var _ = Describe("Validation", func() {
+	var manifest *Manifest
+
+	BeforeEach(func() {
+		var err error
+		manifest, err = LoadYAML([]byte(`---
+releases:
+- name: nats
+  version: "26"
+stemcells:
+- alias: default
+  os: opensuse-42.3
+  version: "30"
+instance_groups:
+- name: nats
+  stemcell: default
+  jobs:
+  - name: nats
+    release: nats
+    provides:
+      nats: {as: nutty-nuts}
+- name: smoke-tests
+  stemcell: default
+  jobs:
+  - name: smoke-tests
+    release: nats
+    consumes:
+      nats: {from: nutty-nuts}
+      database: {from: mysql}
+      other: {from: other, deployment: foo}
+variables:
+- name: ca
+  type: certificate
+  options:
+    is_ca: true
+    common_name: ca
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Validate", func() {
+		It("accepts a valid manifest", func() {
+			Expect(manifest.Validate()).To(BeEmpty())
+		})
+
+		It("reports every error", func() {
+			manifest.InstanceGroups[1].Name = "nats"
+			manifest.InstanceGroups[1].Stemcell = "xenial"
+			manifest.InstanceGroups[1].Jobs[0].Release = "smoke"
+			manifest.Variables = append(manifest.Variables, Variable{Name: "cert", Type: "certificate"})
+
+			errs := manifest.Validate()
+			Expect(errs).To(HaveLen(4))
+			Expect(errs.Error()).To(Equal("instance group 'nats' is declared more than once; " +
+				"instance group 'nats' uses unknown stemcell alias 'xenial'; " +
+				"job 'smoke-tests' in instance group 'nats' references undeclared release 'smoke'; " +
+				"certificate variable 'cert' has no options"))
+		})
+	})
+
+	Describe("ListMissingProviders", func() {
+		It("considers the links of all jobs", func() {
+			Expect(manifest.ListMissingProviders()).To(Equal(map[string]bool{"mysql": false}))
+		})
+	})
+
+	Describe("ListConsumersWithoutProvider", func() {
+		It("lists consumers of missing providers", func() {
+			Expect(manifest.ListConsumersWithoutProvider(nil)).To(ConsistOf(
+				"link 'database' of job 'smoke-tests' in instance group 'smoke-tests' is consumed from unknown provider 'mysql'",
+			))
+		})
+
+		It("ignores providers from other sources", func() {
+			Expect(manifest.ListConsumersWithoutProvider(map[string]bool{"mysql": true})).To(BeEmpty())
+		})
+	})
+})
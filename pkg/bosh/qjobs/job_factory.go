@@ -48,21 +48,17 @@ const (
 )
 
 // JobFactory is a concrete implementation of JobFactory
-type JobFactory struct {
-	Namespace string
-}
+type JobFactory struct{}
 
 // NewJobFactory returns a concrete implementation of JobFactory
-func NewJobFactory(namespace string) *JobFactory {
-	return &JobFactory{
-		Namespace: namespace,
-	}
+func NewJobFactory() *JobFactory {
+	return &JobFactory{}
 }
 
 // VariableInterpolationJob returns an quarks job to create the desired manifest
 // The desired manifest is a BOSH manifest with all variables interpolated.
 // It's sometimes referred to as the 'with-vars' manifest.
-func (f *JobFactory) VariableInterpolationJob(deploymentName string, manifest bdm.Manifest) (*qjv1a1.QuarksJob, error) {
+func (f *JobFactory) VariableInterpolationJob(deploymentName string, namespace string, manifest bdm.Manifest) (*qjv1a1.QuarksJob, error) {
 	args := []string{"util", "variable-interpolation"}
 
 	// This is the source manifest, that still has the '((vars))'
@@ -95,7 +91,7 @@ func (f *JobFactory) VariableInterpolationJob(deploymentName string, manifest bd
 	qJob := &qjv1a1.QuarksJob{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      qJobName,
-			Namespace: f.Namespace,
+			Namespace: namespace,
 			Labels: map[string]string{
 				bdv1.LabelDeploymentName: deploymentName,
 			},
@@ -160,14 +156,15 @@ func (f *JobFactory) VariableInterpolationJob(deploymentName string, manifest bd
 }
 
 // InstanceGroupManifestJob generates the job to create an instance group manifest
-func (f *JobFactory) InstanceGroupManifestJob(deploymentName string, manifest bdm.Manifest, linkInfos converter.LinkInfos, initialRollout bool) (*qjv1a1.QuarksJob, error) {
+func (f *JobFactory) InstanceGroupManifestJob(deploymentName string, namespace string, manifest bdm.Manifest, linkInfos converter.LinkInfos, initialRollout bool, propertyValidation string) (*qjv1a1.QuarksJob, error) {
 	containers := []corev1.Container{}
 	ct := containerTemplate{
-		deploymentName: deploymentName,
-		manifestName:   desiredManifestName(deploymentName),
-		cmd:            "instance-group",
-		namespace:      f.Namespace,
-		initialRollout: initialRollout,
+		deploymentName:     deploymentName,
+		manifestName:       desiredManifestName(deploymentName),
+		cmd:                "instance-group",
+		namespace:          namespace,
+		initialRollout:     initialRollout,
+		propertyValidation: propertyValidation,
 	}
 
 	linkOutputs := map[string]string{}
@@ -184,7 +181,7 @@ func (f *JobFactory) InstanceGroupManifestJob(deploymentName string, manifest bd
 	}
 
 	qJobName := fmt.Sprintf("ig-%s", deploymentName)
-	qJob, err := f.releaseImageQJob(qJobName, deploymentName, manifest, containers, linkInfos.Volumes())
+	qJob, err := f.releaseImageQJob(qJobName, deploymentName, namespace, manifest, containers, linkInfos.Volumes())
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +192,14 @@ func (f *JobFactory) InstanceGroupManifestJob(deploymentName string, manifest bd
 			Name:              secret,
 			PersistenceMethod: qjv1a1.PersistUsingFanOut,
 		}
+		// shared links can be consumed by other deployments
+		qJob.Spec.Output.OutputMap[container][bdm.SharedLinksFilename] = qjv1a1.SecretOptions{
+			Name: names.QuarksLinkSecretName(deploymentName, "shared"),
+			AdditionalSecretLabels: map[string]string{
+				bdv1.LabelSharedLink: "true",
+			},
+			PersistenceMethod: qjv1a1.PersistUsingFanOut,
+		}
 	}
 
 	return qJob, nil
@@ -207,19 +212,25 @@ func desiredManifestName(name string) string {
 }
 
 type containerTemplate struct {
-	deploymentName string
-	manifestName   string
-	cmd            string
-	namespace      string
-	initialRollout bool
+	deploymentName     string
+	manifestName       string
+	cmd                string
+	namespace          string
+	initialRollout     bool
+	propertyValidation string
 }
 
 func (ct *containerTemplate) newUtilContainer(instanceGroupName string, linkVolumeMounts []corev1.VolumeMount) corev1.Container {
+	args := []string{"util", ct.cmd, "--initial-rollout", strconv.FormatBool(ct.initialRollout)}
+	if ct.propertyValidation != "" {
+		args = append(args, "--property-validation", ct.propertyValidation)
+	}
+
 	return corev1.Container{
 		Name:            names.Sanitize(instanceGroupName),
 		Image:           operatorimage.GetOperatorDockerImage(),
 		ImagePullPolicy: operatorimage.GetOperatorImagePullPolicy(),
-		Args:            []string{"util", ct.cmd, "--initial-rollout", strconv.FormatBool(ct.initialRollout)},
+		Args:            args,
 		VolumeMounts: append(linkVolumeMounts, []corev1.VolumeMount{
 			manifestVolumeMount(ct.manifestName),
 			releaseSourceVolumeMount(),
@@ -258,7 +269,7 @@ func (ct *containerTemplate) newUtilContainer(instanceGroupName string, linkVolu
 }
 
 // releaseImageQJob collects outputs, like bpm, links or ig manifests, from the BOSH release images
-func (f *JobFactory) releaseImageQJob(name string, deploymentName string, manifest bdm.Manifest, containers []corev1.Container, linkVolumes []corev1.Volume) (*qjv1a1.QuarksJob, error) {
+func (f *JobFactory) releaseImageQJob(name string, deploymentName string, namespace string, manifest bdm.Manifest, containers []corev1.Container, linkVolumes []corev1.Volume) (*qjv1a1.QuarksJob, error) {
 	initContainers := []corev1.Container{}
 	doneSpecCopyingReleases := map[string]bool{}
 	for _, ig := range manifest.InstanceGroups {
@@ -313,7 +324,7 @@ func (f *JobFactory) releaseImageQJob(name string, deploymentName string, manife
 	qJob := &qjv1a1.QuarksJob{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
-			Namespace: f.Namespace,
+			Namespace: namespace,
 			Labels: map[string]string{
 				bdv1.LabelDeploymentName: deploymentName,
 			},
@@ -26,12 +26,12 @@ var _ = Describe("JobFactory", func() {
 		m, err = env.DefaultBOSHManifest()
 		linkInfos = LinkInfos{}
 		Expect(err).NotTo(HaveOccurred())
-		factory = qjobs.NewJobFactory("namespace")
+		factory = qjobs.NewJobFactory()
 	})
 
 	Describe("InstanceGroupManifestJob", func() {
 		It("creates init containers", func() {
-			qJob, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			qJob, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).ToNot(HaveOccurred())
 			jobIG := qJob.Spec.Template.Spec
 			// Test init containers in the ig manifest qJob
@@ -41,6 +41,14 @@ var _ = Describe("JobFactory", func() {
 			Expect(jobIG.Template.Spec.InitContainers[1].VolumeMounts[0].MountPath).To(Equal("/var/vcap/all-releases"))
 		})
 
+		It("passes the property validation mode to the containers", func() {
+			qJob, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "strict")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(qJob.Spec.Template.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+				"util", "instance-group", "--initial-rollout", "true", "--property-validation", "strict",
+			}))
+		})
+
 		It("creates relative volume infos when having link secrets", func() {
 			linkInfos = LinkInfos{
 				{
@@ -49,7 +57,7 @@ var _ = Describe("JobFactory", func() {
 				},
 			}
 
-			qJob, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			qJob, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).ToNot(HaveOccurred())
 			jobIG := qJob.Spec.Template.Spec
 			// Test init containers in the ig manifest qJob
@@ -65,14 +73,14 @@ var _ = Describe("JobFactory", func() {
 
 		It("handles an error when getting release image", func() {
 			m.Stemcells = nil
-			_, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			_, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Generation of gathering job failed for manifest"))
 		})
 
 		It("does not generate the instance group containers when its instances is zero", func() {
 			m.InstanceGroups[0].Instances = 0
-			qJob, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			qJob, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).ToNot(HaveOccurred())
 			jobIG := qJob.Spec.Template.Spec
 			Expect(len(jobIG.Template.Spec.InitContainers)).To(BeNumerically("<", 2))
@@ -83,7 +91,7 @@ var _ = Describe("JobFactory", func() {
 			It("creates output entries for all provides", func() {
 				m, err = env.ElaboratedBOSHManifest()
 				Expect(err).NotTo(HaveOccurred())
-				qJob, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+				qJob, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 				Expect(err).ToNot(HaveOccurred())
 				om := qJob.Spec.Output.OutputMap
 				Expect(om).To(Equal(
@@ -107,6 +115,12 @@ var _ = Describe("JobFactory", func() {
 								Versioned:              false,
 								PersistenceMethod:      "fan-out",
 							},
+							"shared-links.json": qjv1a1.SecretOptions{
+								Name:                   "link-foo-deployment-shared",
+								AdditionalSecretLabels: map[string]string{"quarks.cloudfoundry.org/shared-link": "true"},
+								Versioned:              false,
+								PersistenceMethod:      "fan-out",
+							},
 						},
 						"diego-cell": qjv1a1.FilesToSecrets{
 							"ig.json": qjv1a1.SecretOptions{
@@ -127,6 +141,12 @@ var _ = Describe("JobFactory", func() {
 								Versioned:              false,
 								PersistenceMethod:      "fan-out",
 							},
+							"shared-links.json": qjv1a1.SecretOptions{
+								Name:                   "link-foo-deployment-shared",
+								AdditionalSecretLabels: map[string]string{"quarks.cloudfoundry.org/shared-link": "true"},
+								Versioned:              false,
+								PersistenceMethod:      "fan-out",
+							},
 						},
 					},
 				))
@@ -134,7 +154,7 @@ var _ = Describe("JobFactory", func() {
 		})
 
 		It("has one spec-copier init container per instance group", func() {
-			job, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			job, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).ToNot(HaveOccurred())
 
 			spec := job.Spec.Template.Spec.Template.Spec
@@ -145,7 +165,7 @@ var _ = Describe("JobFactory", func() {
 		})
 
 		It("has one bpm-configs container per instance group", func() {
-			job, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			job, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).ToNot(HaveOccurred())
 
 			spec := job.Spec.Template.Spec.Template.Spec
@@ -156,7 +176,7 @@ var _ = Describe("JobFactory", func() {
 
 		It("does not generate the instance group containers when its instances is zero", func() {
 			m.InstanceGroups[0].Instances = 0
-			job, err := factory.InstanceGroupManifestJob(deploymentName, *m, linkInfos, true)
+			job, err := factory.InstanceGroupManifestJob(deploymentName, "namespace", *m, linkInfos, true, "")
 			Expect(err).ToNot(HaveOccurred())
 
 			spec := job.Spec.Template.Spec.Template.Spec
@@ -167,7 +187,7 @@ var _ = Describe("JobFactory", func() {
 
 	Describe("VariableInterpolationJob", func() {
 		It("mounts variable secrets in the variable interpolation container", func() {
-			job, err := factory.VariableInterpolationJob(deploymentName, *m)
+			job, err := factory.VariableInterpolationJob(deploymentName, "namespace", *m)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(job.GetLabels()).To(HaveKeyWithValue(manifest.LabelDeploymentName, deploymentName))
 
@@ -44,11 +44,13 @@ var (
 							Type: "object",
 							Properties: map[string]extv1.JSONSchemaProps{
 								"name": {
-									Type:      "string",
-									MinLength: pointers.Int64(1),
+									Type:        "string",
+									MinLength:   pointers.Int64(1),
+									Description: "The name of the config map or secret, or the URL of the manifest",
 								},
 								"type": {
-									Type: "string",
+									Type:        "string",
+									Description: "The type of the reference: configmap, secret, url",
 									Enum: []extv1.JSON{
 										{
 											Raw: []byte(`"configmap"`),
@@ -68,17 +70,20 @@ var (
 							},
 						},
 						"ops": {
-							Type: "array",
+							Type:        "array",
+							Description: "Ops files applied to the manifest, in order",
 							Items: &extv1.JSONSchemaPropsOrArray{
 								Schema: &extv1.JSONSchemaProps{
 									Type: "object",
 									Properties: map[string]extv1.JSONSchemaProps{
 										"name": {
-											Type:      "string",
-											MinLength: pointers.Int64(1),
+											Type:        "string",
+											MinLength:   pointers.Int64(1),
+											Description: "The name of the config map or secret, or the URL of the ops file",
 										},
 										"type": {
-											Type: "string",
+											Type:        "string",
+											Description: "The type of the reference: configmap, secret, url",
 											Enum: []extv1.JSON{
 												{
 													Raw: []byte(`"configmap"`),
@@ -99,6 +104,71 @@ var (
 								},
 							},
 						},
+						"errands": {
+							Type:        "array",
+							Description: "Errand instance groups of the manifest",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name": {
+											Type:      "string",
+											MinLength: pointers.Int64(1),
+										},
+										"schedule": {
+											Type:        "string",
+											Description: "Schedule in cron format, e.g. '0 3 * * *'. Errands without a schedule only run on demand.",
+										},
+									},
+									Required: []string{
+										"name",
+									},
+								},
+							},
+						},
+						"dependsOn": {
+							Type:        "array",
+							Description: "BOSHDeployments of the same namespace, which have to be ready before this deployment is reconciled",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name": {
+											Type:      "string",
+											MinLength: pointers.Int64(1),
+										},
+										"readiness": {
+											Type:        "string",
+											Description: "The state the dependency needs to reach: deployed, ready",
+											Default:     &extv1.JSON{Raw: []byte(`"ready"`)},
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"deployed"`),
+												},
+												{
+													Raw: []byte(`"ready"`),
+												},
+											},
+										},
+									},
+									Required: []string{
+										"name",
+									},
+								},
+							},
+						},
+						"propertyValidation": {
+							Type:        "string",
+							Description: "Checks the job properties of the manifest against the job specs of the releases: warn, strict. It's disabled by default.",
+							Enum: []extv1.JSON{
+								{
+									Raw: []byte(`"warn"`),
+								},
+								{
+									Raw: []byte(`"strict"`),
+								},
+							},
+						},
 					},
 					Required: []string{
 						"manifest",
@@ -108,7 +178,71 @@ var (
 					Type: "object",
 					Properties: map[string]extv1.JSONSchemaProps{
 						"lastReconcile": {
-							Type: "string",
+							Type:        "string",
+							Format:      "date-time",
+							Nullable:    true,
+							Description: "Timestamp for the last reconcile",
+						},
+						"postDeployFailures": {
+							Type:        "array",
+							Description: "Failed post-deploy script executions of the last rollout of each instance group",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"instanceGroup": {Type: "string"},
+										"job":           {Type: "string"},
+										"pod":           {Type: "string"},
+										"message":       {Type: "string"},
+										"timestamp":     {Type: "string", Format: "date-time", Nullable: true},
+									},
+								},
+							},
+						},
+						"errands": {
+							Type:        "array",
+							Description: "Run history of the errands",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name":             {Type: "string"},
+										"lastScheduleTime": {Type: "string", Format: "date-time", Nullable: true},
+										"runs": {
+											Type: "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"trigger":        {Type: "string"},
+														"pod":            {Type: "string"},
+														"startTime":      {Type: "string", Format: "date-time", Nullable: true},
+														"completionTime": {Type: "string", Format: "date-time", Nullable: true},
+														"exitCode":       {Type: "integer", Format: "int32"},
+														"logTail":        {Type: "string"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						"conditions": {
+							Type:        "array",
+							Description: "Conditions of the deployment",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"type":               {Type: "string"},
+										"status":             {Type: "string"},
+										"lastTransitionTime": {Type: "string", Format: "date-time", Nullable: true},
+										"reason":             {Type: "string"},
+										"message":            {Type: "string"},
+									},
+								},
+							},
 						},
 					},
 				},
@@ -116,6 +250,33 @@ var (
 		},
 	}
 
+	// BOSHDeploymentAdditionalPrinterColumns are the columns shown by `kubectl get boshdeployments`
+	BOSHDeploymentAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Manifest",
+			Type:        "string",
+			Description: "The name of the manifest reference",
+			JSONPath:    ".spec.manifest.name",
+		},
+		{
+			Name:        "Dependencies Ready",
+			Type:        "string",
+			Description: "Whether the deployments this deployment depends on are ready",
+			JSONPath:    `.status.conditions[?(@.type=="DependenciesReady")].status`,
+		},
+		{
+			Name:     "Last Reconcile",
+			Type:     "date",
+			JSONPath: ".status.lastReconcile",
+			Priority: 1,
+		},
+		{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
 	// BOSHDeploymentResourceName is the resource name of BOSHDeployment
 	BOSHDeploymentResourceName = fmt.Sprintf("%s.%s", BOSHDeploymentResourcePlural, apis.GroupName)
 
@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"code.cloudfoundry.org/cf-operator/pkg/kube/apis"
@@ -26,6 +27,7 @@ const (
 
 	ManifestSpecName        string = "manifest"
 	OpsSpecName             string = "ops"
+	RuntimeConfigSpecName   string = "runtime-config"
 	ImplicitVariableKeyName string = "value"
 )
 
@@ -34,16 +36,80 @@ var (
 	LabelDeploymentName = fmt.Sprintf("%s/deployment-name", apis.GroupName)
 	// LabelDeploymentSecretType is the label key for secret type
 	LabelDeploymentSecretType = fmt.Sprintf("%s/secret-type", apis.GroupName)
+	// LabelRuntimeConfig marks config maps containing a BOSH runtime config, which is applied to all deployments in the namespace, if set to "true"
+	LabelRuntimeConfig = fmt.Sprintf("%s/runtime-config", apis.GroupName)
+	// LabelSharedLink marks secrets of links provided with `shared: true`, which can be consumed by other deployments
+	LabelSharedLink = fmt.Sprintf("%s/shared-link", apis.GroupName)
+	// LabelLinkProviderDeployment is the label key for the deployment, which provides a link consumed from another deployment
+	LabelLinkProviderDeployment = fmt.Sprintf("%s/link-provider-deployment", apis.GroupName)
+	// AnnotationLinkConsumerNamespaces is the comma separated list of other namespaces, whose deployments may consume the shared links of a deployment
+	AnnotationLinkConsumerNamespaces = fmt.Sprintf("%s/link-consumer-namespaces", apis.GroupName)
 	// AnnotationLinkProvidesKey is the key for the quarks links 'provides' JSON
 	AnnotationLinkProvidesKey = fmt.Sprintf("%s/provides", apis.GroupName)
 	// AnnotationLinkProviderService is the annotation key used on services to identify the link provider
 	AnnotationLinkProviderService = fmt.Sprintf("%s/link-provider-name", apis.GroupName)
+	// AnnotationRunErrand is the annotation key used to run errands on demand. Its value is a comma separated list of errand names.
+	AnnotationRunErrand = fmt.Sprintf("%s/run-errand", apis.GroupName)
+)
+
+// Valid values for the trigger of an errand run
+const (
+	// ErrandTriggerAnnotation runs were requested by the run-errand annotation
+	ErrandTriggerAnnotation = "annotation"
+	// ErrandTriggerSchedule runs were started by the errand's schedule
+	ErrandTriggerSchedule = "schedule"
+	// ErrandTriggerExternal runs were triggered on the QuarksJob directly
+	ErrandTriggerExternal = "external"
 )
 
 // BOSHDeploymentSpec defines the desired state of BOSHDeployment
 type BOSHDeploymentSpec struct {
 	Manifest ResourceReference   `json:"manifest"`
 	Ops      []ResourceReference `json:"ops,omitempty"`
+	Errands  []ErrandSpec        `json:"errands,omitempty"`
+	// DependsOn lists BOSHDeployments of the same namespace, which have to
+	// be ready before this deployment is reconciled
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+	// PropertyValidation checks the job properties of the manifest against
+	// the job specs of the releases. It's disabled by default.
+	PropertyValidation PropertyValidationMode `json:"propertyValidation,omitempty"`
+}
+
+// PropertyValidationMode is the way findings of the job property validation are reported
+type PropertyValidationMode = string
+
+// Valid values for the property validation
+const (
+	// PropertyValidationWarn emits the findings as events on the BOSHDeployment
+	PropertyValidationWarn PropertyValidationMode = "warn"
+	// PropertyValidationStrict fails the instance group resolution, if there are findings
+	PropertyValidationStrict PropertyValidationMode = "strict"
+)
+
+// DependencyReadiness is the state a dependency needs to reach
+type DependencyReadiness = string
+
+// Valid values for the readiness of a dependency
+const (
+	// DependencyDeployed requires the dependency to be reconciled
+	DependencyDeployed DependencyReadiness = "deployed"
+	// DependencyReady requires all instance groups of the dependency to be ready. It's the default.
+	DependencyReady DependencyReadiness = "ready"
+)
+
+// Dependency is a BOSHDeployment, which has to reach a readiness before the
+// dependent BOSHDeployment is reconciled
+type Dependency struct {
+	Name      string              `json:"name"`
+	Readiness DependencyReadiness `json:"readiness,omitempty"`
+}
+
+// ErrandSpec configures an errand instance group of the manifest
+type ErrandSpec struct {
+	Name string `json:"name"`
+	// Schedule in cron format, e.g. '0 3 * * *'. Errands without a schedule
+	// only run on demand.
+	Schedule string `json:"schedule,omitempty"`
 }
 
 // ResourceReference defines the resource reference type and location
@@ -56,6 +122,58 @@ type ResourceReference struct {
 type BOSHDeploymentStatus struct {
 	// Timestamp for the last reconcile
 	LastReconcile *metav1.Time `json:"lastReconcile"`
+	// Failed post-deploy script executions of the last rollout of each instance group
+	PostDeployFailures []PostDeployFailure `json:"postDeployFailures,omitempty"`
+	// Run history of the errands
+	Errands []ErrandStatus `json:"errands,omitempty"`
+	// Conditions of the deployment
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ConditionType is the type of a BOSHDeployment condition
+type ConditionType = string
+
+// Valid values for the type of a condition
+const (
+	// ConditionDependenciesReady is true, once the BOSHDeployments the
+	// deployment depends on and its link providers are ready
+	ConditionDependenciesReady ConditionType = "DependenciesReady"
+)
+
+// Condition describes an aspect of the state of a BOSHDeployment
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// ErrandStatus records the most recent runs of an errand
+type ErrandStatus struct {
+	Name             string       `json:"name"`
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	Runs             []ErrandRun  `json:"runs,omitempty"`
+}
+
+// ErrandRun is a run of an errand's QuarksJob. The completion time, exit code
+// and log tail are set, once the errand's pod terminated.
+type ErrandRun struct {
+	Trigger        string       `json:"trigger"`
+	Pod            string       `json:"pod,omitempty"`
+	StartTime      metav1.Time  `json:"startTime"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	ExitCode       *int32       `json:"exitCode,omitempty"`
+	LogTail        string       `json:"logTail,omitempty"`
+}
+
+// PostDeployFailure records a failed execution of a BOSH job's post-deploy script
+type PostDeployFailure struct {
+	InstanceGroup string      `json:"instanceGroup"`
+	Job           string      `json:"job"`
+	Pod           string      `json:"pod"`
+	Message       string      `json:"message"`
+	Timestamp     metav1.Time `json:"timestamp"`
 }
 
 // +genclient
@@ -79,3 +197,29 @@ type BOSHDeploymentList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []BOSHDeployment `json:"items"`
 }
+
+// SetCondition adds or updates the condition of the same type. The
+// transition time is kept, if the status didn't change.
+func (s *BOSHDeploymentStatus) SetCondition(condition Condition) {
+	for i, c := range s.Conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+		s.Conditions[i] = condition
+		return
+	}
+	s.Conditions = append(s.Conditions, condition)
+}
+
+// GetCondition returns the condition of the type, or nil if it isn't set
+func (s *BOSHDeploymentStatus) GetCondition(conditionType ConditionType) *Condition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+)
+
+// ConvertTo converts this BOSHDeployment to the hub version v1alpha1
+func (src *BOSHDeployment) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.BOSHDeployment)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = v1alpha1.BOSHDeploymentSpec{
+		Manifest:           v1alpha1.ResourceReference{Name: src.Spec.Manifest.Name, Type: string(src.Spec.Manifest.Type)},
+		PropertyValidation: string(src.Spec.PropertyValidation),
+	}
+	for _, ops := range src.Spec.Ops {
+		dst.Spec.Ops = append(dst.Spec.Ops, v1alpha1.ResourceReference{Name: ops.Name, Type: string(ops.Type)})
+	}
+	for _, errand := range src.Spec.Errands {
+		dst.Spec.Errands = append(dst.Spec.Errands, v1alpha1.ErrandSpec(errand))
+	}
+	for _, dependency := range src.Spec.DependsOn {
+		dst.Spec.DependsOn = append(dst.Spec.DependsOn, v1alpha1.Dependency{Name: dependency.Name, Readiness: string(dependency.Readiness)})
+	}
+
+	dst.Status = v1alpha1.BOSHDeploymentStatus{
+		LastReconcile: src.Status.LastReconcile,
+	}
+	for _, failure := range src.Status.PostDeployFailures {
+		dst.Status.PostDeployFailures = append(dst.Status.PostDeployFailures, v1alpha1.PostDeployFailure{
+			InstanceGroup: failure.InstanceGroup,
+			Job:           failure.Job,
+			Pod:           failure.Pod,
+			Message:       failure.Message,
+			Timestamp:     fromTimePointer(failure.Timestamp),
+		})
+	}
+	for _, errand := range src.Status.Errands {
+		status := v1alpha1.ErrandStatus{
+			Name:             errand.Name,
+			LastScheduleTime: errand.LastScheduleTime,
+		}
+		for _, run := range errand.Runs {
+			status.Runs = append(status.Runs, v1alpha1.ErrandRun{
+				Trigger:        string(run.Trigger),
+				Pod:            run.Pod,
+				StartTime:      fromTimePointer(run.StartTime),
+				CompletionTime: run.CompletionTime,
+				ExitCode:       run.ExitCode,
+				LogTail:        run.LogTail,
+			})
+		}
+		dst.Status.Errands = append(dst.Status.Errands, status)
+	}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, v1alpha1.Condition{
+			Type:               string(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: fromTimePointer(condition.LastTransitionTime),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the hub version v1alpha1 to this version
+func (dst *BOSHDeployment) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.BOSHDeployment)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = BOSHDeploymentSpec{
+		Manifest:           ResourceReference{Name: src.Spec.Manifest.Name, Type: ReferenceType(src.Spec.Manifest.Type)},
+		PropertyValidation: PropertyValidationMode(src.Spec.PropertyValidation),
+	}
+	for _, ops := range src.Spec.Ops {
+		dst.Spec.Ops = append(dst.Spec.Ops, ResourceReference{Name: ops.Name, Type: ReferenceType(ops.Type)})
+	}
+	for _, errand := range src.Spec.Errands {
+		dst.Spec.Errands = append(dst.Spec.Errands, ErrandSpec(errand))
+	}
+	for _, dependency := range src.Spec.DependsOn {
+		dst.Spec.DependsOn = append(dst.Spec.DependsOn, Dependency{Name: dependency.Name, Readiness: DependencyReadiness(dependency.Readiness)})
+	}
+
+	dst.Status = BOSHDeploymentStatus{
+		LastReconcile: src.Status.LastReconcile,
+	}
+	for _, failure := range src.Status.PostDeployFailures {
+		dst.Status.PostDeployFailures = append(dst.Status.PostDeployFailures, PostDeployFailure{
+			InstanceGroup: failure.InstanceGroup,
+			Job:           failure.Job,
+			Pod:           failure.Pod,
+			Message:       failure.Message,
+			Timestamp:     toTimePointer(failure.Timestamp),
+		})
+	}
+	for _, errand := range src.Status.Errands {
+		status := ErrandStatus{
+			Name:             errand.Name,
+			LastScheduleTime: errand.LastScheduleTime,
+		}
+		for _, run := range errand.Runs {
+			status.Runs = append(status.Runs, ErrandRun{
+				Trigger:        ErrandTrigger(run.Trigger),
+				Pod:            run.Pod,
+				StartTime:      toTimePointer(run.StartTime),
+				CompletionTime: run.CompletionTime,
+				ExitCode:       run.ExitCode,
+				LogTail:        run.LogTail,
+			})
+		}
+		dst.Status.Errands = append(dst.Status.Errands, status)
+	}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, Condition{
+			Type:               ConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: toTimePointer(condition.LastTransitionTime),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+
+	return nil
+}
+
+// toTimePointer returns nil for the zero time, which v1alpha1 serializes as null
+func toTimePointer(t metav1.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func fromTimePointer(t *metav1.Time) metav1.Time {
+	if t == nil {
+		return metav1.Time{}
+	}
+	return *t
+}
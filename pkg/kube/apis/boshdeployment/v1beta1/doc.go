@@ -0,0 +1,6 @@
+// This file is required so that the DeepCopy implementation is generated
+
+// +k8s:deepcopy-gen=package
+// +groupName=quarks.cloudfoundry.org
+
+package v1beta1
@@ -0,0 +1,289 @@
+package v1beta1
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apis "code.cloudfoundry.org/cf-operator/pkg/kube/apis"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+)
+
+// This file looks almost the same for all controllers
+// Modify the addKnownTypes function, then run `make generate`
+
+var (
+	schemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+	// AddToScheme is used for schema registrations in the controller package
+	// and also in the generated kube code
+	AddToScheme = schemeBuilder.AddToScheme
+
+	// BOSHDeploymentValidation is the validation schema for the v1beta1 BOSHDeployment
+	BOSHDeploymentValidation = extv1.CustomResourceValidation{
+		OpenAPIV3Schema: &extv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"manifest": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"name": {
+									Type:        "string",
+									MinLength:   pointers.Int64(1),
+									Description: "The name of the config map or secret, or the URL of the manifest",
+								},
+								"type": {
+									Type:        "string",
+									Description: "The type of the reference: configmap, secret, url",
+									Enum: []extv1.JSON{
+										{
+											Raw: []byte(`"configmap"`),
+										},
+										{
+											Raw: []byte(`"secret"`),
+										},
+										{
+											Raw: []byte(`"url"`),
+										},
+									},
+								},
+							},
+							Required: []string{
+								"type",
+								"name",
+							},
+						},
+						"ops": {
+							Type:        "array",
+							Description: "Ops files applied to the manifest, in order",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name": {
+											Type:        "string",
+											MinLength:   pointers.Int64(1),
+											Description: "The name of the config map or secret, or the URL of the ops file",
+										},
+										"type": {
+											Type:        "string",
+											Description: "The type of the reference: configmap, secret, url",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"configmap"`),
+												},
+												{
+													Raw: []byte(`"secret"`),
+												},
+												{
+													Raw: []byte(`"url"`),
+												},
+											},
+										},
+									},
+									Required: []string{
+										"type",
+										"name",
+									},
+								},
+							},
+						},
+						"errands": {
+							Type:        "array",
+							Description: "Errand instance groups of the manifest",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name": {
+											Type:      "string",
+											MinLength: pointers.Int64(1),
+										},
+										"schedule": {
+											Type:        "string",
+											Description: "Schedule in cron format, e.g. '0 3 * * *'. Errands without a schedule only run on demand.",
+										},
+									},
+									Required: []string{
+										"name",
+									},
+								},
+							},
+						},
+						"dependsOn": {
+							Type:        "array",
+							Description: "BOSHDeployments of the same namespace, which have to be ready before this deployment is reconciled",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name": {
+											Type:      "string",
+											MinLength: pointers.Int64(1),
+										},
+										"readiness": {
+											Type:        "string",
+											Description: "The state the dependency needs to reach: deployed, ready",
+											Default:     &extv1.JSON{Raw: []byte(`"ready"`)},
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"deployed"`),
+												},
+												{
+													Raw: []byte(`"ready"`),
+												},
+											},
+										},
+									},
+									Required: []string{
+										"name",
+									},
+								},
+							},
+						},
+						"propertyValidation": {
+							Type:        "string",
+							Description: "Checks the job properties of the manifest against the job specs of the releases: warn, strict. It's disabled by default.",
+							Enum: []extv1.JSON{
+								{
+									Raw: []byte(`"warn"`),
+								},
+								{
+									Raw: []byte(`"strict"`),
+								},
+							},
+						},
+					},
+					Required: []string{
+						"manifest",
+					},
+				},
+				"status": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"lastReconcile": {
+							Type:        "string",
+							Format:      "date-time",
+							Description: "Timestamp for the last reconcile",
+						},
+						"postDeployFailures": {
+							Type:        "array",
+							Description: "Failed post-deploy script executions of the last rollout of each instance group",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"instanceGroup": {Type: "string"},
+										"job":           {Type: "string"},
+										"pod":           {Type: "string"},
+										"message":       {Type: "string"},
+										"timestamp":     {Type: "string", Format: "date-time"},
+									},
+								},
+							},
+						},
+						"errands": {
+							Type:        "array",
+							Description: "Run history of the errands",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"name":             {Type: "string"},
+										"lastScheduleTime": {Type: "string", Format: "date-time"},
+										"runs": {
+											Type: "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"trigger": {
+															Type: "string",
+															Enum: []extv1.JSON{
+																{
+																	Raw: []byte(`"annotation"`),
+																},
+																{
+																	Raw: []byte(`"schedule"`),
+																},
+																{
+																	Raw: []byte(`"external"`),
+																},
+															},
+														},
+														"pod":            {Type: "string"},
+														"startTime":      {Type: "string", Format: "date-time"},
+														"completionTime": {Type: "string", Format: "date-time"},
+														"exitCode":       {Type: "integer", Format: "int32"},
+														"logTail":        {Type: "string"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						"conditions": {
+							Type:        "array",
+							Description: "Conditions of the deployment",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"type": {Type: "string"},
+										"status": {
+											Type: "string",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"True"`),
+												},
+												{
+													Raw: []byte(`"False"`),
+												},
+												{
+													Raw: []byte(`"Unknown"`),
+												},
+											},
+										},
+										"lastTransitionTime": {Type: "string", Format: "date-time"},
+										"reason":             {Type: "string"},
+										"message":            {Type: "string"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: apis.GroupName, Version: "v1beta1"}
+)
+
+// Kind takes an unqualified kind and returns back a Group qualified GroupKind
+func Kind(kind string) schema.GroupKind {
+	return SchemeGroupVersion.WithKind(kind).GroupKind()
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+// Adds the list of known types to Scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&BOSHDeployment{},
+		&BOSHDeploymentList{},
+	)
+
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
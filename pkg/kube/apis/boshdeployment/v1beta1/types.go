@@ -0,0 +1,172 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file is safe to edit
+// It's used as input for the Kube code generator
+// Run "make generate" after modifying this file
+
+// ReferenceType lists all the types of Reference we can supports
+type ReferenceType string
+
+// Valid values for ref types
+const (
+	// ConfigMapReference represents ConfigMap reference
+	ConfigMapReference ReferenceType = "configmap"
+	// SecretReference represents Secret reference
+	SecretReference ReferenceType = "secret"
+	// URLReference represents URL reference
+	URLReference ReferenceType = "url"
+)
+
+// BOSHDeploymentSpec defines the desired state of BOSHDeployment
+type BOSHDeploymentSpec struct {
+	Manifest ResourceReference   `json:"manifest"`
+	Ops      []ResourceReference `json:"ops,omitempty"`
+	Errands  []ErrandSpec        `json:"errands,omitempty"`
+	// DependsOn lists BOSHDeployments of the same namespace, which have to
+	// be ready before this deployment is reconciled
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+	// PropertyValidation checks the job properties of the manifest against
+	// the job specs of the releases. It's disabled by default.
+	PropertyValidation PropertyValidationMode `json:"propertyValidation,omitempty"`
+}
+
+// PropertyValidationMode is the way findings of the job property validation are reported
+type PropertyValidationMode string
+
+// Valid values for the property validation
+const (
+	// PropertyValidationWarn emits the findings as events on the BOSHDeployment
+	PropertyValidationWarn PropertyValidationMode = "warn"
+	// PropertyValidationStrict fails the instance group resolution, if there are findings
+	PropertyValidationStrict PropertyValidationMode = "strict"
+)
+
+// DependencyReadiness is the state a dependency needs to reach
+type DependencyReadiness string
+
+// Valid values for the readiness of a dependency
+const (
+	// DependencyDeployed requires the dependency to be reconciled
+	DependencyDeployed DependencyReadiness = "deployed"
+	// DependencyReady requires all instance groups of the dependency to be ready. It's the default.
+	DependencyReady DependencyReadiness = "ready"
+)
+
+// Dependency is a BOSHDeployment, which has to reach a readiness before the
+// dependent BOSHDeployment is reconciled
+type Dependency struct {
+	Name      string              `json:"name"`
+	Readiness DependencyReadiness `json:"readiness,omitempty"`
+}
+
+// ErrandSpec configures an errand instance group of the manifest
+type ErrandSpec struct {
+	Name string `json:"name"`
+	// Schedule in cron format, e.g. '0 3 * * *'. Errands without a schedule
+	// only run on demand.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// ResourceReference defines the resource reference type and location
+type ResourceReference struct {
+	Name string        `json:"name"`
+	Type ReferenceType `json:"type"`
+}
+
+// BOSHDeploymentStatus defines the observed state of BOSHDeployment
+type BOSHDeploymentStatus struct {
+	// Timestamp for the last reconcile
+	LastReconcile *metav1.Time `json:"lastReconcile,omitempty"`
+	// Failed post-deploy script executions of the last rollout of each instance group
+	PostDeployFailures []PostDeployFailure `json:"postDeployFailures,omitempty"`
+	// Run history of the errands
+	Errands []ErrandStatus `json:"errands,omitempty"`
+	// Conditions of the deployment
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ConditionType is the type of a BOSHDeployment condition
+type ConditionType string
+
+// Valid values for the type of a condition
+const (
+	// ConditionDependenciesReady is true, once the BOSHDeployments the
+	// deployment depends on and its link providers are ready
+	ConditionDependenciesReady ConditionType = "DependenciesReady"
+)
+
+// Condition describes an aspect of the state of a BOSHDeployment
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime *metav1.Time           `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// ErrandTrigger is what started an errand run
+type ErrandTrigger string
+
+// Valid values for the trigger of an errand run
+const (
+	// ErrandTriggerAnnotation runs were requested by the run-errand annotation
+	ErrandTriggerAnnotation ErrandTrigger = "annotation"
+	// ErrandTriggerSchedule runs were started by the errand's schedule
+	ErrandTriggerSchedule ErrandTrigger = "schedule"
+	// ErrandTriggerExternal runs were triggered on the QuarksJob directly
+	ErrandTriggerExternal ErrandTrigger = "external"
+)
+
+// ErrandStatus records the most recent runs of an errand
+type ErrandStatus struct {
+	Name             string       `json:"name"`
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	Runs             []ErrandRun  `json:"runs,omitempty"`
+}
+
+// ErrandRun is a run of an errand's QuarksJob. The completion time, exit code
+// and log tail are set, once the errand's pod terminated.
+type ErrandRun struct {
+	Trigger        ErrandTrigger `json:"trigger"`
+	Pod            string        `json:"pod,omitempty"`
+	StartTime      *metav1.Time  `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time  `json:"completionTime,omitempty"`
+	ExitCode       *int32        `json:"exitCode,omitempty"`
+	LogTail        string        `json:"logTail,omitempty"`
+}
+
+// PostDeployFailure records a failed execution of a BOSH job's post-deploy script
+type PostDeployFailure struct {
+	InstanceGroup string       `json:"instanceGroup"`
+	Job           string       `json:"job"`
+	Pod           string       `json:"pod"`
+	Message       string       `json:"message,omitempty"`
+	Timestamp     *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BOSHDeployment is the Schema for the boshdeployments API
+// +k8s:openapi-gen=true
+type BOSHDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BOSHDeploymentSpec   `json:"spec,omitempty"`
+	Status BOSHDeploymentStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BOSHDeploymentList contains a list of BOSHDeployment
+type BOSHDeploymentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BOSHDeployment `json:"items"`
+}
@@ -0,0 +1,154 @@
+package apis_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	certv1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	bdv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1beta1"
+	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	qsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1beta1"
+	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	qstsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1beta1"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+)
+
+var _ = Describe("Conversion", func() {
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	meta := metav1.ObjectMeta{Name: "foo", Namespace: "default", Labels: map[string]string{"app": "foo"}}
+
+	Context("for BOSHDeployments", func() {
+		hub := &bdv1.BOSHDeployment{
+			ObjectMeta: meta,
+			Spec: bdv1.BOSHDeploymentSpec{
+				Manifest:           bdv1.ResourceReference{Name: "manifest", Type: bdv1.ConfigMapReference},
+				Ops:                []bdv1.ResourceReference{{Name: "ops", Type: bdv1.SecretReference}},
+				Errands:            []bdv1.ErrandSpec{{Name: "smoke-tests", Schedule: "0 3 * * *"}},
+				DependsOn:          []bdv1.Dependency{{Name: "database", Readiness: bdv1.DependencyReady}},
+				PropertyValidation: bdv1.PropertyValidationWarn,
+			},
+			Status: bdv1.BOSHDeploymentStatus{
+				LastReconcile: &now,
+				PostDeployFailures: []bdv1.PostDeployFailure{
+					{InstanceGroup: "api", Job: "cc", Pod: "api-0", Message: "failed", Timestamp: now},
+				},
+				Errands: []bdv1.ErrandStatus{
+					{
+						Name:             "smoke-tests",
+						LastScheduleTime: &now,
+						Runs: []bdv1.ErrandRun{
+							{Trigger: bdv1.ErrandTriggerSchedule, Pod: "smoke-tests-1", StartTime: now, CompletionTime: &now, ExitCode: pointers.Int32(1), LogTail: "failed"},
+						},
+					},
+				},
+				Conditions: []bdv1.Condition{
+					{Type: bdv1.ConditionDependenciesReady, Status: corev1.ConditionTrue, LastTransitionTime: now, Reason: "Ready"},
+				},
+			},
+		}
+
+		It("converts to v1beta1 and back", func() {
+			spoke := &bdv1beta1.BOSHDeployment{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.Manifest.Type).To(Equal(bdv1beta1.ConfigMapReference))
+			Expect(spoke.Status.Errands[0].Runs[0].Trigger).To(Equal(bdv1beta1.ErrandTriggerSchedule))
+			Expect(*spoke.Status.Conditions[0].LastTransitionTime).To(Equal(now))
+
+			converted := &bdv1.BOSHDeployment{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+
+		It("omits zero timestamps in v1beta1", func() {
+			hub := hub.DeepCopy()
+			hub.Status.Conditions[0].LastTransitionTime = metav1.Time{}
+
+			spoke := &bdv1beta1.BOSHDeployment{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Status.Conditions[0].LastTransitionTime).To(BeNil())
+		})
+	})
+
+	Context("for QuarksSecrets", func() {
+		hub := &qsv1a1.QuarksSecret{
+			ObjectMeta: meta,
+			Spec: qsv1a1.QuarksSecretSpec{
+				Type:       qsv1a1.Certificate,
+				SecretName: "foo-cert",
+				Request: qsv1a1.Request{
+					CertificateRequest: qsv1a1.CertificateRequest{
+						CommonName:       "foo.com",
+						AlternativeNames: []string{"bar.com"},
+						CARef:            qsv1a1.SecretReference{Name: "ca", Key: "certificate"},
+						CAKeyRef:         qsv1a1.SecretReference{Name: "ca", Key: "private_key"},
+						SignerType:       qsv1a1.ClusterSigner,
+						Usages:           []certv1.KeyUsage{certv1.UsageServerAuth},
+						ServiceRef:       []qsv1a1.ServiceReference{{Name: "foo"}},
+					},
+				},
+			},
+			Status: qsv1a1.QuarksSecretStatus{LastReconcile: &now, Generated: true},
+		}
+
+		It("converts to v1beta1 and back", func() {
+			spoke := &qsv1beta1.QuarksSecret{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.Request.Certificate.CARef).To(Equal(&qsv1beta1.SecretReference{Name: "ca", Key: "certificate"}))
+			Expect(spoke.Spec.Request.Certificate.ServiceRefs).To(Equal([]qsv1beta1.ServiceReference{{Name: "foo"}}))
+
+			converted := &qsv1a1.QuarksSecret{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+
+		It("has no certificate request for other secret types", func() {
+			hub := &qsv1a1.QuarksSecret{
+				ObjectMeta: meta,
+				Spec:       qsv1a1.QuarksSecretSpec{Type: qsv1a1.Password, SecretName: "foo-password"},
+			}
+
+			spoke := &qsv1beta1.QuarksSecret{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.Request.Certificate).To(BeNil())
+
+			converted := &qsv1a1.QuarksSecret{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+	})
+
+	Context("for QuarksStatefulSets", func() {
+		hub := &qstsv1a1.QuarksStatefulSet{
+			ObjectMeta: meta,
+			Spec: qstsv1a1.QuarksStatefulSetSpec{
+				UpdateOnConfigChange: true,
+				Zones:                []string{"z1", "z2"},
+				Template: appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+					Spec:       appsv1.StatefulSetSpec{Replicas: pointers.Int32(2)},
+				},
+				ActivePassiveProbes: map[string]corev1.Probe{
+					"foo": {Handler: corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"true"}}}},
+				},
+			},
+			Status: qstsv1a1.QuarksStatefulSetStatus{LastReconcile: &now},
+		}
+
+		It("converts to v1beta1 and back", func() {
+			spoke := &qstsv1beta1.QuarksStatefulSet{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.Zones).To(Equal([]string{"z1", "z2"}))
+
+			converted := &qstsv1a1.QuarksStatefulSet{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+	})
+})
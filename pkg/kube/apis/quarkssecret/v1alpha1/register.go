@@ -47,12 +47,113 @@ var (
 						},
 						"type": {
 							Type:        "string",
-							MinLength:   pointers.Int64(1),
 							Description: "What kind of secret to generate: password, certificate, ssh, rsa",
+							Enum: []extv1.JSON{
+								{
+									Raw: []byte(`"password"`),
+								},
+								{
+									Raw: []byte(`"certificate"`),
+								},
+								{
+									Raw: []byte(`"ssh"`),
+								},
+								{
+									Raw: []byte(`"rsa"`),
+								},
+							},
 						},
 						"request": {
-							Type:                   "object",
-							XPreserveUnknownFields: pointers.Bool(true),
+							Type:        "object",
+							Description: "Details for the secret generation",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"certificate": {
+									Type:        "object",
+									Description: "Details for the certificate generation, used if the type is certificate",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"commonName": {
+											Type: "string",
+										},
+										"alternativeNames": {
+											Type:     "array",
+											Nullable: true,
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+										},
+										"isCA": {
+											Type: "boolean",
+										},
+										"CARef": {
+											Type:        "object",
+											Description: "The secret containing the CA certificate",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {
+													Type: "string",
+												},
+												"key": {
+													Type: "string",
+												},
+											},
+										},
+										"CAKeyRef": {
+											Type:        "object",
+											Description: "The secret containing the CA private key",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {
+													Type: "string",
+												},
+												"key": {
+													Type: "string",
+												},
+											},
+										},
+										"signerType": {
+											Type:        "string",
+											Description: "The signer of the certificate: local, cluster",
+											Default:     &extv1.JSON{Raw: []byte(`"local"`)},
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"local"`),
+												},
+												{
+													Raw: []byte(`"cluster"`),
+												},
+											},
+										},
+										"usages": {
+											Type:        "array",
+											Description: "Key usages of the certificate requested from the cluster signer",
+											Nullable:    true,
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+										},
+										"serviceRef": {
+											Type:        "array",
+											Description: "Services, whose cluster IPs are added to the alternative names",
+											Nullable:    true,
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"Name": {
+															Type: "string",
+														},
+													},
+												},
+											},
+										},
+										"activateEKSWorkaroundForSAN": {
+											Type: "boolean",
+										},
+									},
+								},
+							},
 						},
 					},
 					Required: []string{
@@ -64,10 +165,14 @@ var (
 					Type: "object",
 					Properties: map[string]extv1.JSONSchemaProps{
 						"generated": {
-							Type: "boolean",
+							Type:        "boolean",
+							Description: "Indicates if the secret has already been generated",
 						},
 						"lastReconcile": {
-							Type: "string",
+							Type:        "string",
+							Format:      "date-time",
+							Nullable:    true,
+							Description: "Timestamp for the last reconcile",
 						},
 					},
 				},
@@ -75,6 +180,33 @@ var (
 		},
 	}
 
+	// QuarksSecretAdditionalPrinterColumns are the columns shown by `kubectl get quarkssecrets`
+	QuarksSecretAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Type",
+			Type:        "string",
+			Description: "What kind of secret is generated",
+			JSONPath:    ".spec.type",
+		},
+		{
+			Name:        "Secret",
+			Type:        "string",
+			Description: "The name of the generated secret",
+			JSONPath:    ".spec.secretName",
+		},
+		{
+			Name:        "Generated",
+			Type:        "boolean",
+			Description: "Whether the secret has been generated",
+			JSONPath:    ".status.generated",
+		},
+		{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
 	// QuarksSecretResourceName is the resource name of QuarksSecret
 	QuarksSecretResourceName = fmt.Sprintf("%s.%s", QuarksSecretResourcePlural, apis.GroupName)
 
@@ -0,0 +1,89 @@
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+)
+
+// ConvertTo converts this QuarksSecret to the hub version v1alpha1
+func (src *QuarksSecret) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.QuarksSecret)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Type = v1alpha1.SecretType(src.Spec.Type)
+	dst.Spec.SecretName = src.Spec.SecretName
+	dst.Spec.Request = v1alpha1.Request{}
+	if req := src.Spec.Request.Certificate; req != nil {
+		dst.Spec.Request.CertificateRequest = v1alpha1.CertificateRequest{
+			CommonName:                  req.CommonName,
+			AlternativeNames:            req.AlternativeNames,
+			IsCA:                        req.IsCA,
+			SignerType:                  v1alpha1.SignerType(req.SignerType),
+			Usages:                      req.Usages,
+			ActivateEKSWorkaroundForSAN: req.ActivateEKSWorkaroundForSAN,
+		}
+		if req.CARef != nil {
+			dst.Spec.Request.CertificateRequest.CARef = v1alpha1.SecretReference(*req.CARef)
+		}
+		if req.CAKeyRef != nil {
+			dst.Spec.Request.CertificateRequest.CAKeyRef = v1alpha1.SecretReference(*req.CAKeyRef)
+		}
+		for _, ref := range req.ServiceRefs {
+			dst.Spec.Request.CertificateRequest.ServiceRef = append(dst.Spec.Request.CertificateRequest.ServiceRef, v1alpha1.ServiceReference(ref))
+		}
+	}
+
+	dst.Status = v1alpha1.QuarksSecretStatus(src.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the hub version v1alpha1 to this version
+func (dst *QuarksSecret) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.QuarksSecret)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Type = SecretType(src.Spec.Type)
+	dst.Spec.SecretName = src.Spec.SecretName
+	dst.Spec.Request = Request{}
+	if req := src.Spec.Request.CertificateRequest; !isEmptyCertificateRequest(req) {
+		dst.Spec.Request.Certificate = &CertificateRequest{
+			CommonName:                  req.CommonName,
+			AlternativeNames:            req.AlternativeNames,
+			IsCA:                        req.IsCA,
+			SignerType:                  SignerType(req.SignerType),
+			Usages:                      req.Usages,
+			ActivateEKSWorkaroundForSAN: req.ActivateEKSWorkaroundForSAN,
+		}
+		if req.CARef != (v1alpha1.SecretReference{}) {
+			ref := SecretReference(req.CARef)
+			dst.Spec.Request.Certificate.CARef = &ref
+		}
+		if req.CAKeyRef != (v1alpha1.SecretReference{}) {
+			ref := SecretReference(req.CAKeyRef)
+			dst.Spec.Request.Certificate.CAKeyRef = &ref
+		}
+		for _, ref := range req.ServiceRef {
+			dst.Spec.Request.Certificate.ServiceRefs = append(dst.Spec.Request.Certificate.ServiceRefs, ServiceReference(ref))
+		}
+	}
+
+	dst.Status = QuarksSecretStatus(src.Status)
+
+	return nil
+}
+
+// isEmptyCertificateRequest returns true for the zero value, which v1alpha1
+// uses for secrets of other types than certificate
+func isEmptyCertificateRequest(req v1alpha1.CertificateRequest) bool {
+	return req.CommonName == "" &&
+		len(req.AlternativeNames) == 0 &&
+		!req.IsCA &&
+		req.CARef == (v1alpha1.SecretReference{}) &&
+		req.CAKeyRef == (v1alpha1.SecretReference{}) &&
+		req.SignerType == "" &&
+		len(req.Usages) == 0 &&
+		len(req.ServiceRef) == 0 &&
+		!req.ActivateEKSWorkaroundForSAN
+}
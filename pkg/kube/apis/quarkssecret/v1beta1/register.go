@@ -0,0 +1,207 @@
+package v1beta1
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apis "code.cloudfoundry.org/cf-operator/pkg/kube/apis"
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+)
+
+// This file looks almost the same for all controllers
+// Modify the addKnownTypes function, then run `make generate`
+
+var (
+	schemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+	// AddToScheme is used for schema registrations in the controller package
+	// and also in the generated kube code
+	AddToScheme = schemeBuilder.AddToScheme
+
+	// QuarksSecretValidation is the validation schema for the v1beta1 QuarksSecret
+	QuarksSecretValidation = extv1.CustomResourceValidation{
+		OpenAPIV3Schema: &extv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"secretName": {
+							Type:        "string",
+							MinLength:   pointers.Int64(1),
+							Description: "The name of the generated secret",
+						},
+						"type": {
+							Type:        "string",
+							Description: "What kind of secret to generate: password, certificate, ssh, rsa",
+							Enum: []extv1.JSON{
+								{
+									Raw: []byte(`"password"`),
+								},
+								{
+									Raw: []byte(`"certificate"`),
+								},
+								{
+									Raw: []byte(`"ssh"`),
+								},
+								{
+									Raw: []byte(`"rsa"`),
+								},
+							},
+						},
+						"request": {
+							Type:        "object",
+							Description: "Details for the secret generation",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"certificate": {
+									Type:        "object",
+									Description: "Details for the certificate generation, required if the type is certificate",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"commonName": {
+											Type: "string",
+										},
+										"alternativeNames": {
+											Type: "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+										},
+										"isCA": {
+											Type: "boolean",
+										},
+										"caRef": {
+											Type:        "object",
+											Description: "The secret containing the CA certificate",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {
+													Type:      "string",
+													MinLength: pointers.Int64(1),
+												},
+												"key": {
+													Type:      "string",
+													MinLength: pointers.Int64(1),
+												},
+											},
+											Required: []string{
+												"name",
+												"key",
+											},
+										},
+										"caKeyRef": {
+											Type:        "object",
+											Description: "The secret containing the CA private key",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {
+													Type:      "string",
+													MinLength: pointers.Int64(1),
+												},
+												"key": {
+													Type:      "string",
+													MinLength: pointers.Int64(1),
+												},
+											},
+											Required: []string{
+												"name",
+												"key",
+											},
+										},
+										"signerType": {
+											Type:        "string",
+											Description: "The signer of the certificate: local, cluster",
+											Default:     &extv1.JSON{Raw: []byte(`"local"`)},
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"local"`),
+												},
+												{
+													Raw: []byte(`"cluster"`),
+												},
+											},
+										},
+										"usages": {
+											Type:        "array",
+											Description: "Key usages of the certificate requested from the cluster signer",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+										},
+										"serviceRefs": {
+											Type:        "array",
+											Description: "Services, whose cluster IPs are added to the alternative names",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {
+															Type:      "string",
+															MinLength: pointers.Int64(1),
+														},
+													},
+													Required: []string{
+														"name",
+													},
+												},
+											},
+										},
+										"activateEKSWorkaroundForSAN": {
+											Type: "boolean",
+										},
+									},
+								},
+							},
+						},
+					},
+					Required: []string{
+						"secretName",
+						"type",
+					},
+				},
+				"status": {
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"generated": {
+							Type:        "boolean",
+							Description: "Indicates if the secret has already been generated",
+						},
+						"lastReconcile": {
+							Type:        "string",
+							Format:      "date-time",
+							Nullable:    true,
+							Description: "Timestamp for the last reconcile",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: apis.GroupName, Version: "v1beta1"}
+)
+
+// Kind takes an unqualified kind and returns back a Group qualified GroupKind
+func Kind(kind string) schema.GroupKind {
+	return SchemeGroupVersion.WithKind(kind).GroupKind()
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+// Adds the list of known types to Scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&QuarksSecret{},
+		&QuarksSecretList{},
+	)
+
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,102 @@
+package v1beta1
+
+import (
+	certv1 "k8s.io/api/certificates/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file is safe to edit
+// It's used as input for the Kube code generator
+// Run "make generate" after modifying this file
+
+// SecretType defines the type of the generated secret
+type SecretType string
+
+// Valid values for secret types
+const (
+	Password    SecretType = "password"
+	Certificate SecretType = "certificate"
+	SSHKey      SecretType = "ssh"
+	RSAKey      SecretType = "rsa"
+)
+
+// SignerType defines the type of the certificate signer
+type SignerType string
+
+// Valid values for signer types
+const (
+	// LocalSigner defines the local as certificate signer
+	LocalSigner SignerType = "local"
+	// ClusterSigner defines the cluster as certificate signer
+	ClusterSigner SignerType = "cluster"
+)
+
+// SecretReference specifies a reference to another secret
+type SecretReference struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// ServiceReference specifies a reference to a service
+type ServiceReference struct {
+	Name string `json:"name"`
+}
+
+// CertificateRequest specifies the details for the certificate generation
+type CertificateRequest struct {
+	CommonName       string   `json:"commonName,omitempty"`
+	AlternativeNames []string `json:"alternativeNames,omitempty"`
+	IsCA             bool     `json:"isCA,omitempty"`
+	// CARef references the secret containing the CA certificate
+	CARef *SecretReference `json:"caRef,omitempty"`
+	// CAKeyRef references the secret containing the CA private key
+	CAKeyRef   *SecretReference  `json:"caKeyRef,omitempty"`
+	SignerType SignerType        `json:"signerType,omitempty"`
+	Usages     []certv1.KeyUsage `json:"usages,omitempty"`
+	// ServiceRefs lists services, whose cluster IPs are added to the alternative names
+	ServiceRefs                 []ServiceReference `json:"serviceRefs,omitempty"`
+	ActivateEKSWorkaroundForSAN bool               `json:"activateEKSWorkaroundForSAN,omitempty"`
+}
+
+// Request specifies details for the secret generation
+type Request struct {
+	// Certificate is required for secrets of type certificate
+	Certificate *CertificateRequest `json:"certificate,omitempty"`
+}
+
+// QuarksSecretSpec defines the desired state of QuarksSecret
+type QuarksSecretSpec struct {
+	Type       SecretType `json:"type"`
+	Request    Request    `json:"request,omitempty"`
+	SecretName string     `json:"secretName"`
+}
+
+// QuarksSecretStatus defines the observed state of QuarksSecret
+type QuarksSecretStatus struct {
+	// Timestamp for the last reconcile
+	LastReconcile *metav1.Time `json:"lastReconcile,omitempty"`
+	// Indicates if the secret has already been generated
+	Generated bool `json:"generated,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksSecret is the Schema for the QuarksSecrets API
+// +k8s:openapi-gen=true
+type QuarksSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksSecretSpec   `json:"spec,omitempty"`
+	Status QuarksSecretStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksSecretList contains a list of QuarksSecret
+type QuarksSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuarksSecret `json:"items"`
+}
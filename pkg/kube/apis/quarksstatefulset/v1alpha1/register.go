@@ -47,12 +47,20 @@ var (
 						},
 						"updateOnConfigChange": {
 							Type:        "boolean",
+							Default:     &extv1.JSON{Raw: []byte(`false`)},
 							Description: "Indicate whether to update Pods in the StatefulSet when an env value or mount changes",
 						},
 						"activePassiveProbes": {
-							Type:                   "object",
-							Description:            "Defines probes to determine active/passive component instances",
-							XPreserveUnknownFields: pointers.Bool(true),
+							Type:        "object",
+							Description: "Defines probes to determine active/passive component instances",
+							AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+								Allows: true,
+								Schema: &extv1.JSONSchemaProps{
+									Type:                   "object",
+									Description:            "A probe for the container of the same name",
+									XPreserveUnknownFields: pointers.Bool(true),
+								},
+							},
 						},
 						"zoneNodeLabel": {
 							Type:        "string",
@@ -76,7 +84,10 @@ var (
 					Type: "object",
 					Properties: map[string]extv1.JSONSchemaProps{
 						"lastReconcile": {
-							Type: "string",
+							Type:        "string",
+							Format:      "date-time",
+							Nullable:    true,
+							Description: "Timestamp for the last reconcile",
 						},
 					},
 				},
@@ -84,6 +95,28 @@ var (
 		},
 	}
 
+	// QuarksStatefulSetAdditionalPrinterColumns are the columns shown by `kubectl get quarksstatefulsets`
+	QuarksStatefulSetAdditionalPrinterColumns = []extv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Replicas",
+			Type:        "integer",
+			Description: "The number of replicas of the StatefulSet template",
+			JSONPath:    ".spec.template.spec.replicas",
+		},
+		{
+			Name:        "Zones",
+			Type:        "string",
+			Description: "The availability zones the QuarksStatefulSet spans",
+			JSONPath:    ".spec.zones",
+			Priority:    1,
+		},
+		{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+	}
+
 	// QuarksStatefulSetResourceName is the resource name of QuarksStatefulSet
 	QuarksStatefulSetResourceName = fmt.Sprintf("%s.%s", QuarksStatefulSetResourcePlural, apis.GroupName)
 
@@ -0,0 +1,29 @@
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+)
+
+// ConvertTo converts this QuarksStatefulSet to the hub version v1alpha1
+func (src *QuarksStatefulSet) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.QuarksStatefulSet)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.QuarksStatefulSetSpec(src.Spec)
+	dst.Status = v1alpha1.QuarksStatefulSetStatus(src.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the hub version v1alpha1 to this version
+func (dst *QuarksStatefulSet) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.QuarksStatefulSet)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = QuarksStatefulSetSpec(src.Spec)
+	dst.Status = QuarksStatefulSetStatus(src.Status)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package v1beta1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file is safe to edit
+// It's used as input for the Kube code generator
+// Run "make generate" after modifying this file
+
+// QuarksStatefulSetSpec defines the desired state of QuarksStatefulSet
+type QuarksStatefulSetSpec struct {
+	// Indicates whether to update Pods in the StatefulSet when an env value or mount changes
+	UpdateOnConfigChange bool `json:"updateOnConfigChange,omitempty"`
+
+	// Indicates the node label that a node locates
+	ZoneNodeLabel string `json:"zoneNodeLabel,omitempty"`
+
+	// Indicates the availability zones that the QuarksStatefulSet needs to span
+	Zones []string `json:"zones,omitempty"`
+
+	// Defines a regular StatefulSet template
+	Template appsv1.StatefulSet `json:"template"`
+
+	// Periodic probe for active/passive containers
+	// Only an active container will process request from a service
+	ActivePassiveProbes map[string]corev1.Probe `json:"activePassiveProbes,omitempty"`
+}
+
+// QuarksStatefulSetStatus defines the observed state of QuarksStatefulSet
+type QuarksStatefulSetStatus struct {
+	// Timestamp for the last reconcile
+	LastReconcile *metav1.Time `json:"lastReconcile,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksStatefulSet is the Schema for the QuarksStatefulSet API
+// +k8s:openapi-gen=true
+type QuarksStatefulSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuarksStatefulSetSpec   `json:"spec,omitempty"`
+	Status QuarksStatefulSetStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuarksStatefulSetList contains a list of QuarksStatefulSet
+type QuarksStatefulSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuarksStatefulSet `json:"items"`
+}
@@ -0,0 +1,215 @@
+package apis_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	bdv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1beta1"
+	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	qsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1beta1"
+	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	qstsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1beta1"
+)
+
+var timeType = reflect.TypeOf(metav1.Time{})
+
+// compare returns the differences between the schema and the JSON
+// serialization of the Go type
+func compare(path string, t reflect.Type, s extv1.JSONSchemaProps) []string {
+	if s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields && len(s.Properties) == 0 {
+		return nil
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	expectType := func(typ string) []string {
+		if s.Type != typ {
+			return []string{fmt.Sprintf("%s: expected type '%s', got '%s'", path, typ, s.Type)}
+		}
+		return nil
+	}
+
+	if t == timeType {
+		diffs := expectType("string")
+		if s.Format != "date-time" {
+			diffs = append(diffs, fmt.Sprintf("%s: expected date-time", path))
+		}
+		return diffs
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return expectType("string")
+	case reflect.Bool:
+		return expectType("boolean")
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return expectType("integer")
+	case reflect.Slice:
+		diffs := expectType("array")
+		if s.Items == nil || s.Items.Schema == nil {
+			return append(diffs, fmt.Sprintf("%s: missing items", path))
+		}
+		return append(diffs, compare(path+"[]", t.Elem(), *s.Items.Schema)...)
+	case reflect.Map:
+		diffs := expectType("object")
+		if s.AdditionalProperties == nil || s.AdditionalProperties.Schema == nil {
+			return append(diffs, fmt.Sprintf("%s: missing additionalProperties", path))
+		}
+		return append(diffs, compare(path+"{}", t.Elem(), *s.AdditionalProperties.Schema)...)
+	case reflect.Struct:
+		diffs := expectType("object")
+		fields := map[string]bool{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")
+			name := tag[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = true
+
+			p, ok := s.Properties[name]
+			if !ok {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: missing in schema", path, name))
+				continue
+			}
+
+			// The zero time is serialized as null, even with omitempty
+			omitEmpty := len(tag) > 1 && tag[1] == "omitempty"
+			switch {
+			case f.Type == timeType,
+				!omitEmpty && (f.Type.Kind() == reflect.Ptr || f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Map):
+				if !p.Nullable {
+					diffs = append(diffs, fmt.Sprintf("%s.%s: serialized as null, but not nullable", path, name))
+				}
+			}
+
+			diffs = append(diffs, compare(path+"."+name, f.Type, p)...)
+		}
+		for name := range s.Properties {
+			if !fields[name] {
+				diffs = append(diffs, fmt.Sprintf("%s.%s: missing in Go type", path, name))
+			}
+		}
+		return diffs
+	}
+
+	return []string{fmt.Sprintf("%s: unexpected kind %s", path, t.Kind())}
+}
+
+// enum returns the values of the enum of a schema
+func enum(s extv1.JSONSchemaProps) []string {
+	values := []string{}
+	for _, e := range s.Enum {
+		var v string
+		Expect(json.Unmarshal(e.Raw, &v)).To(Succeed())
+		values = append(values, v)
+	}
+	return values
+}
+
+var _ = Describe("Schemas", func() {
+	type crd struct {
+		spec       reflect.Type
+		status     reflect.Type
+		validation extv1.CustomResourceValidation
+		columns    []extv1.CustomResourceColumnDefinition
+	}
+
+	crds := map[string]crd{
+		"BOSHDeployment": {
+			reflect.TypeOf(bdv1.BOSHDeploymentSpec{}),
+			reflect.TypeOf(bdv1.BOSHDeploymentStatus{}),
+			bdv1.BOSHDeploymentValidation,
+			bdv1.BOSHDeploymentAdditionalPrinterColumns,
+		},
+		"QuarksSecret": {
+			reflect.TypeOf(qsv1a1.QuarksSecretSpec{}),
+			reflect.TypeOf(qsv1a1.QuarksSecretStatus{}),
+			qsv1a1.QuarksSecretValidation,
+			qsv1a1.QuarksSecretAdditionalPrinterColumns,
+		},
+		"QuarksStatefulSet": {
+			reflect.TypeOf(qstsv1a1.QuarksStatefulSetSpec{}),
+			reflect.TypeOf(qstsv1a1.QuarksStatefulSetStatus{}),
+			qstsv1a1.QuarksStatefulSetValidation,
+			qstsv1a1.QuarksStatefulSetAdditionalPrinterColumns,
+		},
+		"v1beta1 BOSHDeployment": {
+			reflect.TypeOf(bdv1beta1.BOSHDeploymentSpec{}),
+			reflect.TypeOf(bdv1beta1.BOSHDeploymentStatus{}),
+			bdv1beta1.BOSHDeploymentValidation,
+			bdv1.BOSHDeploymentAdditionalPrinterColumns,
+		},
+		"v1beta1 QuarksSecret": {
+			reflect.TypeOf(qsv1beta1.QuarksSecretSpec{}),
+			reflect.TypeOf(qsv1beta1.QuarksSecretStatus{}),
+			qsv1beta1.QuarksSecretValidation,
+			qsv1a1.QuarksSecretAdditionalPrinterColumns,
+		},
+		"v1beta1 QuarksStatefulSet": {
+			reflect.TypeOf(qstsv1beta1.QuarksStatefulSetSpec{}),
+			reflect.TypeOf(qstsv1beta1.QuarksStatefulSetStatus{}),
+			qstsv1beta1.QuarksStatefulSetValidation,
+			qstsv1a1.QuarksStatefulSetAdditionalPrinterColumns,
+		},
+	}
+
+	for kind, c := range crds {
+		kind, c := kind, c
+
+		Context(fmt.Sprintf("for %s", kind), func() {
+			It("matches the spec and status of the Go type", func() {
+				schema := c.validation.OpenAPIV3Schema
+				Expect(schema.Type).To(Equal("object"))
+				Expect(compare("spec", c.spec, schema.Properties["spec"])).To(BeEmpty())
+				Expect(compare("status", c.status, schema.Properties["status"])).To(BeEmpty())
+			})
+
+			It("has printer columns", func() {
+				Expect(c.columns).ToNot(BeEmpty())
+				for _, column := range c.columns {
+					Expect(column.JSONPath).To(HavePrefix("."))
+				}
+			})
+		})
+	}
+
+	It("has enums for the reference types", func() {
+		manifest := bdv1.BOSHDeploymentValidation.OpenAPIV3Schema.Properties["spec"].Properties["manifest"]
+		Expect(enum(manifest.Properties["type"])).To(ConsistOf(bdv1.ConfigMapReference, bdv1.SecretReference, bdv1.URLReference))
+
+		ops := bdv1.BOSHDeploymentValidation.OpenAPIV3Schema.Properties["spec"].Properties["ops"]
+		Expect(enum(ops.Items.Schema.Properties["type"])).To(ConsistOf(bdv1.ConfigMapReference, bdv1.SecretReference, bdv1.URLReference))
+	})
+
+	It("has enums for the secret and signer types", func() {
+		spec := qsv1a1.QuarksSecretValidation.OpenAPIV3Schema.Properties["spec"]
+		Expect(enum(spec.Properties["type"])).To(ConsistOf(qsv1a1.Password, qsv1a1.Certificate, qsv1a1.SSHKey, qsv1a1.RSAKey))
+
+		signerType := spec.Properties["request"].Properties["certificate"].Properties["signerType"]
+		Expect(enum(signerType)).To(ConsistOf(qsv1a1.LocalSigner, qsv1a1.ClusterSigner))
+		Expect(string(signerType.Default.Raw)).To(Equal(`"local"`))
+	})
+
+	It("defaults the readiness of dependencies", func() {
+		dependsOn := bdv1.BOSHDeploymentValidation.OpenAPIV3Schema.Properties["spec"].Properties["dependsOn"]
+		readiness := dependsOn.Items.Schema.Properties["readiness"]
+		Expect(enum(readiness)).To(ContainElement(bdv1.DependencyReady))
+		Expect(string(readiness.Default.Raw)).To(Equal(`"ready"`))
+	})
+})
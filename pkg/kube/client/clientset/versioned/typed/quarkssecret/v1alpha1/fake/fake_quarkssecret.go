@@ -23,9 +23,9 @@ type FakeQuarksSecrets struct {
 	ns   string
 }
 
-var quarkssecretsResource = schema.GroupVersionResource{Group: "quarkssecret", Version: "v1alpha1", Resource: "quarkssecrets"}
+var quarkssecretsResource = schema.GroupVersionResource{Group: "quarks.cloudfoundry.org", Version: "v1alpha1", Resource: "quarkssecrets"}
 
-var quarkssecretsKind = schema.GroupVersionKind{Group: "quarkssecret", Version: "v1alpha1", Kind: "QuarksSecret"}
+var quarkssecretsKind = schema.GroupVersionKind{Group: "quarks.cloudfoundry.org", Version: "v1alpha1", Kind: "QuarksSecret"}
 
 // Get takes name of the quarksSecret, and returns the corresponding quarksSecret object, and an error if there is any.
 func (c *FakeQuarksSecrets) Get(name string, options v1.GetOptions) (result *v1alpha1.QuarksSecret, err error) {
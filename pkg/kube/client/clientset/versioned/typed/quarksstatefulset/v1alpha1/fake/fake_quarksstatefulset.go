@@ -23,9 +23,9 @@ type FakeQuarksStatefulSets struct {
 	ns   string
 }
 
-var quarksstatefulsetsResource = schema.GroupVersionResource{Group: "quarksstatefulset", Version: "v1alpha1", Resource: "quarksstatefulsets"}
+var quarksstatefulsetsResource = schema.GroupVersionResource{Group: "quarks.cloudfoundry.org", Version: "v1alpha1", Resource: "quarksstatefulsets"}
 
-var quarksstatefulsetsKind = schema.GroupVersionKind{Group: "quarksstatefulset", Version: "v1alpha1", Kind: "QuarksStatefulSet"}
+var quarksstatefulsetsKind = schema.GroupVersionKind{Group: "quarks.cloudfoundry.org", Version: "v1alpha1", Kind: "QuarksStatefulSet"}
 
 // Get takes name of the quarksStatefulSet, and returns the corresponding quarksStatefulSet object, and an error if there is any.
 func (c *FakeQuarksStatefulSets) Get(name string, options v1.GetOptions) (result *v1alpha1.QuarksStatefulSet, err error) {
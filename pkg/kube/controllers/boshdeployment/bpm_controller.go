@@ -21,6 +21,7 @@ import (
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/desiredmanifest"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 	"code.cloudfoundry.org/quarks-utils/pkg/meltdown"
@@ -39,7 +40,6 @@ func AddBPM(ctx context.Context, config *config.Config, mgr manager.Manager) err
 		desiredmanifest.NewDesiredManifest(mgr.GetClient()),
 		controllerutil.SetControllerReference,
 		bpmconverter.NewConverter(
-			config.Namespace,
 			bpmconverter.NewVolumeFactory(),
 			func(deploymentName string, instanceGroupName string, version string, disableLogSidecar bool, releaseImageProvider bdm.ReleaseImageProvider, bpmConfigs bpm.Configs) bpmconverter.ContainerFactory {
 				return bpmconverter.NewContainerFactory(deploymentName, instanceGroupName, version, disableLogSidecar, releaseImageProvider, bpmConfigs)
@@ -50,7 +50,7 @@ func AddBPM(ctx context.Context, config *config.Config, mgr manager.Manager) err
 	)
 
 	// Create a new controller
-	c, err := controller.New("bpm-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "bpm-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxBoshDeploymentWorkers,
 	})
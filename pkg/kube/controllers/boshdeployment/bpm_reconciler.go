@@ -2,6 +2,7 @@ package boshdeployment
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -24,7 +25,10 @@ import (
 	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
 	qstscontroller "code.cloudfoundry.org/cf-operator/pkg/kube/controllers/quarksstatefulset"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/mutate"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
 	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
@@ -35,7 +39,7 @@ import (
 
 // BPMConverter converts k8s resources from single BOSH manifest
 type BPMConverter interface {
-	Resources(manifestName string, dns bpmconverter.DomainNameService, qStsVersion string, instanceGroup *bdm.InstanceGroup, releaseImageProvider bdm.ReleaseImageProvider, bpmConfigs bpm.Configs, igResolvedSecretVersion string) (*bpmconverter.Resources, error)
+	Resources(manifestName string, namespace string, dns bpmconverter.DomainNameService, qStsVersion string, instanceGroup *bdm.InstanceGroup, releaseImageProvider bdm.ReleaseImageProvider, bpmConfigs bpm.Configs, igResolvedSecretVersion string) (*bpmconverter.Resources, error)
 }
 
 // DesiredManifest unmarshals desired manifest from the manifest secret
@@ -77,7 +81,7 @@ type ReconcileBPM struct {
 // desired manifest. It then applies BPM information and deploys instance groups.
 func (r *ReconcileBPM) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	log.Infof(ctx, "Reconciling Instance Group BPM versioned secret '%s'", request.NamespacedName)
@@ -97,10 +101,8 @@ func (r *ReconcileBPM) Reconcile(request reconcile.Request) (reconcile.Result, e
 		return reconcile.Result{RequeueAfter: time.Second * 5}, nil
 	}
 
-	if meltdown.NewAnnotationWindow(r.config.MeltdownDuration, bpmSecret.ObjectMeta.Annotations).Contains(time.Now()) {
-		log.WithEvent(bpmSecret, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", bpmSecret.Name, r.config.MeltdownRequeueAfter)
-		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
-	}
+	ctx, span := tracing.StartForObject(ctx, "bpm-reconcile", bpmSecret)
+	defer span.End()
 
 	// Get the label from the BPM Secret and read the corresponding desired manifest
 	var deploymentName string
@@ -151,7 +153,7 @@ func (r *ReconcileBPM) Reconcile(request reconcile.Request) (reconcile.Result, e
 			log.WithEvent(bpmSecret, "DnsReconcileError").Errorf(ctx, "Failed to reconcile dns: %v", err)
 	}
 
-	resources, err := r.applyBPMResources(bdpl.Name, bpmSecret, manifest, dns)
+	resources, err := r.applyBPMResources(ctx, bdpl, bpmSecret, manifest, dns)
 	if err != nil {
 		return reconcile.Result{},
 			log.WithEvent(bpmSecret, "BPMApplyingError").Errorf(ctx, "Failed to apply BPM information: %v", err)
@@ -165,10 +167,14 @@ func (r *ReconcileBPM) Reconcile(request reconcile.Request) (reconcile.Result, e
 	// Deploy instance groups
 	err = r.deployInstanceGroups(ctx, bdpl, instanceGroupName, resources)
 	if err != nil {
+		metrics.SetBOSHDeploymentPhase(bdpl.Namespace, bdpl.Name, metrics.PhaseFailed)
 		return reconcile.Result{},
 			log.WithEvent(bpmSecret, "InstanceGroupStartError").Errorf(ctx, "Failed to start: %v", err)
 	}
+	metrics.SetBOSHDeploymentPhase(bdpl.Namespace, bdpl.Name, metrics.PhaseDeploying)
 
+	// Mark the secret as reconciled, the create predicate skips it when the
+	// operator restarts, which would bump the QuarksStatefulSet version
 	meltdown.SetLastReconcile(&bpmSecret.ObjectMeta, time.Now())
 	err = r.client.Update(ctx, bpmSecret)
 	if err != nil {
@@ -179,7 +185,8 @@ func (r *ReconcileBPM) Reconcile(request reconcile.Request) (reconcile.Result, e
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileBPM) applyBPMResources(bdplName string, bpmSecret *corev1.Secret, manifest *bdm.Manifest, dns boshdns.DomainNameService) (*bpmconverter.Resources, error) {
+func (r *ReconcileBPM) applyBPMResources(ctx context.Context, bdpl *bdv1.BOSHDeployment, bpmSecret *corev1.Secret, manifest *bdm.Manifest, dns boshdns.DomainNameService) (*bpmconverter.Resources, error) {
+	bdplName := bdpl.Name
 
 	instanceGroupName, ok := bpmSecret.Labels[qjv1a1.LabelRemoteID]
 	if !ok {
@@ -196,6 +203,11 @@ func (r *ReconcileBPM) applyBPMResources(bdplName string, bpmSecret *corev1.Secr
 		return nil, errors.New("Couldn't find bpm.yaml key in manifest secret")
 	}
 
+	// Findings of the job property validation in warn mode
+	for _, warning := range bpmInfo.PropertyWarnings {
+		log.WarningEvent(ctx, bdpl, "PropertyValidation", fmt.Sprintf("instance group '%s': %s", instanceGroupName, warning))
+	}
+
 	instanceGroup, found := manifest.InstanceGroups.InstanceGroupByName(instanceGroupName)
 	if !found {
 		return nil, errors.Errorf("instance group '%s' not found", instanceGroupName)
@@ -204,7 +216,7 @@ func (r *ReconcileBPM) applyBPMResources(bdplName string, bpmSecret *corev1.Secr
 	// Fetch qSts version
 	quarksStatefulSet := &qstsv1a1.QuarksStatefulSet{}
 	quarksStatefulSetName := instanceGroup.QuarksStatefulSetName(bdplName)
-	err := r.client.Get(r.ctx, types.NamespacedName{Namespace: r.config.Namespace, Name: quarksStatefulSetName}, quarksStatefulSet)
+	err := r.client.Get(r.ctx, types.NamespacedName{Namespace: bdpl.Namespace, Name: quarksStatefulSetName}, quarksStatefulSet)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
 			return nil, errors.Errorf("Failed to get QuarksStatefulSet instance '%s': %v", quarksStatefulSetName, err)
@@ -220,12 +232,12 @@ func (r *ReconcileBPM) applyBPMResources(bdplName string, bpmSecret *corev1.Secr
 		return nil, err
 	}
 
-	igResolvedSecretVersion, err := r.fetchIGresolvedVersion(bdplName, instanceGroupName)
+	igResolvedSecretVersion, err := r.fetchIGresolvedVersion(bdplName, bdpl.Namespace, instanceGroupName)
 	if err != nil {
 		return nil, err
 	}
 
-	resources, err := r.converter.Resources(bdplName, dns, qStsVersionString, instanceGroup, manifest, bpmInfo.Configs, igResolvedSecretVersion)
+	resources, err := r.converter.Resources(bdplName, bdpl.Namespace, dns, qStsVersionString, instanceGroup, manifest, bpmInfo.Configs, igResolvedSecretVersion)
 	if err != nil {
 		return resources, err
 	}
@@ -233,14 +245,14 @@ func (r *ReconcileBPM) applyBPMResources(bdplName string, bpmSecret *corev1.Secr
 	return resources, nil
 }
 
-func (r *ReconcileBPM) fetchIGresolvedVersion(manifestName, instanceGroupName string) (string, error) {
+func (r *ReconcileBPM) fetchIGresolvedVersion(manifestName, namespace, instanceGroupName string) (string, error) {
 	igResolvedSecretName := names.InstanceGroupSecretName(
 		names.DeploymentSecretTypeInstanceGroupResolvedProperties,
 		manifestName,
 		instanceGroupName,
 		"",
 	)
-	igResolvedSecret, err := r.versionedSecretStore.Latest(r.ctx, r.config.Namespace, igResolvedSecretName)
+	igResolvedSecret, err := r.versionedSecretStore.Latest(r.ctx, namespace, igResolvedSecretName)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to read latest versioned secret %s for bosh deployment %s", igResolvedSecretName, manifestName)
 	}
@@ -296,6 +308,7 @@ func (r *ReconcileBPM) deployInstanceGroups(ctx context.Context, bdpl *bdv1.BOSH
 		if err := r.setReference(bdpl, &qSts, r.scheme); err != nil {
 			return log.WithEvent(bdpl, "QuarksStatefulSetForDeploymentError").Errorf(ctx, "Failed to set reference for QuarksStatefulSet instance group '%s' : %v", instanceGroupName, err)
 		}
+		tracing.Annotate(ctx, &qSts)
 
 		op, err := controllerutil.CreateOrUpdate(ctx, r.client, &qSts, mutate.QuarksStatefulSetMutateFn(&qSts))
 		if err != nil {
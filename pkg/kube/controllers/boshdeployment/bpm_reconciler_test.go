@@ -334,6 +334,15 @@ variables: []
 				err = client.Get(context.Background(), types.NamespacedName{Name: "foo", Namespace: "default"}, newInstance)
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("emits warning events for findings of the job property validation", func() {
+				bpmInformation.Data["bpm.yaml"] = []byte(`property_warnings:
+- "job 'foo': property 'pasword' is not declared in the job spec"`)
+
+				_, err := reconciler.Reconcile(request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recorder.Events).To(Receive(Equal("Warning PropertyValidation instance group 'fakepod': job 'foo': property 'pasword' is not declared in the job spec")))
+			})
 		})
 	})
 })
@@ -0,0 +1,224 @@
+package boshdeployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/converter"
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/mutate"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+)
+
+// sharedLink is a shared link secret of another deployment
+type sharedLink struct {
+	bdm.SharedLink
+	namespace string
+}
+
+// resolveCrossDeploymentLinks finds the shared link secrets for links
+// consumed from other deployments. They are copied to link secrets of the
+// instance, so the instance group manifest job can mount them.
+// Links, which are not shared yet, are returned as missing providers.
+// Links are known by their provider name in the instance group manifest, so
+// providers may only be consumed once by name. providers maps the names of
+// the providers, which are already known, to their origin.
+func (r *ReconcileBOSHDeployment) resolveCrossDeploymentLinks(ctx context.Context, instance *bdv1.BOSHDeployment, manifest *bdm.Manifest, providers map[string]string) (converter.LinkInfos, map[string]bdm.QuarksLink, []string, error) {
+	linkInfos := converter.LinkInfos{}
+	quarksLinks := map[string]bdm.QuarksLink{}
+	missing := []string{}
+
+	for _, consumed := range manifest.ListCrossDeploymentLinks() {
+		origin := fmt.Sprintf("deployment '%s'", consumed.Deployment)
+		if other, ok := providers[consumed.Provider]; ok {
+			return linkInfos, quarksLinks, missing, errors.Errorf("link provider '%s' of %s collides with the provider of the same name of %s", consumed.Provider, origin, other)
+		}
+		providers[consumed.Provider] = origin
+
+		link, err := r.findSharedLink(ctx, instance.Namespace, consumed)
+		if err != nil {
+			return linkInfos, quarksLinks, missing, err
+		}
+		if link == nil {
+			missing = append(missing, fmt.Sprintf("%s/%s", consumed.Deployment, consumed.Provider))
+			continue
+		}
+
+		// Addresses are service names, which need the namespace if the provider is in another one
+		if link.namespace != instance.Namespace {
+			link.Address = qualifyAddress(link.Address, link.namespace)
+			for i := range link.Instances {
+				link.Instances[i].Address = qualifyAddress(link.Instances[i].Address, link.namespace)
+			}
+		}
+
+		secretName, err := r.applyConsumedLinkSecret(ctx, instance, consumed, link)
+		if err != nil {
+			return linkInfos, quarksLinks, missing, err
+		}
+
+		linkInfos = append(linkInfos, converter.LinkInfo{
+			SecretName:   secretName,
+			ProviderName: consumed.Provider,
+			ProviderType: link.Type,
+		})
+		quarksLinks[consumed.Provider] = bdm.QuarksLink{
+			Type:      link.Type,
+			Address:   link.Address,
+			Instances: link.Instances,
+		}
+	}
+
+	return linkInfos, quarksLinks, missing, nil
+}
+
+// findSharedLink looks for the shared link secret in the namespace of the
+// consumer first and then in all other watched namespaces, whose providing
+// deployment shares its links with the consumer's namespace. It returns nil
+// if the link wasn't found.
+func (r *ReconcileBOSHDeployment) findSharedLink(ctx context.Context, namespace string, consumed bdm.CrossDeploymentLink) (*sharedLink, error) {
+	opts := []crc.ListOption{
+		crc.MatchingLabels{
+			bdv1.LabelDeploymentName: consumed.Deployment,
+			bdv1.LabelSharedLink:     "true",
+		},
+	}
+	if !watchnamespaces.Enabled() {
+		opts = append(opts, crc.InNamespace(namespace))
+	}
+
+	secrets := &corev1.SecretList{}
+	err := r.client.List(ctx, secrets, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing shared link secrets of deployment '%s'", consumed.Deployment)
+	}
+
+	found := map[string]*sharedLink{}
+	for _, s := range secrets.Items {
+		link := &sharedLink{namespace: s.Namespace}
+		if err := json.Unmarshal(s.Data[bdm.SharedLinkSecretKey], &link.SharedLink); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse shared link secret '%s/%s'", s.Namespace, s.Name)
+		}
+		if link.Name != consumed.Provider {
+			continue
+		}
+		if s.Namespace != namespace {
+			shared, err := r.sharedWithNamespace(ctx, s.Namespace, consumed.Deployment, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if !shared {
+				log.Debugf(ctx, "Deployment '%s/%s' doesn't share its links with namespace '%s'", s.Namespace, consumed.Deployment, namespace)
+				continue
+			}
+		}
+		if _, ok := found[s.Namespace]; ok {
+			return nil, errors.Errorf("duplicated shared links of provider '%s' in deployment '%s/%s'", consumed.Provider, s.Namespace, consumed.Deployment)
+		}
+		found[s.Namespace] = link
+	}
+
+	if link, ok := found[namespace]; ok {
+		return link, nil
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		for _, link := range found {
+			return link, nil
+		}
+	}
+
+	namespaces := make([]string, 0, len(found))
+	for ns := range found {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return nil, errors.Errorf("shared link of provider '%s' in deployment '%s' found in multiple namespaces: %s", consumed.Provider, consumed.Deployment, strings.Join(namespaces, ", "))
+}
+
+// sharedWithNamespace returns true, if the providing deployment is in a
+// watched namespace and lists the consuming namespace in its
+// AnnotationLinkConsumerNamespaces annotation
+func (r *ReconcileBOSHDeployment) sharedWithNamespace(ctx context.Context, providerNamespace string, deployment string, consumerNamespace string) (bool, error) {
+	watched, err := watchnamespaces.IsWatchedNamespace(ctx, r.client, r.config, providerNamespace)
+	if apierrors.IsNotFound(errors.Cause(err)) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !watched {
+		return false, nil
+	}
+
+	provider := &bdv1.BOSHDeployment{}
+	err = r.client.Get(ctx, types.NamespacedName{Namespace: providerNamespace, Name: deployment}, provider)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get deployment '%s/%s'", providerNamespace, deployment)
+	}
+
+	for _, name := range strings.Split(provider.GetAnnotations()[bdv1.AnnotationLinkConsumerNamespaces], ",") {
+		if strings.TrimSpace(name) == consumerNamespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// applyConsumedLinkSecret creates or updates the link secret of the instance
+// for a consumed link, with the flattened link properties as data
+func (r *ReconcileBOSHDeployment) applyConsumedLinkSecret(ctx context.Context, instance *bdv1.BOSHDeployment, consumed bdm.CrossDeploymentLink, link *sharedLink) (string, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.QuarksLinkSecretName(instance.Name, "consumes", consumed.Deployment, consumed.Provider),
+			Namespace: instance.Namespace,
+			Labels: map[string]string{
+				bdv1.LabelDeploymentName:         instance.Name,
+				bdv1.LabelLinkProviderDeployment: consumed.Deployment,
+			},
+		},
+		StringData: link.Properties,
+	}
+
+	if err := r.setReference(instance, secret, r.scheme); err != nil {
+		return "", errors.Wrapf(err, "failed to set ownerReference for link secret '%s'", secret.Name)
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, mutate.SecretMutateFn(secret))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to apply link secret '%s'", secret.Name)
+	}
+	log.Debugf(ctx, "Link secret '%s' for provider '%s/%s' has been %s", secret.Name, consumed.Deployment, consumed.Provider, op)
+
+	return secret.Name, nil
+}
+
+// qualifyAddress adds the namespace to a service name
+func qualifyAddress(address string, namespace string) string {
+	if address == "" || strings.Contains(address, ".") {
+		return address
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", address, namespace, boshdns.GetClusterDomain())
+}
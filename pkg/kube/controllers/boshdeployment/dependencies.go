@@ -0,0 +1,169 @@
+package boshdeployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+)
+
+const (
+	// dependencyRequeueAfter is the time to wait before checking unready dependencies again
+	dependencyRequeueAfter = 30 * time.Second
+
+	reasonDependencyCycle       = "DependencyCycle"
+	reasonDependenciesNotReady  = "DependenciesNotReady"
+	reasonMissingLinkProviders  = "MissingLinkProviders"
+	reasonDependenciesAvailable = "DependenciesAvailable"
+)
+
+// missingProvidersError is returned by listLinkInfos, if no link secrets
+// exist for some providers. The providing deployment might not be ready yet.
+type missingProvidersError struct {
+	providers []string
+}
+
+func (e *missingProvidersError) Error() string {
+	return fmt.Sprintf("missing link secrets for providers: %s", strings.Join(e.providers, ", "))
+}
+
+// dependencyCycle returns the names of the BOSHDeployments forming a cycle,
+// if one can be reached from the instance by following dependsOn
+func (r *ReconcileBOSHDeployment) dependencyCycle(ctx context.Context, instance *bdv1.BOSHDeployment) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(name string, deps []bdv1.Dependency) ([]string, error)
+	visit = func(name string, deps []bdv1.Dependency) ([]string, error) {
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dep := range deps {
+			switch state[dep.Name] {
+			case visiting:
+				for i, n := range path {
+					if n == dep.Name {
+						return append(append([]string{}, path[i:]...), dep.Name), nil
+					}
+				}
+			case visited:
+				continue
+			}
+
+			bdpl := &bdv1.BOSHDeployment{}
+			err := r.client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: dep.Name}, bdpl)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					state[dep.Name] = visited
+					continue
+				}
+				return nil, errors.Wrapf(err, "getting dependency '%s'", dep.Name)
+			}
+
+			cycle, err := visit(dep.Name, bdpl.Spec.DependsOn)
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil, nil
+	}
+
+	return visit(instance.Name, instance.Spec.DependsOn)
+}
+
+// unreadyDependencies returns a description for each dependency, which
+// didn't reach its readiness yet
+func (r *ReconcileBOSHDeployment) unreadyDependencies(ctx context.Context, instance *bdv1.BOSHDeployment) ([]string, error) {
+	unready := []string{}
+
+	for _, dep := range instance.Spec.DependsOn {
+		bdpl := &bdv1.BOSHDeployment{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: dep.Name}, bdpl)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				unready = append(unready, fmt.Sprintf("%s (not found)", dep.Name))
+				continue
+			}
+			return unready, errors.Wrapf(err, "getting dependency '%s'", dep.Name)
+		}
+
+		if bdpl.Status.LastReconcile == nil {
+			unready = append(unready, fmt.Sprintf("%s (not deployed)", dep.Name))
+			continue
+		}
+
+		if dep.Readiness == bdv1.DependencyDeployed {
+			continue
+		}
+
+		ready, err := r.instanceGroupsReady(ctx, bdpl)
+		if err != nil {
+			return unready, errors.Wrapf(err, "checking readiness of dependency '%s'", dep.Name)
+		}
+		if !ready {
+			unready = append(unready, fmt.Sprintf("%s (not ready)", dep.Name))
+		}
+	}
+
+	return unready, nil
+}
+
+// instanceGroupsReady checks if the deployment has statefulsets and all of
+// their replicas are updated and ready
+func (r *ReconcileBOSHDeployment) instanceGroupsReady(ctx context.Context, instance *bdv1.BOSHDeployment) (bool, error) {
+	statefulSets := &appsv1.StatefulSetList{}
+	err := r.client.List(ctx, statefulSets,
+		crc.InNamespace(instance.Namespace),
+		crc.MatchingLabels{bdv1.LabelDeploymentName: instance.Name},
+	)
+	if err != nil {
+		return false, errors.Wrapf(err, "listing statefulsets of deployment '%s'", instance.Name)
+	}
+
+	if len(statefulSets.Items) == 0 {
+		return false, nil
+	}
+
+	for _, sts := range statefulSets.Items {
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		if sts.Status.ObservedGeneration < sts.Generation ||
+			sts.Status.ReadyReplicas < replicas ||
+			sts.Status.UpdatedReplicas < replicas {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// setDependenciesCondition sets the DependenciesReady condition on the instance
+func setDependenciesCondition(instance *bdv1.BOSHDeployment, status corev1.ConditionStatus, reason string, message string) {
+	instance.Status.SetCondition(bdv1.Condition{
+		Type:               bdv1.ConditionDependenciesReady,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
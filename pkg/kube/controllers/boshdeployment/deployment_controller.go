@@ -9,6 +9,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -23,6 +24,7 @@ import (
 	"code.cloudfoundry.org/cf-operator/pkg/bosh/qjobs"
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/reference"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/withops"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
@@ -43,13 +45,13 @@ func AddDeployment(ctx context.Context, config *config.Config, mgr manager.Manag
 				return boshdns.NewDNS(deploymentName, m)
 			},
 		),
-		qjobs.NewJobFactory(config.Namespace),
-		converter.NewVariablesConverter(config.Namespace),
+		qjobs.NewJobFactory(),
+		converter.NewVariablesConverter(),
 		controllerutil.SetControllerReference,
 	)
 
 	// Create a new controller
-	c, err := controller.New("boshdeployment-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "boshdeployment-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxBoshDeploymentWorkers,
 	})
@@ -71,7 +73,11 @@ func AddDeployment(ctx context.Context, config *config.Config, mgr manager.Manag
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			o := e.ObjectOld.(*bdv1.BOSHDeployment)
 			n := e.ObjectNew.(*bdv1.BOSHDeployment)
-			if !reflect.DeepEqual(o.Spec, n.Spec) {
+			// Errands are run by the errand controller, they don't change the deployment
+			if !reflect.DeepEqual(o.Spec.Manifest, n.Spec.Manifest) ||
+				!reflect.DeepEqual(o.Spec.Ops, n.Spec.Ops) ||
+				!reflect.DeepEqual(o.Spec.DependsOn, n.Spec.DependsOn) ||
+				o.Spec.PropertyValidation != n.Spec.PropertyValidation {
 				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
 					ctx, e.MetaNew, "bdv1.BOSHDeployment",
 					fmt.Sprintf("Update predicate passed for '%s'", e.MetaNew.GetName()),
@@ -122,6 +128,40 @@ func AddDeployment(ctx context.Context, config *config.Config, mgr manager.Manag
 		return errors.Wrapf(err, "Watching configmaps failed in bosh deployment controller.")
 	}
 
+	// Watch runtime configs, which apply to all BOSHDeployments in their namespace
+	runtimeConfigPredicates := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isRuntimeConfig(e.Meta.GetLabels()) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isRuntimeConfig(e.Meta.GetLabels()) },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !isRuntimeConfig(e.MetaOld.GetLabels()) && !isRuntimeConfig(e.MetaNew.GetLabels()) {
+				return false
+			}
+			oldConfigMap := e.ObjectOld.(*corev1.ConfigMap)
+			newConfigMap := e.ObjectNew.(*corev1.ConfigMap)
+
+			return !reflect.DeepEqual(oldConfigMap.Data, newConfigMap.Data) ||
+				isRuntimeConfig(e.MetaOld.GetLabels()) != isRuntimeConfig(e.MetaNew.GetLabels())
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			reconciles, err := deploymentsInNamespace(ctx, mgr.GetClient(), a.Meta.GetNamespace())
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for runtime config '%s': %v", a.Meta.GetName(), err)
+			}
+
+			for _, reconciliation := range reconciles {
+				ctxlog.NewMappingEvent(a.Object).Debug(ctx, reconciliation, "BOSHDeployment", a.Meta.GetName(), "RuntimeConfig")
+			}
+
+			return reconciles
+		}),
+	}, runtimeConfigPredicates)
+	if err != nil {
+		return errors.Wrapf(err, "Watching runtime configs failed in bosh deployment controller.")
+	}
+
 	// Watch Secrets referenced by the BOSHDeployment
 	secretPredicates := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
@@ -207,5 +247,58 @@ func AddDeployment(ctx context.Context, config *config.Config, mgr manager.Manag
 
 	}
 
+	// Watch shared link secrets, which are consumed by other deployments
+	sharedLinkPredicates := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isSharedLinkSecret(e.Meta.GetLabels()) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret := e.ObjectOld.(*corev1.Secret)
+			newSecret := e.ObjectNew.(*corev1.Secret)
+
+			return isSharedLinkSecret(newSecret.GetLabels()) && !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			reconciles, err := consumersOfSharedLink(ctx, mgr.GetClient(), a.Meta.GetLabels()[bdv1.LabelDeploymentName])
+			if err != nil {
+				ctxlog.Errorf(ctx, "Failed to calculate reconciles for shared link secret '%s': %v", a.Meta.GetName(), err)
+			}
+
+			for _, reconciliation := range reconciles {
+				ctxlog.NewMappingEvent(a.Object).Debug(ctx, reconciliation, "BOSHDeployment", a.Meta.GetName(), "SharedLink")
+			}
+
+			return reconciles
+		}),
+	}, sharedLinkPredicates)
+	if err != nil {
+		return errors.Wrapf(err, "watching shared link secrets failed in bosh deployment controller.")
+	}
+
 	return nil
 }
+
+func isRuntimeConfig(labels map[string]string) bool {
+	return labels[bdv1.LabelRuntimeConfig] == "true"
+}
+
+// deploymentsInNamespace returns reconciles for all BOSHDeployments in the namespace
+func deploymentsInNamespace(ctx context.Context, c client.Client, namespace string) ([]reconcile.Request, error) {
+	reconciles := []reconcile.Request{}
+
+	bdpls := &bdv1.BOSHDeploymentList{}
+	err := c.List(ctx, bdpls, client.InNamespace(namespace))
+	if err != nil {
+		return reconciles, errors.Wrapf(err, "listing BOSHDeployments in namespace '%s'", namespace)
+	}
+
+	for _, bdpl := range bdpls.Items {
+		reconciles = append(reconciles, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: bdpl.Namespace, Name: bdpl.Name},
+		})
+	}
+
+	return reconciles, nil
+}
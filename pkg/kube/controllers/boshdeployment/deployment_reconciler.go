@@ -3,8 +3,8 @@ package boshdeployment
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/pkg/errors"
 
@@ -23,23 +23,25 @@ import (
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/mutate"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
 	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
-	"code.cloudfoundry.org/quarks-utils/pkg/meltdown"
 	"code.cloudfoundry.org/quarks-utils/pkg/names"
 )
 
 // JobFactory creates Jobs for a given manifest
 type JobFactory interface {
-	VariableInterpolationJob(deploymentName string, manifest bdm.Manifest) (*qjv1a1.QuarksJob, error)
-	InstanceGroupManifestJob(deploymentName string, manifest bdm.Manifest, linkInfos converter.LinkInfos, initialRollout bool) (*qjv1a1.QuarksJob, error)
+	VariableInterpolationJob(deploymentName string, namespace string, manifest bdm.Manifest) (*qjv1a1.QuarksJob, error)
+	InstanceGroupManifestJob(deploymentName string, namespace string, manifest bdm.Manifest, linkInfos converter.LinkInfos, initialRollout bool, propertyValidation string) (*qjv1a1.QuarksJob, error)
 }
 
 // VariablesConverter converts BOSH variables into QuarksSecrets
 type VariablesConverter interface {
-	Variables(manifestName string, variables []bdm.Variable) ([]qsv1a1.QuarksSecret, error)
+	Variables(manifestName string, namespace string, variables []bdm.Variable) ([]qsv1a1.QuarksSecret, error)
 }
 
 // WithOps interpolates BOSH manifests and operations files to create the WithOps manifest
@@ -80,22 +82,23 @@ type ReconcileBOSHDeployment struct {
 }
 
 // Reconcile starts the deployment process for a BOSHDeployment and deploys QuarksJobs to generate required properties for instance groups and rendered BPM
-func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (result reconcile.Result, err error) {
 	// Fetch the BOSHDeployment instance
 	instance := &bdv1.BOSHDeployment{}
 
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	log.Infof(ctx, "Reconciling BOSHDeployment %s", request.NamespacedName)
-	err := r.client.Get(ctx, request.NamespacedName, instance)
+	err = r.client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			log.Debug(ctx, "Skip reconcile: BOSHDeployment not found")
+			metrics.DeleteBOSHDeploymentPhase(request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 
@@ -103,9 +106,42 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 			log.WithEvent(instance, "GetBOSHDeploymentError").Errorf(ctx, "failed to get BOSHDeployment '%s': %v", request.NamespacedName, err)
 	}
 
-	if meltdown.NewWindow(r.config.MeltdownDuration, instance.Status.LastReconcile).Contains(time.Now()) {
-		log.WithEvent(instance, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", instance.Name, r.config.MeltdownRequeueAfter)
-		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
+	defer func() {
+		if err != nil {
+			metrics.SetBOSHDeploymentPhase(instance.Namespace, instance.Name, metrics.PhaseFailed)
+		}
+	}()
+
+	ctx, span := tracing.StartForDeployment(ctx, "boshdeployment-reconcile", instance)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
+	// Wait for the BOSHDeployments this deployment depends on
+	if len(instance.Spec.DependsOn) > 0 {
+		cycle, err := r.dependencyCycle(ctx, instance)
+		if err != nil {
+			return reconcile.Result{},
+				log.WithEvent(instance, "DependencyError").Errorf(ctx, "failed to check dependencies of BOSHDeployment '%s': %v", request.NamespacedName, err)
+		}
+		if cycle != nil {
+			message := fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+			setDependenciesCondition(instance, corev1.ConditionFalse, reasonDependencyCycle, message)
+			r.updateStatus(ctx, instance)
+			metrics.SetBOSHDeploymentPhase(instance.Namespace, instance.Name, metrics.PhaseFailed)
+			log.WithEvent(instance, reasonDependencyCycle).Errorf(ctx, "BOSHDeployment '%s' can't be reconciled: %s", request.NamespacedName, message)
+			return reconcile.Result{}, nil
+		}
+
+		unready, err := r.unreadyDependencies(ctx, instance)
+		if err != nil {
+			return reconcile.Result{},
+				log.WithEvent(instance, "DependencyError").Errorf(ctx, "failed to check dependencies of BOSHDeployment '%s': %v", request.NamespacedName, err)
+		}
+		if len(unready) > 0 {
+			return r.waitForDependencies(ctx, instance, reasonDependenciesNotReady, fmt.Sprintf("waiting for dependencies: %s", strings.Join(unready, ", ")))
+		}
 	}
 
 	// Resolve the manifest with ops
@@ -116,7 +152,10 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 	}
 
 	// Get link infos containing provider name and its secret name
-	linkInfos, err := r.listLinkInfos(instance, manifest)
+	linkInfos, err := r.listLinkInfos(ctx, instance, manifest)
+	if err, ok := err.(*missingProvidersError); ok {
+		return r.waitForDependencies(ctx, instance, reasonMissingLinkProviders, err.Error())
+	}
 	if err != nil {
 		return reconcile.Result{},
 			log.WithEvent(instance, "InstanceGroupManifestError").Errorf(ctx, "failed to list quarks-link secrets for BOSHDeployment '%s': %v", request.NamespacedName, err)
@@ -132,7 +171,7 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 
 	// Create all QuarksSecret variables
 	log.Debug(ctx, "Converting BOSH manifest variables to QuarksSecret resources")
-	secrets, err := r.converter.Variables(instance.Name, manifest.Variables)
+	secrets, err := r.converter.Variables(instance.Name, instance.Namespace, manifest.Variables)
 	if err != nil {
 		return reconcile.Result{},
 			log.WithEvent(instance, "BadManifestError").Error(ctx, errors.Wrap(err, "failed to generate quarks secrets from manifest"))
@@ -149,7 +188,7 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 	}
 
 	// Apply the "Variable Interpolation" QuarksJob, which creates the desired manifest secret
-	qJob, err := r.jobFactory.VariableInterpolationJob(instance.Name, *manifest)
+	qJob, err := r.jobFactory.VariableInterpolationJob(instance.Name, instance.Namespace, *manifest)
 	if err != nil {
 		return reconcile.Result{}, log.WithEvent(instance, "DesiredManifestError").Errorf(ctx, "failed to build the desired manifest qJob: %v", err)
 	}
@@ -163,7 +202,7 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 
 	// Apply the "Instance group manifest" QuarksJob, which creates instance group manifests (ig-resolved) secrets and BPM config secrets
 	// once the "Variable Interpolation" job created the desired manifest.
-	qJob, err = r.jobFactory.InstanceGroupManifestJob(instance.Name, *manifest, linkInfos, instance.ObjectMeta.Generation == 1)
+	qJob, err = r.jobFactory.InstanceGroupManifestJob(instance.Name, instance.Namespace, *manifest, linkInfos, instance.ObjectMeta.Generation == 1, instance.Spec.PropertyValidation)
 	if err != nil {
 		return reconcile.Result{},
 			log.WithEvent(instance, "InstanceGroupManifestError").Errorf(ctx, "failed to build instance group manifest qJob: %v", err)
@@ -176,6 +215,12 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 			log.WithEvent(instance, "InstanceGroupManifestError").Errorf(ctx, "failed to create instance group manifest qJob for BOSHDeployment '%s': %v", request.NamespacedName, err)
 	}
 
+	if len(instance.Spec.DependsOn) > 0 || instance.Status.GetCondition(bdv1.ConditionDependenciesReady) != nil {
+		setDependenciesCondition(instance, corev1.ConditionTrue, reasonDependenciesAvailable, "")
+	}
+
+	metrics.SetBOSHDeploymentPhase(instance.Namespace, instance.Name, metrics.PhaseRendering)
+
 	// Update status of bdpl with the timestamp of the last reconcile
 	now := metav1.Now()
 	instance.Status.LastReconcile = &now
@@ -189,6 +234,23 @@ func (r *ReconcileBOSHDeployment) Reconcile(request reconcile.Request) (reconcil
 	return reconcile.Result{}, nil
 }
 
+// waitForDependencies records why the deployment can't be reconciled yet and requeues it
+func (r *ReconcileBOSHDeployment) waitForDependencies(ctx context.Context, instance *bdv1.BOSHDeployment, reason string, message string) (reconcile.Result, error) {
+	setDependenciesCondition(instance, corev1.ConditionFalse, reason, message)
+	r.updateStatus(ctx, instance)
+	metrics.SetBOSHDeploymentPhase(instance.Namespace, instance.Name, metrics.PhaseWaiting)
+	log.WithEvent(instance, reason).Infof(ctx, "BOSHDeployment '%s/%s' %s, requeue after %s", instance.Namespace, instance.Name, message, dependencyRequeueAfter)
+	return reconcile.Result{RequeueAfter: dependencyRequeueAfter}, nil
+}
+
+// updateStatus updates the status of the instance, errors are only logged
+func (r *ReconcileBOSHDeployment) updateStatus(ctx context.Context, instance *bdv1.BOSHDeployment) {
+	err := r.client.Status().Update(ctx, instance)
+	if err != nil {
+		log.WithEvent(instance, "UpdateError").Errorf(ctx, "failed to update status of bdpl '%s' (%v): %s", instance.Name, instance.ResourceVersion, err)
+	}
+}
+
 // resolveManifest resolves manifest with ops manifest
 func (r *ReconcileBOSHDeployment) resolveManifest(ctx context.Context, instance *bdv1.BOSHDeployment) (*bdm.Manifest, error) {
 	log.Debug(ctx, "Resolving manifest")
@@ -227,6 +289,8 @@ func (r *ReconcileBOSHDeployment) createManifestWithOps(ctx context.Context, ins
 		},
 	}
 
+	tracing.Annotate(ctx, manifestSecret)
+
 	// Set ownership reference
 	if err := r.setReference(instance, manifestSecret, r.scheme); err != nil {
 		return nil, log.WithEvent(instance, "ManifestWithOpsRefError").Errorf(ctx, "failed to set ownerReference for Secret '%s': %v", manifestSecretName, err)
@@ -249,7 +313,22 @@ func (r *ReconcileBOSHDeployment) createQuarksJob(ctx context.Context, instance
 		return errors.Errorf("failed to set ownerReference for QuarksJob '%s': %v", qJob.GetName(), err)
 	}
 
-	op, err := controllerutil.CreateOrUpdate(ctx, r.client, qJob, mutate.QuarksJobMutateFn(qJob))
+	// The output secrets carry the trace context as a label, since
+	// quarks-job doesn't copy annotations
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" && qJob.Spec.Output != nil {
+		if qJob.Spec.Output.SecretLabels == nil {
+			qJob.Spec.Output.SecretLabels = map[string]string{}
+		}
+		qJob.Spec.Output.SecretLabels[tracing.AnnotationTraceParent] = traceParent
+	}
+
+	mutateFn := mutate.QuarksJobMutateFn(qJob)
+	op, err := controllerutil.CreateOrUpdate(ctx, r.client, qJob, func() error {
+		err := mutateFn()
+		// The mutate func doesn't reset existing annotations
+		tracing.Annotate(ctx, qJob)
+		return err
+	})
 	if err != nil {
 		return errors.Wrapf(err, "creating or updating QuarksJob '%s'", qJob.Name)
 	}
@@ -261,7 +340,7 @@ func (r *ReconcileBOSHDeployment) createQuarksJob(ctx context.Context, instance
 
 // listLinkInfos returns a LinkInfos containing link providers if needed
 // and updates `quarks_links` properties
-func (r *ReconcileBOSHDeployment) listLinkInfos(instance *bdv1.BOSHDeployment, manifest *bdm.Manifest) (converter.LinkInfos, error) {
+func (r *ReconcileBOSHDeployment) listLinkInfos(ctx context.Context, instance *bdv1.BOSHDeployment, manifest *bdm.Manifest) (converter.LinkInfos, error) {
 	linkInfos := converter.LinkInfos{}
 
 	// find all missing providers in the manifest, so we can look for secrets
@@ -357,8 +436,27 @@ func (r *ReconcileBOSHDeployment) listLinkInfos(instance *bdv1.BOSHDeployment, m
 		}
 	}
 
+	// links consumed from other deployments via `deployment:`
+	providers := map[string]string{}
+	for name := range manifest.ListProviders() {
+		providers[name] = "the manifest"
+	}
+	for _, info := range linkInfos {
+		providers[info.ProviderName] = fmt.Sprintf("link secret '%s'", info.SecretName)
+	}
+	crossLinkInfos, crossQuarksLinks, missingCross, err := r.resolveCrossDeploymentLinks(ctx, instance, manifest, providers)
+	if err != nil {
+		return linkInfos, errors.Wrapf(err, "failed to resolve links from other deployments for '%s'", instance.Name)
+	}
+	linkInfos = append(linkInfos, crossLinkInfos...)
+	for name, link := range crossQuarksLinks {
+		quarksLinks[name] = link
+	}
+	missingPs = append(missingPs, missingCross...)
+
 	if len(missingPs) != 0 {
-		return linkInfos, errors.New(fmt.Sprintf("missing link secrets for providers: %s", strings.Join(missingPs, ", ")))
+		sort.Strings(missingPs)
+		return linkInfos, &missingProvidersError{providers: missingPs}
 	}
 
 	if len(quarksLinks) != 0 {
@@ -423,6 +521,7 @@ func (r *ReconcileBOSHDeployment) createQuarksSecrets(ctx context.Context, manif
 			err = log.WithEvent(manifestSecret, "OwnershipError").Errorf(ctx, "failed to set ownership for %s: %v", variable.Name, err)
 			return err
 		}
+		tracing.Annotate(ctx, &variable)
 
 		op, err := controllerutil.CreateOrUpdate(ctx, r.client, &variable, mutate.QuarksSecretMutateFn(&variable))
 		if err != nil {
@@ -9,8 +9,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +32,10 @@ import (
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers"
 	cfd "code.cloudfoundry.org/cf-operator/pkg/kube/controllers/boshdeployment"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/fakes"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
 	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
 	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
@@ -213,6 +219,7 @@ var _ = Describe("ReconcileBoshDeployment", func() {
 
 				// check for events
 				Expect(<-recorder.Events).To(ContainSubstring("WithOpsManifestError"))
+				Expect(testutil.ToFloat64(metrics.BOSHDeploymentPhase.WithLabelValues("default", deploymentName, metrics.PhaseFailed))).To(Equal(1.0))
 			})
 		})
 
@@ -398,6 +405,368 @@ var _ = Describe("ReconcileBoshDeployment", func() {
 				})
 			})
 
+			Context("when tracing is enabled", func() {
+				var created []metav1.Object
+
+				BeforeEach(func() {
+					tracing.SetEnabled(true)
+					instance.UID = "a1b2c3"
+					instance.Generation = 2
+					igQJob.Spec.Output = &qjv1a1.Output{}
+					kubeConverter.VariablesReturns([]qsv1a1.QuarksSecret{
+						{ObjectMeta: metav1.ObjectMeta{Name: "fake-variable", Namespace: "default"}},
+					}, nil)
+
+					client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+						switch object := object.(type) {
+						case *bdv1.BOSHDeployment:
+							instance.DeepCopyInto(object)
+							return nil
+						}
+						return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+					})
+
+					created = []metav1.Object{}
+					client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+						created = append(created, object.(metav1.Object))
+						return nil
+					})
+				})
+
+				AfterEach(func() {
+					tracing.SetEnabled(false)
+				})
+
+				It("propagates the trace context of the deployment to the generated resources", func() {
+					_, err := reconciler.Reconcile(request)
+					Expect(err).NotTo(HaveOccurred())
+
+					traceParent := tracing.DeploymentSpanContext(instance).String()
+					Expect(created).To(HaveLen(4))
+					for _, object := range created {
+						Expect(object.GetAnnotations()).To(HaveKeyWithValue(tracing.AnnotationTraceParent, traceParent), object.GetName())
+					}
+					Expect(igQJob.Spec.Output.SecretLabels).To(HaveKeyWithValue(tracing.AnnotationTraceParent, traceParent))
+				})
+			})
+
+			Context("when the deployment depends on other deployments", func() {
+				var (
+					deployments  map[string]*bdv1.BOSHDeployment
+					statefulSets []appsv1.StatefulSet
+					statusWriter *fakes.FakeStatusWriter
+					replicas     int32
+				)
+
+				conditionOf := func(i int) *bdv1.Condition {
+					_, object, _ := statusWriter.UpdateArgsForCall(i)
+					return object.(*bdv1.BOSHDeployment).Status.GetCondition(bdv1.ConditionDependenciesReady)
+				}
+
+				BeforeEach(func() {
+					now := metav1.Now()
+					replicas = 2
+					instance.Spec.DependsOn = []bdv1.Dependency{{Name: "database"}}
+					deployments = map[string]*bdv1.BOSHDeployment{
+						"database": {
+							ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "default"},
+							Status:     bdv1.BOSHDeploymentStatus{LastReconcile: &now},
+						},
+					}
+					statefulSets = []appsv1.StatefulSet{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "database-pxc", Namespace: "default"},
+							Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+							Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+						},
+					}
+
+					client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+						switch object := object.(type) {
+						case *bdv1.BOSHDeployment:
+							if nn.Name == deploymentName {
+								instance.DeepCopyInto(object)
+								return nil
+							}
+							bdpl, ok := deployments[nn.Name]
+							if !ok {
+								return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+							}
+							bdpl.DeepCopyInto(object)
+						case *qjv1a1.QuarksJob:
+							return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+						}
+
+						return nil
+					})
+					client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+						switch object := object.(type) {
+						case *appsv1.StatefulSetList:
+							list := appsv1.StatefulSetList{Items: statefulSets}
+							list.DeepCopyInto(object)
+						}
+
+						return nil
+					})
+
+					statusWriter = &fakes.FakeStatusWriter{}
+					client.StatusCalls(func() crc.StatusWriter { return statusWriter })
+				})
+
+				It("reconciles once the dependencies are ready", func() {
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(Equal(reconcile.Result{}))
+					Expect(jobFactory.InstanceGroupManifestJobCallCount()).To(Equal(1))
+
+					Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+					Expect(conditionOf(0).Status).To(Equal(corev1.ConditionTrue))
+					Expect(testutil.ToFloat64(metrics.BOSHDeploymentPhase.WithLabelValues("default", deploymentName, metrics.PhaseRendering))).To(Equal(1.0))
+				})
+
+				It("waits for dependencies which don't exist", func() {
+					delete(deployments, "database")
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(jobFactory.VariableInterpolationJobCallCount()).To(Equal(0))
+					Expect(conditionOf(0).Reason).To(Equal("DependenciesNotReady"))
+					Expect(conditionOf(0).Message).To(Equal("waiting for dependencies: database (not found)"))
+					Expect(<-recorder.Events).To(ContainSubstring("DependenciesNotReady"))
+					Expect(testutil.ToFloat64(metrics.BOSHDeploymentPhase.WithLabelValues("default", deploymentName, metrics.PhaseWaiting))).To(Equal(1.0))
+				})
+
+				It("waits for dependencies which were not deployed yet", func() {
+					deployments["database"].Status.LastReconcile = nil
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(conditionOf(0).Status).To(Equal(corev1.ConditionFalse))
+					Expect(conditionOf(0).Message).To(ContainSubstring("database (not deployed)"))
+				})
+
+				It("waits for dependencies which instance groups are not ready", func() {
+					statefulSets[0].Status.ReadyReplicas = 1
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(conditionOf(0).Message).To(ContainSubstring("database (not ready)"))
+				})
+
+				It("doesn't wait for instance groups if the dependency only needs to be deployed", func() {
+					instance.Spec.DependsOn[0].Readiness = bdv1.DependencyDeployed
+					statefulSets = nil
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(Equal(reconcile.Result{}))
+					Expect(jobFactory.InstanceGroupManifestJobCallCount()).To(Equal(1))
+				})
+
+				It("detects dependency cycles", func() {
+					deployments["database"].Spec.DependsOn = []bdv1.Dependency{{Name: "storage"}}
+					deployments["storage"] = &bdv1.BOSHDeployment{
+						ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "default"},
+						Spec: bdv1.BOSHDeploymentSpec{
+							DependsOn: []bdv1.Dependency{{Name: deploymentName}},
+						},
+					}
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result).To(Equal(reconcile.Result{}))
+					Expect(jobFactory.VariableInterpolationJobCallCount()).To(Equal(0))
+					Expect(conditionOf(0).Reason).To(Equal("DependencyCycle"))
+					Expect(conditionOf(0).Message).To(Equal("dependency cycle detected: foo -> database -> storage -> foo"))
+				})
+			})
+
+			Context("when the manifest consumes links from other deployments", func() {
+				var (
+					sharedSecrets      []corev1.Secret
+					consumerNamespaces string
+				)
+
+				sharedSecret := func(namespace string) corev1.Secret {
+					return corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "link-db-shared-mysql-mysql",
+							Namespace: namespace,
+							Labels: map[string]string{
+								bdv1.LabelDeploymentName: "db",
+								bdv1.LabelSharedLink:     "true",
+							},
+						},
+						Data: map[string][]byte{
+							"link": []byte(`{"name":"mysql","type":"mysql","address":"db-mysql","instances":[{"address":"db-mysql-0","index":0}],"properties":{"port":"3306"}}`),
+						},
+					}
+				}
+
+				BeforeEach(func() {
+					manifest.InstanceGroups[0].Jobs[0].Consumes = map[string]interface{}{
+						"database": map[string]interface{}{
+							"from":       "mysql",
+							"deployment": "db",
+						},
+					}
+					sharedSecrets = []corev1.Secret{sharedSecret("default")}
+					consumerNamespaces = "default"
+					config.OperatorNamespace = "cf-operator"
+					watchnamespaces.SetNamespaces([]string{"default", "databases", "other"})
+
+					client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+						switch object := object.(type) {
+						case *bdv1.BOSHDeployment:
+							if nn.Name != "db" {
+								instance.DeepCopyInto(object)
+								break
+							}
+							provider := bdv1.BOSHDeployment{
+								ObjectMeta: metav1.ObjectMeta{
+									Name:        "db",
+									Namespace:   nn.Namespace,
+									Annotations: map[string]string{bdv1.AnnotationLinkConsumerNamespaces: consumerNamespaces},
+								},
+							}
+							provider.DeepCopyInto(object)
+						case *corev1.Namespace:
+							ns := corev1.Namespace{
+								ObjectMeta: metav1.ObjectMeta{
+									Name:   nn.Name,
+									Labels: map[string]string{webhook.LabelWatchNamespace: "cf-operator"},
+								},
+							}
+							if nn.Name == "unwatched" {
+								ns.Labels = nil
+							}
+							ns.DeepCopyInto(object)
+						case *qjv1a1.QuarksJob, *corev1.Secret:
+							return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+						}
+
+						return nil
+					})
+					client.ListCalls(func(context context.Context, object runtime.Object, _ ...crc.ListOption) error {
+						switch object := object.(type) {
+						case *corev1.SecretList:
+							secretList := corev1.SecretList{Items: sharedSecrets}
+							secretList.DeepCopyInto(object)
+						}
+
+						return nil
+					})
+				})
+
+				It("copies the shared link secret and passes it to the QJob", func() {
+					_, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, _, m, linkInfos, _, _ := jobFactory.InstanceGroupManifestJobArgsForCall(0)
+					Expect(linkInfos).To(Equal(converter.LinkInfos{
+						{
+							SecretName:   "link-foo-consumes-db-mysql",
+							ProviderName: "mysql",
+							ProviderType: "mysql",
+						},
+					}))
+					Expect(m.Properties["quarks_links"]).To(Equal(map[string]bdm.QuarksLink{
+						"mysql": {
+							Type:      "mysql",
+							Address:   "db-mysql",
+							Instances: []bdm.JobInstance{{Address: "db-mysql-0"}},
+						},
+					}))
+
+					var linkSecret *corev1.Secret
+					for i := 0; i < client.CreateCallCount(); i++ {
+						_, object, _ := client.CreateArgsForCall(i)
+						if secret, ok := object.(*corev1.Secret); ok && secret.Name == "link-foo-consumes-db-mysql" {
+							linkSecret = secret
+						}
+					}
+					Expect(linkSecret).ToNot(BeNil())
+					Expect(linkSecret.StringData).To(Equal(map[string]string{"port": "3306"}))
+					Expect(linkSecret.Labels).To(HaveKeyWithValue(bdv1.LabelLinkProviderDeployment, "db"))
+				})
+
+				It("qualifies the addresses of links from other namespaces", func() {
+					sharedSecrets = []corev1.Secret{sharedSecret("databases")}
+
+					_, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, _, m, _, _, _ := jobFactory.InstanceGroupManifestJobArgsForCall(0)
+					link := m.Properties["quarks_links"].(map[string]bdm.QuarksLink)["mysql"]
+					Expect(link.Address).To(HavePrefix("db-mysql.databases.svc."))
+					Expect(link.Instances[0].Address).To(HavePrefix("db-mysql-0.databases.svc."))
+				})
+
+				It("prefers the shared link from the same namespace", func() {
+					sharedSecrets = []corev1.Secret{sharedSecret("databases"), sharedSecret("default")}
+
+					_, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, _, m, _, _, _ := jobFactory.InstanceGroupManifestJobArgsForCall(0)
+					Expect(m.Properties["quarks_links"].(map[string]bdm.QuarksLink)["mysql"].Address).To(Equal("db-mysql"))
+				})
+
+				It("fails if the shared link exists in multiple other namespaces", func() {
+					sharedSecrets = []corev1.Secret{sharedSecret("databases"), sharedSecret("other")}
+
+					_, err := reconciler.Reconcile(request)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("found in multiple namespaces: databases, other"))
+				})
+
+				AfterEach(func() {
+					watchnamespaces.SetNamespaces(nil)
+				})
+
+				It("ignores links of deployments, which don't share them with the namespace", func() {
+					sharedSecrets = []corev1.Secret{sharedSecret("databases")}
+					consumerNamespaces = "other, staging"
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(jobFactory.InstanceGroupManifestJobCallCount()).To(Equal(0))
+				})
+
+				It("ignores links from namespaces, which are not watched", func() {
+					sharedSecrets = []corev1.Secret{sharedSecret("unwatched")}
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(jobFactory.InstanceGroupManifestJobCallCount()).To(Equal(0))
+				})
+
+				It("fails if the consumed provider collides with a provider of the manifest", func() {
+					manifest.InstanceGroups[0].Jobs[0].Provides = map[string]interface{}{
+						"mysql": map[string]interface{}{},
+					}
+
+					_, err := reconciler.Reconcile(request)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("link provider 'mysql' of deployment 'db' collides with the provider of the same name of the manifest"))
+				})
+
+				It("waits for the provider deployment to share the link", func() {
+					sharedSecrets = nil
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(jobFactory.InstanceGroupManifestJobCallCount()).To(Equal(0))
+					Expect(<-recorder.Events).To(ContainSubstring("missing link secrets for providers: db/mysql"))
+				})
+			})
+
 			Context("when the manifest contains explicit links", func() {
 				var bazSecret *corev1.Secret
 
@@ -480,14 +849,14 @@ var _ = Describe("ReconcileBoshDeployment", func() {
 				It("passes link secrets to QJobs", func() {
 					_, err := reconciler.Reconcile(request)
 					Expect(err).ToNot(HaveOccurred())
-					_, _, linksSecrets, _ := jobFactory.InstanceGroupManifestJobArgsForCall(0)
+					_, _, _, linksSecrets, _, _ := jobFactory.InstanceGroupManifestJobArgsForCall(0)
 					Expect(linksSecrets).To(Equal(converter.LinkInfos{
 						{
 							SecretName:   "baz-sec",
 							ProviderName: "baz",
 						},
 					}))
-					_, _, linksSecrets, _ = jobFactory.InstanceGroupManifestJobArgsForCall(0)
+					_, _, _, linksSecrets, _, _ = jobFactory.InstanceGroupManifestJobArgsForCall(0)
 					Expect(linksSecrets).To(Equal(converter.LinkInfos{
 						{
 							SecretName:   "baz-sec",
@@ -510,10 +879,22 @@ var _ = Describe("ReconcileBoshDeployment", func() {
 					Expect(err.Error()).To(ContainSubstring("listing secrets for link in deployment"))
 				})
 
-				It("handles an error on missing providers when the secret doesn't have the annotation", func() {
+				It("waits for missing providers when the secret doesn't have the annotation", func() {
+					statusWriter := &fakes.FakeStatusWriter{}
+					client.StatusCalls(func() crc.StatusWriter { return statusWriter })
 					bazSecret.Annotations = nil
-					_, err := reconciler.Reconcile(request)
-					Expect(err.Error()).To(ContainSubstring("missing link secrets for providers"))
+
+					result, err := reconciler.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(jobFactory.InstanceGroupManifestJobCallCount()).To(Equal(0))
+
+					Expect(statusWriter.UpdateCallCount()).To(Equal(1))
+					_, object, _ := statusWriter.UpdateArgsForCall(0)
+					condition := object.(*bdv1.BOSHDeployment).Status.GetCondition(bdv1.ConditionDependenciesReady)
+					Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+					Expect(condition.Reason).To(Equal("MissingLinkProviders"))
+					Expect(condition.Message).To(Equal("missing link secrets for providers: baz"))
 				})
 
 				It("handles an error on duplicated secrets of provider when duplicated secrets match the annotation", func() {
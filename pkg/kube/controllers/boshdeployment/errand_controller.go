@@ -0,0 +1,123 @@
+package boshdeployment
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/podlogs"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
+	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddErrand creates a new errand controller to watch for BOSHDeployments,
+// which request errand runs by annotation or schedule, and for the pods of
+// errands, whose results are recorded in the BOSHDeployment's status.
+func AddErrand(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "errand-reconciler", mgr.GetEventRecorderFor("errand-recorder"))
+
+	podLogs, err := podlogs.NewPodLogs(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "Adding errand controller to manager failed.")
+	}
+
+	r := NewErrandReconciler(ctx, config, mgr, podLogs)
+
+	// Create a new controller
+	c, err := ratelimit.NewController(ctx, "errand-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxBoshDeploymentWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding errand controller to manager failed.")
+	}
+
+	// Trigger when errands are requested or their schedule changes
+	p := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			bdpl := e.Object.(*bdv1.BOSHDeployment)
+			_, requested := bdpl.Annotations[bdv1.AnnotationRunErrand]
+			return requested || len(bdpl.Spec.Errands) > 0
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o := e.ObjectOld.(*bdv1.BOSHDeployment)
+			n := e.ObjectNew.(*bdv1.BOSHDeployment)
+
+			if n.Annotations[bdv1.AnnotationRunErrand] != "" && o.Annotations[bdv1.AnnotationRunErrand] != n.Annotations[bdv1.AnnotationRunErrand] ||
+				!reflect.DeepEqual(o.Spec.Errands, n.Spec.Errands) {
+				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
+					ctx, e.MetaNew, "bdv1.BOSHDeployment",
+					fmt.Sprintf("Update predicate passed for '%s'", e.MetaNew.GetName()),
+				)
+				return true
+			}
+			return false
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &bdv1.BOSHDeployment{}}, &handler.EnqueueRequestForObject{}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching bosh deployment failed in errand controller.")
+	}
+
+	// Watch errand pods, which terminated
+	podPredicates := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			pod := e.Object.(*corev1.Pod)
+			return isErrandPod(pod) && podResult(pod) != nil
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o := e.ObjectOld.(*corev1.Pod)
+			n := e.ObjectNew.(*corev1.Pod)
+
+			return isErrandPod(n) && podResult(n) != nil &&
+				!reflect.DeepEqual(o.Status.ContainerStatuses, n.Status.ContainerStatuses)
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(a handler.MapObject) []reconcile.Request {
+			pod := a.Object.(*corev1.Pod)
+			request := reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      pod.Labels[bdm.LabelDeploymentName],
+				Namespace: pod.Namespace,
+			}}
+			ctxlog.NewMappingEvent(a.Object).Debug(ctx, request, "BOSHDeployment", a.Meta.GetName(), "errand-pod")
+			return []reconcile.Request{request}
+		}),
+	}, podPredicates)
+	if err != nil {
+		return errors.Wrapf(err, "Watching pods failed in errand controller.")
+	}
+
+	return nil
+}
+
+// isErrandPod returns true for pods of the errand QuarksJobs of a
+// BOSHDeployment
+func isErrandPod(pod *corev1.Pod) bool {
+	for _, label := range []string{qjv1a1.LabelQJobName, bdm.LabelDeploymentName, bdm.LabelInstanceGroupName} {
+		if _, ok := pod.Labels[label]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,375 @@
+package boshdeployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/bpmconverter"
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/cron"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+const (
+	// maxErrandRuns is the number of runs kept in the status of each errand
+	maxErrandRuns = 10
+	// errandLogTailLines is the number of log lines recorded for a run
+	errandLogTailLines = 20
+)
+
+// LogTailer reads the last lines of container logs
+type LogTailer interface {
+	TailLog(ctx context.Context, namespace, podName, containerName string, lines int64, previous bool) (string, error)
+}
+
+var _ reconcile.Reconciler = &ReconcileErrand{}
+
+// NewErrandReconciler returns a new reconcile.Reconciler
+func NewErrandReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, logTailer LogTailer) reconcile.Reconciler {
+	return &ReconcileErrand{
+		ctx:       ctx,
+		config:    config,
+		client:    mgr.GetClient(),
+		logTailer: logTailer,
+	}
+}
+
+// ReconcileErrand runs the errands of a BOSHDeployment and records their runs
+type ReconcileErrand struct {
+	ctx       context.Context
+	config    *config.Config
+	client    crc.Client
+	logTailer LogTailer
+}
+
+// errandResult is the outcome of an errand's pod
+type errandResult struct {
+	completionTime metav1.Time
+	exitCode       int32
+	container      string
+	// previous is set, if the failed container was restarted
+	previous bool
+}
+
+// Reconcile records the results of terminated errand pods, runs the errands
+// requested by the run-errand annotation and those whose schedule is due.
+// It requeues the BOSHDeployment for the next scheduled run.
+func (r *ReconcileErrand) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
+	defer cancel()
+
+	log.Infof(ctx, "Reconciling errands of BOSHDeployment '%s'", request.NamespacedName)
+	bdpl := &bdv1.BOSHDeployment{}
+	err := r.client.Get(ctx, request.NamespacedName, bdpl)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug(ctx, "Skip reconcile: BOSHDeployment not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	statusChanged, err := r.recordResults(ctx, bdpl)
+	if err != nil {
+		return reconcile.Result{},
+			log.WithEvent(bdpl, "ErrandStatusError").Errorf(ctx, "Failed to record errand results of BOSHDeployment '%s': %v", bdpl.Name, err)
+	}
+
+	// Errands which failed to trigger are kept in the annotation and retried
+	requested, hasAnnotation := bdpl.Annotations[bdv1.AnnotationRunErrand]
+	pending := []string{}
+	var triggerErr error
+	for _, errand := range strings.Split(requested, ",") {
+		errand = strings.TrimSpace(errand)
+		if errand == "" {
+			continue
+		}
+
+		err := r.trigger(ctx, bdpl, errand, bdv1.ErrandTriggerAnnotation)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				log.WithEvent(bdpl, "RunErrandError").Errorf(ctx, "Errand '%s' of BOSHDeployment '%s' not found", errand, bdpl.Name)
+				continue
+			}
+			triggerErr = log.WithEvent(bdpl, "RunErrandError").Errorf(ctx, "Failed to run errand '%s' of BOSHDeployment '%s': %v", errand, bdpl.Name, err)
+			pending = append(pending, errand)
+			continue
+		}
+		statusChanged = true
+	}
+
+	requeueAfter, scheduleChanged := r.runScheduled(ctx, bdpl)
+
+	if statusChanged || scheduleChanged {
+		err = r.client.Status().Update(ctx, bdpl)
+		if err != nil {
+			return reconcile.Result{},
+				log.WithEvent(bdpl, "UpdateError").Errorf(ctx, "Failed to update errand status on BOSHDeployment '%s' (%v): %s", bdpl.Name, bdpl.ResourceVersion, err)
+		}
+	}
+
+	if hasAnnotation {
+		if len(pending) == 0 {
+			delete(bdpl.Annotations, bdv1.AnnotationRunErrand)
+		} else {
+			bdpl.Annotations[bdv1.AnnotationRunErrand] = strings.Join(pending, ",")
+		}
+		err = r.client.Update(ctx, bdpl)
+		if err != nil {
+			return reconcile.Result{},
+				log.WithEvent(bdpl, "UpdateError").Errorf(ctx, "Failed to update run-errand annotation on BOSHDeployment '%s' (%v): %s", bdpl.Name, bdpl.ResourceVersion, err)
+		}
+	}
+
+	if triggerErr != nil {
+		return reconcile.Result{}, triggerErr
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// trigger runs the errand's QuarksJob and adds a run to the errand's status.
+// Errands, which are already triggered, are not triggered again.
+func (r *ReconcileErrand) trigger(ctx context.Context, bdpl *bdv1.BOSHDeployment, errand string, trigger string) error {
+	qJob := &qjv1a1.QuarksJob{}
+	qJobName := fmt.Sprintf("%s-%s", bdpl.Name, errand)
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: bdpl.Namespace, Name: qJobName}, qJob)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get QuarksJob '%s'", qJobName)
+	}
+
+	if qJob.Spec.Trigger.Strategy == qjv1a1.TriggerNow {
+		log.Debugf(ctx, "Errand '%s' of BOSHDeployment '%s' is already triggered", errand, bdpl.Name)
+		return nil
+	}
+
+	log.Infof(ctx, "Running errand '%s' of BOSHDeployment '%s'", errand, bdpl.Name)
+	qJob.Spec.Trigger.Strategy = qjv1a1.TriggerNow
+	err = r.client.Update(ctx, qJob)
+	if err != nil {
+		return errors.Wrapf(err, "failed to trigger QuarksJob '%s'", qJobName)
+	}
+
+	status := errandStatus(bdpl, errand)
+	addRun(status, bdv1.ErrandRun{Trigger: trigger, StartTime: metav1.Now()})
+
+	return nil
+}
+
+// runScheduled triggers the errands whose schedule is due. It returns the
+// duration until the next scheduled run, or zero if there is none.
+func (r *ReconcileErrand) runScheduled(ctx context.Context, bdpl *bdv1.BOSHDeployment) (time.Duration, bool) {
+	var requeueAfter time.Duration
+	changed := false
+
+	for _, errand := range bdpl.Spec.Errands {
+		if errand.Schedule == "" {
+			continue
+		}
+
+		schedule, err := cron.Parse(errand.Schedule)
+		if err != nil {
+			log.WithEvent(bdpl, "ErrandScheduleError").Errorf(ctx, "Invalid schedule of errand '%s': %v", errand.Name, err)
+			continue
+		}
+
+		now := time.Now()
+		status := errandStatus(bdpl, errand.Name)
+		// Runs, which were missed before the schedule was added, are skipped
+		if status.LastScheduleTime == nil {
+			status.LastScheduleTime = &metav1.Time{Time: now}
+			changed = true
+		}
+
+		next := schedule.Next(status.LastScheduleTime.Time)
+		if next.IsZero() {
+			continue
+		}
+
+		if !now.Before(next) {
+			err := r.trigger(ctx, bdpl, errand.Name, bdv1.ErrandTriggerSchedule)
+			if err != nil {
+				// Retried when the BOSHDeployment is requeued
+				log.WithEvent(bdpl, "RunErrandError").Errorf(ctx, "Failed to run scheduled errand '%s' of BOSHDeployment '%s': %v", errand.Name, bdpl.Name, err)
+				next = now.Add(time.Minute)
+			} else {
+				status.LastScheduleTime = &metav1.Time{Time: now}
+				changed = true
+				next = schedule.Next(now)
+			}
+		}
+
+		if wait := next.Sub(now); requeueAfter == 0 || wait < requeueAfter {
+			requeueAfter = wait
+		}
+	}
+
+	return requeueAfter, changed
+}
+
+// recordResults adds the results of terminated errand pods to the runs in
+// the status
+func (r *ReconcileErrand) recordResults(ctx context.Context, bdpl *bdv1.BOSHDeployment) (bool, error) {
+	hasQJobName, err := labels.NewRequirement(qjv1a1.LabelQJobName, selection.Exists, nil)
+	if err != nil {
+		return false, err
+	}
+	selector := labels.SelectorFromSet(labels.Set{bdm.LabelDeploymentName: bdpl.Name}).Add(*hasQJobName)
+
+	podList := &corev1.PodList{}
+	err = r.client.List(ctx, podList,
+		crc.InNamespace(bdpl.Namespace),
+		crc.MatchingLabelsSelector{Selector: selector},
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list errand pods")
+	}
+
+	changed := false
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !isErrandPod(pod) {
+			continue
+		}
+		result := podResult(pod)
+		if result == nil {
+			continue
+		}
+
+		errand := pod.Labels[bdm.LabelInstanceGroupName]
+		if r.recordResult(ctx, bdpl, errand, pod, result) {
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// recordResult updates the run of the pod. Pods are assigned to the latest
+// run, which started before them. Pods of errands, which were triggered on
+// the QuarksJob directly, get a new run.
+func (r *ReconcileErrand) recordResult(ctx context.Context, bdpl *bdv1.BOSHDeployment, errand string, pod *corev1.Pod, result *errandResult) bool {
+	status := errandStatus(bdpl, errand)
+
+	run := findRun(status, pod)
+	if run == nil {
+		if len(status.Runs) > 0 && pod.CreationTimestamp.Before(&status.Runs[len(status.Runs)-1].StartTime) {
+			// Pods of runs, which are no longer in the history
+			return false
+		}
+		run = addRun(status, bdv1.ErrandRun{Trigger: bdv1.ErrandTriggerExternal, StartTime: pod.CreationTimestamp})
+	}
+
+	if run.Pod == pod.Name && run.ExitCode != nil && *run.ExitCode == result.exitCode &&
+		run.CompletionTime != nil && run.CompletionTime.Equal(&result.completionTime) {
+		return false
+	}
+
+	exitCode := result.exitCode
+	completionTime := result.completionTime
+	run.Pod = pod.Name
+	run.ExitCode = &exitCode
+	run.CompletionTime = &completionTime
+
+	logTail, err := r.logTailer.TailLog(ctx, pod.Namespace, pod.Name, result.container, errandLogTailLines, result.previous)
+	if err != nil {
+		log.Debugf(ctx, "Failed to read log of errand pod '%s': %v", pod.Name, err)
+	}
+	run.LogTail = logTail
+
+	if exitCode != 0 {
+		log.WithEvent(bdpl, "ErrandFailed").Infof(ctx, "Errand '%s' failed in pod '%s' with exit code %d", errand, pod.Name, exitCode)
+	}
+	return true
+}
+
+// findRun returns the run of the pod
+func findRun(status *bdv1.ErrandStatus, pod *corev1.Pod) *bdv1.ErrandRun {
+	for i := range status.Runs {
+		if status.Runs[i].Pod == pod.Name {
+			return &status.Runs[i]
+		}
+	}
+	for i := len(status.Runs) - 1; i >= 0; i-- {
+		run := &status.Runs[i]
+		if run.Pod == "" && !pod.CreationTimestamp.Time.Before(run.StartTime.Time) {
+			return run
+		}
+	}
+	return nil
+}
+
+// errandStatus returns the status of the errand, which is added if missing
+func errandStatus(bdpl *bdv1.BOSHDeployment, errand string) *bdv1.ErrandStatus {
+	for i := range bdpl.Status.Errands {
+		if bdpl.Status.Errands[i].Name == errand {
+			return &bdpl.Status.Errands[i]
+		}
+	}
+	bdpl.Status.Errands = append(bdpl.Status.Errands, bdv1.ErrandStatus{Name: errand})
+	return &bdpl.Status.Errands[len(bdpl.Status.Errands)-1]
+}
+
+// addRun appends the run and drops the oldest runs
+func addRun(status *bdv1.ErrandStatus, run bdv1.ErrandRun) *bdv1.ErrandRun {
+	status.Runs = append(status.Runs, run)
+	if len(status.Runs) > maxErrandRuns {
+		status.Runs = status.Runs[len(status.Runs)-maxErrandRuns:]
+	}
+	return &status.Runs[len(status.Runs)-1]
+}
+
+// podResult returns the result of the errand's pod, or nil if the errand is
+// still running. The errand failed, if one of its containers failed, even if
+// it's restarted. It succeeded, once all job containers terminated
+// successfully.
+func podResult(pod *corev1.Pod) *errandResult {
+	var result *errandResult
+	running := false
+	for _, status := range pod.Status.ContainerStatuses {
+		// The logs sidecar doesn't terminate with the errand
+		if status.Name == bpmconverter.LogsContainerName {
+			continue
+		}
+
+		terminated := status.State.Terminated
+		if terminated == nil {
+			if last := status.LastTerminationState.Terminated; last != nil && last.ExitCode != 0 {
+				return &errandResult{completionTime: last.FinishedAt, exitCode: last.ExitCode, container: status.Name, previous: true}
+			}
+			running = true
+			continue
+		}
+
+		if terminated.ExitCode != 0 {
+			return &errandResult{completionTime: terminated.FinishedAt, exitCode: terminated.ExitCode, container: status.Name}
+		}
+
+		// The log tail of a successful run is the one of the last container
+		if result == nil || result.completionTime.Before(&terminated.FinishedAt) {
+			result = &errandResult{completionTime: terminated.FinishedAt, container: status.Name}
+		}
+	}
+
+	if running {
+		return nil
+	}
+	return result
+}
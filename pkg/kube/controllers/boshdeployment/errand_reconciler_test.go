@@ -0,0 +1,327 @@
+package boshdeployment_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers"
+	cfd "code.cloudfoundry.org/cf-operator/pkg/kube/controllers/boshdeployment"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/fakes"
+	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileErrand", func() {
+	var (
+		manager    *fakes.FakeManager
+		reconciler reconcile.Reconciler
+		recorder   *record.FakeRecorder
+		request    reconcile.Request
+		ctx        context.Context
+		logTailer  *fakes.FakeLogTailer
+		client     crc.Client
+		bdpl       *bdv1.BOSHDeployment
+		qJob       *qjv1a1.QuarksJob
+		pods       []*corev1.Pod
+	)
+
+	newErrandPod := func(name string, created time.Time, state corev1.ContainerState, lastState corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					bdm.LabelDeploymentName:    "foo",
+					bdm.LabelInstanceGroupName: "smoke-tests",
+					qjv1a1.LabelQJobName:       "foo-smoke-tests",
+				},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "logs", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: "smoke-tests-smoke-tests", State: state, LastTerminationState: lastState},
+				},
+			},
+		}
+	}
+
+	terminated := func(exitCode int32, finished time.Time) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, FinishedAt: metav1.NewTime(finished)}}
+	}
+
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+
+	getBOSHDeployment := func() *bdv1.BOSHDeployment {
+		d := &bdv1.BOSHDeployment{}
+		err := client.Get(context.Background(), types.NamespacedName{Name: "foo", Namespace: "default"}, d)
+		Expect(err).ToNot(HaveOccurred())
+		return d
+	}
+
+	getStrategy := func() qjv1a1.Strategy {
+		q := &qjv1a1.QuarksJob{}
+		err := client.Get(context.Background(), types.NamespacedName{Name: "foo-smoke-tests", Namespace: "default"}, q)
+		Expect(err).ToNot(HaveOccurred())
+		return q.Spec.Trigger.Strategy
+	}
+
+	BeforeEach(func() {
+		controllers.AddToScheme(scheme.Scheme)
+		recorder = record.NewFakeRecorder(20)
+		manager = &fakes.FakeManager{}
+		manager.GetSchemeReturns(scheme.Scheme)
+		manager.GetEventRecorderForReturns(recorder)
+		logTailer = &fakes.FakeLogTailer{}
+		logTailer.TailLogReturns("smoke tests passed\n", nil)
+
+		bdpl = &bdv1.BOSHDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+		}
+		qJob = &qjv1a1.QuarksJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-smoke-tests",
+				Namespace: "default",
+			},
+			Spec: qjv1a1.QuarksJobSpec{
+				Trigger: qjv1a1.Trigger{Strategy: qjv1a1.TriggerManual},
+			},
+		}
+		pods = []*corev1.Pod{}
+
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}}
+
+		_, log := helper.NewTestLogger()
+		ctx = ctxlog.NewParentContext(log)
+		ctx = ctxlog.NewContextWithRecorder(ctx, "TestRecorder", recorder)
+	})
+
+	JustBeforeEach(func() {
+		objects := []runtime.Object{bdpl, qJob}
+		for _, pod := range pods {
+			objects = append(objects, pod)
+		}
+		client = fake.NewFakeClient(objects...)
+		manager.GetClientReturns(client)
+		reconciler = cfd.NewErrandReconciler(ctx, &cfcfg.Config{CtxTimeOut: 10 * time.Second}, manager, logTailer)
+	})
+
+	Context("when the run-errand annotation is set", func() {
+		BeforeEach(func() {
+			bdpl.Annotations = map[string]string{bdv1.AnnotationRunErrand: "smoke-tests, missing"}
+		})
+
+		It("triggers the errand, records the run and removes the annotation", func() {
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(getStrategy()).To(Equal(qjv1a1.TriggerNow))
+
+			d := getBOSHDeployment()
+			Expect(d.Annotations).ToNot(HaveKey(bdv1.AnnotationRunErrand))
+			Expect(d.Status.Errands).To(HaveLen(1))
+			Expect(d.Status.Errands[0].Name).To(Equal("smoke-tests"))
+			Expect(d.Status.Errands[0].Runs).To(HaveLen(1))
+			Expect(d.Status.Errands[0].Runs[0].Trigger).To(Equal(bdv1.ErrandTriggerAnnotation))
+			Expect(d.Status.Errands[0].Runs[0].CompletionTime).To(BeNil())
+
+			Expect(<-recorder.Events).To(ContainSubstring("Errand 'missing' of BOSHDeployment 'foo' not found"))
+		})
+
+		Context("when the errand is already triggered", func() {
+			BeforeEach(func() {
+				qJob.Spec.Trigger.Strategy = qjv1a1.TriggerNow
+			})
+
+			It("doesn't record another run", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(getBOSHDeployment().Status.Errands).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("when an errand has a schedule", func() {
+		BeforeEach(func() {
+			bdpl.Spec.Errands = []bdv1.ErrandSpec{{Name: "smoke-tests", Schedule: "*/5 * * * *"}}
+		})
+
+		It("doesn't run missed schedules and requeues for the next one", func() {
+			result, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 5*time.Minute))
+
+			Expect(getStrategy()).To(Equal(qjv1a1.TriggerManual))
+			d := getBOSHDeployment()
+			Expect(d.Status.Errands).To(HaveLen(1))
+			Expect(d.Status.Errands[0].LastScheduleTime).ToNot(BeNil())
+			Expect(d.Status.Errands[0].Runs).To(BeEmpty())
+		})
+
+		Context("when the schedule is due", func() {
+			BeforeEach(func() {
+				bdpl.Status.Errands = []bdv1.ErrandStatus{
+					{Name: "smoke-tests", LastScheduleTime: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}},
+				}
+			})
+
+			It("runs the errand", func() {
+				result, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("<=", 5*time.Minute))
+
+				Expect(getStrategy()).To(Equal(qjv1a1.TriggerNow))
+				d := getBOSHDeployment()
+				Expect(d.Status.Errands[0].LastScheduleTime.Time).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(d.Status.Errands[0].Runs).To(HaveLen(1))
+				Expect(d.Status.Errands[0].Runs[0].Trigger).To(Equal(bdv1.ErrandTriggerSchedule))
+			})
+		})
+
+		Context("when the schedule is invalid", func() {
+			BeforeEach(func() {
+				bdpl.Spec.Errands[0].Schedule = "every day"
+			})
+
+			It("emits an event", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(<-recorder.Events).To(ContainSubstring("ErrandScheduleError"))
+			})
+		})
+	})
+
+	Context("when an errand pod terminated", func() {
+		started := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+		BeforeEach(func() {
+			bdpl.Status.Errands = []bdv1.ErrandStatus{
+				{Name: "smoke-tests", Runs: []bdv1.ErrandRun{
+					{Trigger: bdv1.ErrandTriggerAnnotation, StartTime: metav1.NewTime(started)},
+				}},
+			}
+		})
+
+		Context("when it succeeded", func() {
+			BeforeEach(func() {
+				pods = append(pods, newErrandPod("foo-smoke-tests-abcde", started.Add(time.Second), terminated(0, started.Add(time.Minute)), corev1.ContainerState{}))
+			})
+
+			It("records the result of the run", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				runs := getBOSHDeployment().Status.Errands[0].Runs
+				Expect(runs).To(HaveLen(1))
+				Expect(runs[0].Trigger).To(Equal(bdv1.ErrandTriggerAnnotation))
+				Expect(runs[0].Pod).To(Equal("foo-smoke-tests-abcde"))
+				Expect(*runs[0].ExitCode).To(Equal(int32(0)))
+				Expect(runs[0].CompletionTime.Time).To(Equal(started.Add(time.Minute)))
+				Expect(runs[0].LogTail).To(Equal("smoke tests passed\n"))
+
+				_, namespace, podName, container, lines, previous := logTailer.TailLogArgsForCall(0)
+				Expect(namespace).To(Equal("default"))
+				Expect(podName).To(Equal("foo-smoke-tests-abcde"))
+				Expect(container).To(Equal("smoke-tests-smoke-tests"))
+				Expect(lines).To(Equal(int64(20)))
+				Expect(previous).To(BeFalse())
+			})
+
+			It("doesn't record the result again", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(logTailer.TailLogCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when its container failed and was restarted", func() {
+			BeforeEach(func() {
+				pods = append(pods, newErrandPod("foo-smoke-tests-abcde", started.Add(time.Second), running, terminated(3, started.Add(time.Minute))))
+			})
+
+			It("records the failure with the log of the previous container", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				runs := getBOSHDeployment().Status.Errands[0].Runs
+				Expect(*runs[0].ExitCode).To(Equal(int32(3)))
+
+				_, _, _, _, _, previous := logTailer.TailLogArgsForCall(0)
+				Expect(previous).To(BeTrue())
+				Expect(<-recorder.Events).To(ContainSubstring("ErrandFailed"))
+			})
+		})
+
+		Context("when the errand was triggered on the QuarksJob", func() {
+			BeforeEach(func() {
+				pods = append(pods,
+					newErrandPod("foo-smoke-tests-abcde", started.Add(time.Second), terminated(0, started.Add(time.Minute)), corev1.ContainerState{}),
+					newErrandPod("foo-smoke-tests-fghij", started.Add(time.Hour), terminated(1, started.Add(time.Hour+time.Minute)), corev1.ContainerState{}),
+				)
+			})
+
+			It("adds an external run", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				runs := getBOSHDeployment().Status.Errands[0].Runs
+				Expect(runs).To(HaveLen(2))
+				Expect(runs[0].Pod).To(Equal("foo-smoke-tests-abcde"))
+				Expect(runs[1].Trigger).To(Equal(bdv1.ErrandTriggerExternal))
+				Expect(runs[1].Pod).To(Equal("foo-smoke-tests-fghij"))
+				Expect(*runs[1].ExitCode).To(Equal(int32(1)))
+			})
+		})
+
+		Context("when the pod is older than the recorded runs", func() {
+			BeforeEach(func() {
+				pods = append(pods, newErrandPod("foo-smoke-tests-old", started.Add(-time.Hour), terminated(0, started.Add(-time.Minute)), corev1.ContainerState{}))
+			})
+
+			It("ignores it", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				runs := getBOSHDeployment().Status.Errands[0].Runs
+				Expect(runs).To(HaveLen(1))
+				Expect(runs[0].Pod).To(BeEmpty())
+			})
+		})
+
+		Context("when it's still running", func() {
+			BeforeEach(func() {
+				pods = append(pods, newErrandPod("foo-smoke-tests-abcde", started.Add(time.Second), running, corev1.ContainerState{}))
+			})
+
+			It("doesn't record a result", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(getBOSHDeployment().Status.Errands[0].Runs[0].CompletionTime).To(BeNil())
+				Expect(logTailer.TailLogCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
@@ -1,14 +1,51 @@
 package boshdeployment
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
+	"github.com/pkg/errors"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 )
 
+func isSharedLinkSecret(labels map[string]string) bool {
+	return labels[bdv1.LabelSharedLink] == "true"
+}
+
+// consumersOfSharedLink returns reconciles for all deployments, which have
+// link secrets for links consumed from the providing deployment
+func consumersOfSharedLink(ctx context.Context, c client.Client, deploymentName string) ([]reconcile.Request, error) {
+	reconciles := []reconcile.Request{}
+	if deploymentName == "" {
+		return reconciles, nil
+	}
+
+	secrets := &corev1.SecretList{}
+	err := c.List(ctx, secrets, client.MatchingLabels{bdv1.LabelLinkProviderDeployment: deploymentName})
+	if err != nil {
+		return reconciles, errors.Wrapf(err, "listing link secrets consumed from deployment '%s'", deploymentName)
+	}
+
+	seen := map[types.NamespacedName]bool{}
+	for _, s := range secrets.Items {
+		nn := types.NamespacedName{Namespace: s.Namespace, Name: s.Labels[bdv1.LabelDeploymentName]}
+		if nn.Name == "" || seen[nn] {
+			continue
+		}
+		seen[nn] = true
+		reconciles = append(reconciles, reconcile.Request{NamespacedName: nn})
+	}
+
+	return reconciles, nil
+}
+
 func isLinkProviderService(svc *corev1.Service) bool {
 	if _, ok := svc.GetAnnotations()[bdv1.AnnotationLinkProviderService]; ok {
 		return true
@@ -0,0 +1,81 @@
+package boshdeployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/statefulset"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/desiredmanifest"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/podexec"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AddPostDeploy creates a new post-deploy controller to watch for stateful
+// sets of instance groups. Once the rollout of an instance group finished, it
+// runs the post-deploy scripts of its BOSH jobs in each instance.
+func AddPostDeploy(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "post-deploy-reconciler", mgr.GetEventRecorderFor("post-deploy-recorder"))
+
+	podExec, err := podexec.NewPodExec(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "Adding post-deploy controller to manager failed.")
+	}
+
+	r := NewPostDeployReconciler(
+		ctx, config, mgr,
+		desiredmanifest.NewDesiredManifest(mgr.GetClient()),
+		podExec,
+	)
+
+	// Create a new controller
+	c, err := ratelimit.NewController(ctx, "post-deploy-controller", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxBoshDeploymentWorkers,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding post-deploy controller to manager failed.")
+	}
+
+	// Trigger when the rollout of a BOSH instance group stateful set is done
+	p := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			o := e.ObjectOld.(*appsv1.StatefulSet)
+			n := e.ObjectNew.(*appsv1.StatefulSet)
+
+			if _, ok := n.GetLabels()[bdm.LabelInstanceGroupName]; !ok {
+				return false
+			}
+
+			if statefulset.IsRolloutDone(n) && !statefulset.IsRolloutDone(o) {
+				ctxlog.NewPredicateEvent(e.ObjectNew).Debug(
+					ctx, e.MetaNew, "StatefulSet",
+					fmt.Sprintf("Update predicate passed for '%s'", e.MetaNew.GetName()),
+				)
+				return true
+			}
+			return false
+		},
+	}
+	err = c.Watch(&source.Kind{Type: &appsv1.StatefulSet{}}, &handler.EnqueueRequestForObject{}, p)
+	if err != nil {
+		return errors.Wrapf(err, "Watching stateful sets failed in post-deploy controller.")
+	}
+
+	return nil
+}
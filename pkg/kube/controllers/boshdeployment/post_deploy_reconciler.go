@@ -0,0 +1,229 @@
+package boshdeployment
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/bpmconverter"
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/statefulset"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// PodExecutor runs commands in containers of running pods
+type PodExecutor interface {
+	Exec(ctx context.Context, namespace, podName, containerName string, command []string) (string, string, error)
+}
+
+var _ reconcile.Reconciler = &ReconcilePostDeploy{}
+
+// NewPostDeployReconciler returns a new reconcile.Reconciler
+func NewPostDeployReconciler(ctx context.Context, config *config.Config, mgr manager.Manager, resolver DesiredManifest, executor PodExecutor) reconcile.Reconciler {
+	return &ReconcilePostDeploy{
+		ctx:      ctx,
+		config:   config,
+		client:   mgr.GetClient(),
+		resolver: resolver,
+		executor: executor,
+	}
+}
+
+// ReconcilePostDeploy runs the post-deploy scripts of an instance group
+type ReconcilePostDeploy struct {
+	ctx      context.Context
+	config   *config.Config
+	client   crc.Client
+	resolver DesiredManifest
+	executor PodExecutor
+}
+
+// Reconcile runs the post-deploy scripts of all jobs of an instance group in
+// each of its pods, after the rollout of the instance group's stateful set is
+// done. Failures are recorded in the status of the BOSHDeployment.
+func (r *ReconcilePostDeploy) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Set the ctx to be Background, as the top-level context for incoming requests.
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
+	defer cancel()
+
+	log.Infof(ctx, "Reconciling post-deploy for StatefulSet '%s'", request.NamespacedName)
+	sts := &appsv1.StatefulSet{}
+	err := r.client.Get(ctx, request.NamespacedName, sts)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug(ctx, "Skip reconcile: StatefulSet not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !statefulset.IsRolloutDone(sts) {
+		log.Debugf(ctx, "Skip reconcile: rollout of StatefulSet '%s' is not done", sts.Name)
+		return reconcile.Result{}, nil
+	}
+
+	deploymentName, ok := sts.Labels[bdm.LabelDeploymentName]
+	if !ok {
+		log.Debugf(ctx, "Skip reconcile: StatefulSet '%s' doesn't belong to a BOSHDeployment", sts.Name)
+		return reconcile.Result{}, nil
+	}
+	instanceGroupName := sts.Labels[bdm.LabelInstanceGroupName]
+
+	bdpl := &bdv1.BOSHDeployment{}
+	err = r.client.Get(ctx, types.NamespacedName{Namespace: request.Namespace, Name: deploymentName}, bdpl)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debugf(ctx, "Skip reconcile: BOSHDeployment '%s' not found", deploymentName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{},
+			log.WithEvent(sts, "GetBOSHDeploymentError").Errorf(ctx, "Failed to get BOSHDeployment '%s': %v", deploymentName, err)
+	}
+
+	manifest, err := r.resolver.DesiredManifest(ctx, deploymentName, request.Namespace)
+	if err != nil {
+		return reconcile.Result{},
+			log.WithEvent(bdpl, "DesiredManifestReadError").Errorf(ctx, "Failed to read desired manifest '%s': %v", deploymentName, err)
+	}
+
+	if !manifest.PostDeployEnabled() {
+		log.Debugf(ctx, "Skip reconcile: post-deploy is disabled for BOSHDeployment '%s'", deploymentName)
+		return reconcile.Result{}, nil
+	}
+
+	instanceGroup, found := manifest.InstanceGroups.InstanceGroupByName(instanceGroupName)
+	if !found {
+		log.Debugf(ctx, "Skip reconcile: instance group '%s' not found in desired manifest", instanceGroupName)
+		return reconcile.Result{}, nil
+	}
+
+	pods, err := r.listPods(ctx, sts)
+	if err != nil {
+		return reconcile.Result{},
+			log.WithEvent(bdpl, "PostDeployError").Errorf(ctx, "Failed to list pods of StatefulSet '%s': %v", sts.Name, err)
+	}
+
+	failures := []bdv1.PostDeployFailure{}
+	for _, pod := range pods {
+		for _, job := range instanceGroup.Jobs {
+			failure := r.runPostDeploy(ctx, manifest, instanceGroup, job, pod)
+			if failure != nil {
+				failures = append(failures, *failure)
+			}
+		}
+	}
+
+	for _, failure := range failures {
+		log.WithEvent(bdpl, "PostDeployError").Errorf(ctx, "Post-deploy script of job '%s' failed in pod '%s': %s", failure.Job, failure.Pod, failure.Message)
+	}
+
+	// Replace the previous failures of this stateful set's pods with the new ones
+	status := []bdv1.PostDeployFailure{}
+	for _, failure := range bdpl.Status.PostDeployFailures {
+		if failure.InstanceGroup != instanceGroupName || !isPodOf(failure.Pod, sts.Name) {
+			status = append(status, failure)
+		}
+	}
+	bdpl.Status.PostDeployFailures = append(status, failures...)
+
+	err = r.client.Status().Update(ctx, bdpl)
+	if err != nil {
+		return reconcile.Result{},
+			log.WithEvent(bdpl, "UpdateError").Errorf(ctx, "Failed to update post-deploy status on BOSHDeployment '%s' (%v): %s", bdpl.Name, bdpl.ResourceVersion, err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// listPods returns the pods of the stateful set, sorted by name
+func (r *ReconcilePostDeploy) listPods(ctx context.Context, sts *appsv1.StatefulSet) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	err := r.client.List(ctx, podList,
+		crc.InNamespace(sts.Namespace),
+		crc.MatchingLabels(sts.Spec.Selector.MatchLabels),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	return pods, nil
+}
+
+// isPodOf returns true if the pod name is the one of a stateful set's replica
+func isPodOf(podName string, statefulSetName string) bool {
+	ordinal := strings.TrimPrefix(podName, statefulSetName+"-")
+	if ordinal == podName || ordinal == "" {
+		return false
+	}
+	_, err := strconv.Atoi(ordinal)
+	return err == nil
+}
+
+// runPostDeploy executes the post-deploy script of a job, if it exists, in
+// the first container which runs the job's release image
+func (r *ReconcilePostDeploy) runPostDeploy(ctx context.Context, manifest *bdm.Manifest, instanceGroup *bdm.InstanceGroup, job bdm.Job, pod corev1.Pod) *bdv1.PostDeployFailure {
+	failure := func(msg string) *bdv1.PostDeployFailure {
+		return &bdv1.PostDeployFailure{
+			InstanceGroup: instanceGroup.Name,
+			Job:           job.Name,
+			Pod:           pod.Name,
+			Message:       msg,
+			Timestamp:     metav1.Now(),
+		}
+	}
+
+	jobImage, err := manifest.GetReleaseImage(instanceGroup.Name, job.Name)
+	if err != nil {
+		return failure(err.Error())
+	}
+
+	containerName := ""
+	for _, container := range pod.Spec.Containers {
+		if container.Image == jobImage {
+			containerName = container.Name
+			break
+		}
+	}
+	if containerName == "" {
+		// Jobs without processes have no container to run the script in
+		log.Debugf(ctx, "No container found for job '%s' in pod '%s', skipping post-deploy", job.Name, pod.Name)
+		return nil
+	}
+
+	postDeploy := filepath.Join(bpmconverter.VolumeJobsDirMountPath, job.Name, "bin", "post-deploy")
+	command := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf(`if [ -x "%[1]s" ]; then "%[1]s"; fi`, postDeploy),
+	}
+
+	log.Debugf(ctx, "Running post-deploy of job '%s' in pod '%s' container '%s'", job.Name, pod.Name, containerName)
+	_, stderr, err := r.executor.Exec(ctx, pod.Namespace, pod.Name, containerName, command)
+	if err != nil {
+		msg := err.Error()
+		if stderr = strings.TrimSpace(stderr); stderr != "" {
+			msg = fmt.Sprintf("%s: %s", msg, stderr)
+		}
+		return failure(msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package boshdeployment_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers"
+	cfd "code.cloudfoundry.org/cf-operator/pkg/kube/controllers/boshdeployment"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/fakes"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/statefulset"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcilePostDeploy", func() {
+	var (
+		manager    *fakes.FakeManager
+		reconciler reconcile.Reconciler
+		recorder   *record.FakeRecorder
+		request    reconcile.Request
+		ctx        context.Context
+		resolver   *fakes.FakeDesiredManifest
+		executor   *fakes.FakePodExecutor
+		manifest   *bdm.Manifest
+		client     crc.Client
+		sts        *appsv1.StatefulSet
+		bdpl       *bdv1.BOSHDeployment
+		jobImage   string
+	)
+
+	newPod := func(name string, app string, image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": app},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "foo", Image: image},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		controllers.AddToScheme(scheme.Scheme)
+		recorder = record.NewFakeRecorder(20)
+		manager = &fakes.FakeManager{}
+		manager.GetSchemeReturns(scheme.Scheme)
+		manager.GetEventRecorderForReturns(recorder)
+		resolver = &fakes.FakeDesiredManifest{}
+		executor = &fakes.FakePodExecutor{}
+
+		manifest = &bdm.Manifest{
+			Releases: []*bdm.Release{
+				{
+					Name:    "bar",
+					URL:     "docker.io/cfcontainerization",
+					Version: "1.0",
+					Stemcell: &bdm.ReleaseStemcell{
+						OS:      "opensuse",
+						Version: "42.3",
+					},
+				},
+			},
+			InstanceGroups: []*bdm.InstanceGroup{
+				{
+					Name:      "fakepod",
+					Instances: 2,
+					Jobs: []bdm.Job{
+						{Name: "foo", Release: "bar"},
+					},
+				},
+			},
+		}
+		resolver.DesiredManifestReturns(manifest, nil)
+
+		var err error
+		jobImage, err = manifest.GetReleaseImage("fakepod", "foo")
+		Expect(err).ToNot(HaveOccurred())
+
+		sts = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fakepod",
+				Namespace: "default",
+				Labels: map[string]string{
+					bdm.LabelDeploymentName:    "foo",
+					bdm.LabelInstanceGroupName: "fakepod",
+				},
+				Annotations: map[string]string{
+					statefulset.AnnotationCanaryRollout: "Done",
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "fakepod"},
+				},
+			},
+		}
+
+		bdpl = &bdv1.BOSHDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+		}
+
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "fakepod", Namespace: "default"}}
+
+		_, log := helper.NewTestLogger()
+		ctx = ctxlog.NewParentContext(log)
+		ctx = ctxlog.NewContextWithRecorder(ctx, "TestRecorder", recorder)
+	})
+
+	JustBeforeEach(func() {
+		client = fake.NewFakeClient(
+			sts,
+			bdpl,
+			newPod("fakepod-1", "fakepod", jobImage),
+			newPod("fakepod-0", "fakepod", jobImage),
+			newPod("other-0", "other", jobImage),
+		)
+		manager.GetClientReturns(client)
+		reconciler = cfd.NewPostDeployReconciler(ctx, &cfcfg.Config{CtxTimeOut: 10 * time.Second}, manager, resolver, executor)
+	})
+
+	getStatus := func() []bdv1.PostDeployFailure {
+		d := &bdv1.BOSHDeployment{}
+		err := client.Get(context.Background(), types.NamespacedName{Name: "foo", Namespace: "default"}, d)
+		Expect(err).ToNot(HaveOccurred())
+		return d.Status.PostDeployFailures
+	}
+
+	Context("when the rollout of the stateful set is done", func() {
+		It("runs the post-deploy script of each job in each pod", func() {
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(executor.ExecCallCount()).To(Equal(2))
+			_, namespace, podName, containerName, command := executor.ExecArgsForCall(0)
+			Expect(namespace).To(Equal("default"))
+			Expect(podName).To(Equal("fakepod-0"))
+			Expect(containerName).To(Equal("foo"))
+			Expect(command[2]).To(ContainSubstring("/var/vcap/jobs/foo/bin/post-deploy"))
+
+			_, _, podName, _, _ = executor.ExecArgsForCall(1)
+			Expect(podName).To(Equal("fakepod-1"))
+
+			Expect(getStatus()).To(BeEmpty())
+		})
+
+		Context("when a post-deploy script fails", func() {
+			BeforeEach(func() {
+				executor.ExecReturnsOnCall(1, "", "database not reachable\n", errors.New("command terminated with exit code 1"))
+				bdpl.Status.PostDeployFailures = []bdv1.PostDeployFailure{
+					{InstanceGroup: "fakepod", Job: "foo", Pod: "fakepod-0", Message: "old"},
+					{InstanceGroup: "other", Job: "foo", Pod: "other-0", Message: "other"},
+				}
+			})
+
+			It("records the failure in the status and emits an event", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				failures := getStatus()
+				Expect(failures).To(HaveLen(2))
+				Expect(failures[0].Message).To(Equal("other"))
+				Expect(failures[1].InstanceGroup).To(Equal("fakepod"))
+				Expect(failures[1].Job).To(Equal("foo"))
+				Expect(failures[1].Pod).To(Equal("fakepod-1"))
+				Expect(failures[1].Message).To(Equal("command terminated with exit code 1: database not reachable"))
+
+				Expect(<-recorder.Events).To(ContainSubstring("PostDeployError"))
+			})
+		})
+
+		Context("when post-deploy is disabled in the manifest", func() {
+			BeforeEach(func() {
+				disabled := false
+				manifest.Features = &bdm.Feature{PostDeploy: &disabled}
+			})
+
+			It("doesn't run any script", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(executor.ExecCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when no container runs the job's release image", func() {
+			BeforeEach(func() {
+				jobImage = "docker.io/other/image:1.0"
+			})
+
+			It("skips the job", func() {
+				_, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(executor.ExecCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the rollout of the stateful set is not done", func() {
+		BeforeEach(func() {
+			sts.Annotations[statefulset.AnnotationCanaryRollout] = "Rollout"
+		})
+
+		It("doesn't run any script", func() {
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(executor.ExecCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the desired manifest can't be read", func() {
+		BeforeEach(func() {
+			resolver.DesiredManifestReturns(nil, errors.New("fake-error"))
+		})
+
+		It("returns an error", func() {
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to read desired manifest"))
+		})
+	})
+})
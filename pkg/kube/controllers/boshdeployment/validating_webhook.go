@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"k8s.io/api/admission/v1beta1"
@@ -21,6 +22,7 @@ import (
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/statefulset"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/boshdns"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/cron"
 	wh "code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/withops"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
@@ -177,6 +179,18 @@ func (v *Validator) Handle(ctx context.Context, req admission.Request) admission
 		}
 	}
 
+	err = validateErrandSchedules(boshDeployment.Spec.Errands)
+	if err != nil {
+		return admission.Response{
+			AdmissionResponse: v1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: fmt.Sprintf("Failed to validate errands: %s", err.Error()),
+				},
+			},
+		}
+	}
+
 	v.log.Infof("Verifying dependencies for deployment '%s'", boshDeployment.Name)
 	withops := withops.NewResolver(
 		v.client,
@@ -220,6 +234,20 @@ func (v *Validator) Handle(ctx context.Context, req admission.Request) admission
 			},
 		}
 	}
+
+	v.log.Infof("Validating manifest of deployment '%s'", boshDeployment.Name)
+	errs := v.validateManifest(boshDeployment, manifest)
+	if len(errs) > 0 {
+		return admission.Response{
+			AdmissionResponse: v1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: fmt.Sprintf("Invalid manifest: %s", errs.Error()),
+				},
+			},
+		}
+	}
+
 	return admission.Response{
 		AdmissionResponse: v1beta1.AdmissionResponse{
 			Allowed: true,
@@ -238,6 +266,30 @@ func validateUpdateBlock(manifest manifest.Manifest) error {
 	return err
 }
 
+// validateManifest runs the validation suite on the resolved manifest. Links
+// consumed from providers outside of the manifest are only logged, they may
+// be provided later by a link secret or a dependency and the deployment waits
+// for them.
+func (v *Validator) validateManifest(bdpl *bdv1.BOSHDeployment, m *bdm.Manifest) bdm.ValidationErrors {
+	for _, consumer := range m.ListConsumersWithoutProvider(nil) {
+		v.log.Infof("Deployment '%s/%s' waits for a provider: %s", bdpl.Namespace, bdpl.Name, consumer)
+	}
+
+	return m.Validate()
+}
+
+func validateErrandSchedules(errands []bdv1.ErrandSpec) error {
+	for _, errand := range errands {
+		if errand.Schedule == "" {
+			continue
+		}
+		if _, err := cron.Parse(errand.Schedule); err != nil {
+			return errors.Wrapf(err, "errand '%s'", errand.Name)
+		}
+	}
+	return nil
+}
+
 // Validator implements inject.Client.
 // A client will be automatically injected.
 var _ inject.Client = &Validator{}
@@ -18,7 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
-	"code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/boshdeployment"
 	"code.cloudfoundry.org/cf-operator/testing"
@@ -34,9 +34,10 @@ var _ = Describe("When the validating webhook handles a manifest", func() {
 		env                    testing.Catalog
 		client                 client.Client
 		decoder                *admission.Decoder
-		manifest               *manifest.Manifest
+		manifest               *bdm.Manifest
 		validator              admission.Handler
 		boshDeploymentBytes    []byte
+		objects                []runtime.Object
 		validateBoshDeployment func() admission.Response
 	)
 
@@ -45,6 +46,10 @@ var _ = Describe("When the validating webhook handles a manifest", func() {
 		ctx = ctxlog.NewParentContext(log)
 
 		boshDeployment := bdv1.BOSHDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
 			Spec: bdv1.BOSHDeploymentSpec{
 				Manifest: bdv1.ResourceReference{
 					Type: bdv1.ConfigMapReference,
@@ -54,13 +59,14 @@ var _ = Describe("When the validating webhook handles a manifest", func() {
 		}
 		boshDeploymentBytes, _ = json.Marshal(boshDeployment)
 		manifest, _ = env.BOSHManifestWithZeroInstances()
+		objects = []runtime.Object{}
 	})
 
 	JustBeforeEach(func() {
 		manifestBytes, _ := manifest.Marshal()
 		scheme := runtime.NewScheme()
 		Expect(corev1.AddToScheme(scheme)).To(Succeed())
-		client = fake.NewFakeClientWithScheme(scheme, &corev1.ConfigMap{
+		client = fake.NewFakeClientWithScheme(scheme, append(objects, &corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "base-manifest",
 				Namespace: "default",
@@ -68,7 +74,7 @@ var _ = Describe("When the validating webhook handles a manifest", func() {
 			Data: map[string]string{
 				bdv1.ManifestSpecName: string(manifestBytes),
 			},
-		})
+		})...)
 		decoder, _ = admission.NewDecoder(scheme)
 		validator = boshdeployment.NewValidator(log, &cfcfg.Config{CtxTimeOut: 10 * time.Second})
 		validator.(inject.Client).InjectClient(client)
@@ -131,4 +137,74 @@ var _ = Describe("When the validating webhook handles a manifest", func() {
 			Expect(response.AdmissionResponse.Allowed).To(BeFalse())
 		})
 	})
+
+	Context("with an invalid errand schedule", func() {
+		BeforeEach(func() {
+			boshDeployment := bdv1.BOSHDeployment{
+				Spec: bdv1.BOSHDeploymentSpec{
+					Manifest: bdv1.ResourceReference{
+						Type: bdv1.ConfigMapReference,
+						Name: "base-manifest",
+					},
+					Errands: []bdv1.ErrandSpec{{Name: "smoke-tests", Schedule: "0 25 * * *"}},
+				},
+			}
+			boshDeploymentBytes, _ = json.Marshal(boshDeployment)
+		})
+
+		It("the manifest is rejected", func() {
+			response := validateBoshDeployment()
+			Expect(response.AdmissionResponse.Allowed).To(BeFalse())
+			Expect(response.AdmissionResponse.Result.Message).To(ContainSubstring("errand 'smoke-tests'"))
+		})
+	})
+
+	Context("with semantic errors in the manifest", func() {
+		BeforeEach(func() {
+			ig := *manifest.InstanceGroups[0]
+			ig.Stemcell = "unknown"
+			manifest.InstanceGroups = append(manifest.InstanceGroups, &ig)
+			manifest.InstanceGroups[0].Jobs = append(manifest.InstanceGroups[0].Jobs, bdm.Job{Name: "redis", Release: "redis"})
+			manifest.Variables = []bdm.Variable{{Name: "ca", Type: "certificate"}}
+		})
+
+		It("the manifest is rejected with all errors", func() {
+			response := validateBoshDeployment()
+			Expect(response.AdmissionResponse.Allowed).To(BeFalse())
+			Expect(response.AdmissionResponse.Result.Message).To(Equal("Invalid manifest: " +
+				"job 'redis' in instance group 'nats' references undeclared release 'redis'; " +
+				"instance group 'nats' is declared more than once; " +
+				"instance group 'nats' uses unknown stemcell alias 'unknown'; " +
+				"certificate variable 'ca' has no options"))
+		})
+	})
+
+	Context("with variables, which are neither declared nor in a secret", func() {
+		BeforeEach(func() {
+			manifest.InstanceGroups[0].Jobs[0].Properties.Properties["nats"] = map[string]interface{}{
+				"user":     "((nats_user))",
+				"password": "((nats_password))",
+			}
+		})
+
+		It("the manifest is rejected with all missing variables", func() {
+			response := validateBoshDeployment()
+			Expect(response.AdmissionResponse.Allowed).To(BeFalse())
+			Expect(response.AdmissionResponse.Result.Message).To(ContainSubstring("failed to load secret for variable 'nats_password'"))
+			Expect(response.AdmissionResponse.Result.Message).To(ContainSubstring("failed to load secret for variable 'nats_user'"))
+		})
+	})
+
+	Context("with a consumed link", func() {
+		BeforeEach(func() {
+			manifest.InstanceGroups[0].Jobs[0].Consumes = map[string]interface{}{
+				"database": map[string]interface{}{"from": "mysql"},
+			}
+		})
+
+		It("the manifest is accepted, since the provider may be created later", func() {
+			response := validateBoshDeployment()
+			Expect(response.AdmissionResponse.Allowed).To(BeTrue())
+		})
+	})
 })
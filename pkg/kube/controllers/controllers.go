@@ -12,13 +12,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	machinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"code.cloudfoundry.org/cf-operator/pkg/credsgen"
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	bdv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1beta1"
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	qsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1beta1"
 	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	qstsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1beta1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/boshdeployment"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/quarkslink"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/quarkssecret"
@@ -26,6 +31,7 @@ import (
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/statefulset"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/versionedsecret"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/watchnamespace"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
 	wh "code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
 	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
@@ -35,6 +41,8 @@ import (
 const (
 	// HTTPReadyzEndpoint route
 	HTTPReadyzEndpoint = "/readyz"
+	// ConversionWebhookPath is the route of the CRD conversion webhook
+	ConversionWebhookPath = "/convert"
 	// WebhookConfigPrefix is the prefix for the dir containing the webhook SSL certs
 	WebhookConfigPrefix = "cf-operator-hook-"
 	// WebhookConfigDir contains the dir with the webhook SSL certs
@@ -45,9 +53,11 @@ const (
 // manager. The manager will set fields on the controllers and start them, when
 // itself is started.
 var addToManagerFuncs = []func(context.Context, *config.Config, manager.Manager) error{
-	watchnamespace.AddTerminate,
+	watchnamespace.AddWatchNamespace,
 	boshdeployment.AddDeployment,
 	boshdeployment.AddBPM,
+	boshdeployment.AddPostDeploy,
+	boshdeployment.AddErrand,
 	quarkssecret.AddQuarksSecret,
 	quarkssecret.AddCertificateSigningRequest,
 	quarkssecret.AddSecretRotation,
@@ -60,9 +70,20 @@ var addToManagerFuncs = []func(context.Context, *config.Config, manager.Manager)
 var addToSchemes = runtime.SchemeBuilder{
 	extv1.AddToScheme,
 	bdv1.AddToScheme,
+	bdv1beta1.AddToScheme,
 	qjv1a1.AddToScheme,
 	qsv1a1.AddToScheme,
+	qsv1beta1.AddToScheme,
 	qstsv1a1.AddToScheme,
+	qstsv1beta1.AddToScheme,
+}
+
+// convertedCRDs have several versions, which are converted by the conversion
+// webhook
+var convertedCRDs = []string{
+	bdv1.BOSHDeploymentResourceName,
+	qsv1a1.QuarksSecretResourceName,
+	qstsv1a1.QuarksStatefulSetResourceName,
 }
 
 var validatingHookFuncs = []func(*zap.SugaredLogger, *config.Config) *wh.OperatorWebhook{
@@ -95,10 +116,13 @@ func AddToScheme(s *runtime.Scheme) error {
 func AddHooks(ctx context.Context, config *config.Config, m manager.Manager, generator credsgen.Generator) error {
 	ctxlog.Infof(ctx, "Setting up webhook server on %s:%d", config.WebhookServerHost, config.WebhookServerPort)
 
-	ctxlog.Info(ctx, "Setting a cf-operator namespace label on the watched namespace")
-	err := setWatchNamespaceLabel(ctx, config, m.GetClient())
-	if err != nil {
-		return errors.Wrap(err, "setting the operator namespace label")
+	// With several watched namespaces, the watch namespace controller sets the label
+	if !watchnamespaces.Enabled() {
+		ctxlog.Info(ctx, "Setting a cf-operator namespace label on the watched namespace")
+		err := setWatchNamespaceLabel(ctx, config, m.GetClient())
+		if err != nil {
+			return errors.Wrap(err, "setting the operator namespace label")
+		}
 	}
 
 	webhookConfig := NewWebhookConfig(m.GetClient(), config, generator, WebhookConfigPrefix+config.OperatorNamespace)
@@ -107,6 +131,7 @@ func AddHooks(ctx context.Context, config *config.Config, m manager.Manager, gen
 	hookServer.CertDir = webhookConfig.CertDir
 
 	hookServer.Register(HTTPReadyzEndpoint, ordinaryHTTPHandler())
+	hookServer.Register(ConversionWebhookPath, &conversion.Webhook{})
 
 	validatingWebhooks := make([]*wh.OperatorWebhook, len(validatingHookFuncs))
 	log := ctxlog.ExtractLogger(ctx)
@@ -124,7 +149,7 @@ func AddHooks(ctx context.Context, config *config.Config, m manager.Manager, gen
 	}
 
 	ctxlog.Info(ctx, "generating webhook certificates")
-	err = webhookConfig.setupCertificate(ctx)
+	err := webhookConfig.setupCertificate(ctx)
 	if err != nil {
 		return errors.Wrap(err, "setting up the webhook server certificate")
 	}
@@ -141,6 +166,18 @@ func AddHooks(ctx context.Context, config *config.Config, m manager.Manager, gen
 		return errors.Wrap(err, "generating the webhook server configuration")
 	}
 
+	ctxlog.Info(ctx, "generating conversion webhook configuration")
+	err = webhookConfig.generateConversionWebhookConfig(ctx, ConversionWebhookPath, convertedCRDs)
+	if err != nil {
+		return errors.Wrap(err, "generating the conversion webhook configuration")
+	}
+
+	rotator := NewWebhookCertificateRotator(ctx, webhookConfig, validatingWebhooks, mutatingWebhooks, convertedCRDs)
+	err = m.Add(rotator)
+	if err != nil {
+		return errors.Wrap(err, "adding the webhook certificate rotator to the manager")
+	}
+
 	return nil
 }
 
@@ -151,29 +188,28 @@ func ordinaryHTTPHandler() http.Handler {
 }
 
 func setWatchNamespaceLabel(ctx context.Context, config *config.Config, c client.Client) error {
-	ns := &unstructured.Unstructured{}
-	ns.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "",
-		Kind:    "Namespace",
-		Version: "v1",
-	})
-	err := c.Get(ctx, machinerytypes.NamespacedName{Name: config.Namespace}, ns)
-
-	if err != nil {
-		return errors.Wrap(err, "getting the namespace object")
-	}
-
-	labels := ns.GetLabels()
-	if labels == nil {
-		labels = map[string]string{}
-	}
-	labels[wh.LabelWatchNamespace] = config.OperatorNamespace
-	ns.SetLabels(labels)
-	err = c.Update(ctx, ns)
+	// Operator replicas set the label concurrently, retry on conflicts
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns := &unstructured.Unstructured{}
+		ns.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   "",
+			Kind:    "Namespace",
+			Version: "v1",
+		})
+		err := c.Get(ctx, machinerytypes.NamespacedName{Name: config.Namespace}, ns)
+
+		if err != nil {
+			return errors.Wrap(err, "getting the namespace object")
+		}
 
-	if err != nil {
-		return errors.Wrap(err, "updating the namespace object")
-	}
+		labels := ns.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[wh.LabelWatchNamespace] = config.OperatorNamespace
+		ns.SetLabels(labels)
 
-	return nil
+		// Return the unwrapped error, so conflicts are detected
+		return c.Update(ctx, ns)
+	})
 }
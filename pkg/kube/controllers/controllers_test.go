@@ -10,6 +10,8 @@ import (
 	"github.com/spf13/afero"
 
 	admissionregistration "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -84,7 +86,10 @@ var _ = Describe("Controllers", func() {
 
 		It("sets the operator namespace label", func() {
 			client.UpdateCalls(func(_ context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
-				ns := object.(*unstructured.Unstructured)
+				ns, ok := object.(*unstructured.Unstructured)
+				if !ok {
+					return nil
+				}
 				labels := ns.GetLabels()
 
 				Expect(labels["cf-operator-ns"]).To(Equal(config.OperatorNamespace))
@@ -126,6 +131,137 @@ var _ = Describe("Controllers", func() {
 			})
 		})
 
+		Context("if another replica creates the cert secret at the same time", func() {
+			It("uses the certificate of the other replica", func() {
+				created := false
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *unstructured.Unstructured:
+						if object.GetKind() != "Secret" {
+							return nil
+						}
+						if !created {
+							return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+						}
+						object.Object["data"] = map[string]interface{}{
+							"certificate":    base64.StdEncoding.EncodeToString([]byte("the-cert")),
+							"private_key":    base64.StdEncoding.EncodeToString([]byte("the-key")),
+							"ca_certificate": base64.StdEncoding.EncodeToString([]byte("the-ca-cert")),
+							"ca_private_key": base64.StdEncoding.EncodeToString([]byte("the-ca-key")),
+						}
+					}
+					return nil
+				})
+				client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					switch object.(type) {
+					case *corev1.Secret:
+						created = true
+						return apierrors.NewAlreadyExists(schema.GroupResource{}, "cf-operator-webhook-server-cert")
+					}
+					return nil
+				})
+
+				err := controllers.AddHooks(ctx, config, manager, generator)
+				Expect(err).ToNot(HaveOccurred())
+
+				caCert, err := afero.ReadFile(config.Fs, "/tmp/cf-operator-hook-"+config.OperatorNamespace+"/ca-cert.pem")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(caCert)).To(Equal("the-ca-cert"))
+			})
+		})
+
+		Context("if the webhook configurations exist already", func() {
+			It("updates them", func() {
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *unstructured.Unstructured:
+						if object.GetKind() == "Secret" {
+							return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+						}
+						object.SetResourceVersion("42")
+					}
+					return nil
+				})
+				client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+					switch object.(type) {
+					case *admissionregistration.ValidatingWebhookConfiguration, *admissionregistration.MutatingWebhookConfiguration:
+						return apierrors.NewAlreadyExists(schema.GroupResource{}, "cf-operator-hook-default")
+					}
+					return nil
+				})
+
+				err := controllers.AddHooks(ctx, config, manager, generator)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Namespace label, the 2 webhook configs and the 3 CRDs with conversion webhooks
+				Expect(client.UpdateCallCount()).To(Equal(6))
+				_, object, _ := client.UpdateArgsForCall(1)
+				Expect(object.(*admissionregistration.ValidatingWebhookConfiguration).ResourceVersion).To(Equal("42"))
+				_, object, _ = client.UpdateArgsForCall(2)
+				Expect(object.(*admissionregistration.MutatingWebhookConfiguration).ResourceVersion).To(Equal("42"))
+			})
+		})
+
+		Context("if the CRDs have several versions", func() {
+			var crds []*extv1.CustomResourceDefinition
+
+			BeforeEach(func() {
+				crds = []*extv1.CustomResourceDefinition{}
+				client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+					switch object := object.(type) {
+					case *unstructured.Unstructured:
+						switch object.GetKind() {
+						case "Secret":
+							return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+						case "CustomResourceDefinition":
+							object.SetName(nn.Name)
+							object.Object["spec"] = map[string]interface{}{
+								"versions": []interface{}{
+									map[string]interface{}{"name": "v1alpha1", "served": true, "storage": true},
+									map[string]interface{}{"name": "v1beta1", "served": false, "storage": false},
+								},
+							}
+						}
+					}
+					return nil
+				})
+			})
+
+			It("points them to the conversion webhook", func() {
+				client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					if crd, ok := object.(*extv1.CustomResourceDefinition); ok {
+						crds = append(crds, crd)
+					}
+					return nil
+				})
+
+				err := controllers.AddHooks(ctx, config, manager, generator)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(crds).To(HaveLen(3))
+				for _, crd := range crds {
+					Expect(crd.Spec.Conversion.Strategy).To(Equal(extv1.WebhookConverter))
+					Expect(*crd.Spec.Conversion.WebhookClientConfig.URL).To(HaveSuffix(controllers.ConversionWebhookPath))
+					Expect(crd.Spec.Conversion.WebhookClientConfig.CABundle).ToNot(BeEmpty())
+					for _, version := range crd.Spec.Versions {
+						Expect(version.Served).To(BeTrue())
+					}
+				}
+			})
+
+			It("keeps serving the storage version, if the cluster does not support conversion webhooks", func() {
+				client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					if crd, ok := object.(*extv1.CustomResourceDefinition); ok {
+						return apierrors.NewInvalid(schema.GroupKind{Kind: "CustomResourceDefinition"}, crd.Name, nil)
+					}
+					return nil
+				})
+
+				err := controllers.AddHooks(ctx, config, manager, generator)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
 		Context("if there is a persisted cert secret already", func() {
 			BeforeEach(func() {
 				secret := &unstructured.Unstructured{
@@ -11,16 +11,17 @@ import (
 )
 
 type FakeBPMConverter struct {
-	ResourcesStub        func(string, bpmconverter.DomainNameService, string, *manifest.InstanceGroup, manifest.ReleaseImageProvider, bpm.Configs, string) (*bpmconverter.Resources, error)
+	ResourcesStub        func(string, string, bpmconverter.DomainNameService, string, *manifest.InstanceGroup, manifest.ReleaseImageProvider, bpm.Configs, string) (*bpmconverter.Resources, error)
 	resourcesMutex       sync.RWMutex
 	resourcesArgsForCall []struct {
 		arg1 string
-		arg2 bpmconverter.DomainNameService
-		arg3 string
-		arg4 *manifest.InstanceGroup
-		arg5 manifest.ReleaseImageProvider
-		arg6 bpm.Configs
-		arg7 string
+		arg2 string
+		arg3 bpmconverter.DomainNameService
+		arg4 string
+		arg5 *manifest.InstanceGroup
+		arg6 manifest.ReleaseImageProvider
+		arg7 bpm.Configs
+		arg8 string
 	}
 	resourcesReturns struct {
 		result1 *bpmconverter.Resources
@@ -34,22 +35,23 @@ type FakeBPMConverter struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBPMConverter) Resources(arg1 string, arg2 bpmconverter.DomainNameService, arg3 string, arg4 *manifest.InstanceGroup, arg5 manifest.ReleaseImageProvider, arg6 bpm.Configs, arg7 string) (*bpmconverter.Resources, error) {
+func (fake *FakeBPMConverter) Resources(arg1 string, arg2 string, arg3 bpmconverter.DomainNameService, arg4 string, arg5 *manifest.InstanceGroup, arg6 manifest.ReleaseImageProvider, arg7 bpm.Configs, arg8 string) (*bpmconverter.Resources, error) {
 	fake.resourcesMutex.Lock()
 	ret, specificReturn := fake.resourcesReturnsOnCall[len(fake.resourcesArgsForCall)]
 	fake.resourcesArgsForCall = append(fake.resourcesArgsForCall, struct {
 		arg1 string
-		arg2 bpmconverter.DomainNameService
-		arg3 string
-		arg4 *manifest.InstanceGroup
-		arg5 manifest.ReleaseImageProvider
-		arg6 bpm.Configs
-		arg7 string
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
-	fake.recordInvocation("Resources", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+		arg2 string
+		arg3 bpmconverter.DomainNameService
+		arg4 string
+		arg5 *manifest.InstanceGroup
+		arg6 manifest.ReleaseImageProvider
+		arg7 bpm.Configs
+		arg8 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
+	fake.recordInvocation("Resources", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.resourcesMutex.Unlock()
 	if fake.ResourcesStub != nil {
-		return fake.ResourcesStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return fake.ResourcesStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -64,17 +66,17 @@ func (fake *FakeBPMConverter) ResourcesCallCount() int {
 	return len(fake.resourcesArgsForCall)
 }
 
-func (fake *FakeBPMConverter) ResourcesCalls(stub func(string, bpmconverter.DomainNameService, string, *manifest.InstanceGroup, manifest.ReleaseImageProvider, bpm.Configs, string) (*bpmconverter.Resources, error)) {
+func (fake *FakeBPMConverter) ResourcesCalls(stub func(string, string, bpmconverter.DomainNameService, string, *manifest.InstanceGroup, manifest.ReleaseImageProvider, bpm.Configs, string) (*bpmconverter.Resources, error)) {
 	fake.resourcesMutex.Lock()
 	defer fake.resourcesMutex.Unlock()
 	fake.ResourcesStub = stub
 }
 
-func (fake *FakeBPMConverter) ResourcesArgsForCall(i int) (string, bpmconverter.DomainNameService, string, *manifest.InstanceGroup, manifest.ReleaseImageProvider, bpm.Configs, string) {
+func (fake *FakeBPMConverter) ResourcesArgsForCall(i int) (string, string, bpmconverter.DomainNameService, string, *manifest.InstanceGroup, manifest.ReleaseImageProvider, bpm.Configs, string) {
 	fake.resourcesMutex.RLock()
 	defer fake.resourcesMutex.RUnlock()
 	argsForCall := fake.resourcesArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeBPMConverter) ResourcesReturns(result1 *bpmconverter.Resources, result2 error) {
@@ -11,13 +11,15 @@ import (
 )
 
 type FakeJobFactory struct {
-	InstanceGroupManifestJobStub        func(string, manifest.Manifest, converter.LinkInfos, bool) (*v1alpha1.QuarksJob, error)
+	InstanceGroupManifestJobStub        func(string, string, manifest.Manifest, converter.LinkInfos, bool, string) (*v1alpha1.QuarksJob, error)
 	instanceGroupManifestJobMutex       sync.RWMutex
 	instanceGroupManifestJobArgsForCall []struct {
 		arg1 string
-		arg2 manifest.Manifest
-		arg3 converter.LinkInfos
-		arg4 bool
+		arg2 string
+		arg3 manifest.Manifest
+		arg4 converter.LinkInfos
+		arg5 bool
+		arg6 string
 	}
 	instanceGroupManifestJobReturns struct {
 		result1 *v1alpha1.QuarksJob
@@ -27,11 +29,12 @@ type FakeJobFactory struct {
 		result1 *v1alpha1.QuarksJob
 		result2 error
 	}
-	VariableInterpolationJobStub        func(string, manifest.Manifest) (*v1alpha1.QuarksJob, error)
+	VariableInterpolationJobStub        func(string, string, manifest.Manifest) (*v1alpha1.QuarksJob, error)
 	variableInterpolationJobMutex       sync.RWMutex
 	variableInterpolationJobArgsForCall []struct {
 		arg1 string
-		arg2 manifest.Manifest
+		arg2 string
+		arg3 manifest.Manifest
 	}
 	variableInterpolationJobReturns struct {
 		result1 *v1alpha1.QuarksJob
@@ -45,19 +48,21 @@ type FakeJobFactory struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeJobFactory) InstanceGroupManifestJob(arg1 string, arg2 manifest.Manifest, arg3 converter.LinkInfos, arg4 bool) (*v1alpha1.QuarksJob, error) {
+func (fake *FakeJobFactory) InstanceGroupManifestJob(arg1 string, arg2 string, arg3 manifest.Manifest, arg4 converter.LinkInfos, arg5 bool, arg6 string) (*v1alpha1.QuarksJob, error) {
 	fake.instanceGroupManifestJobMutex.Lock()
 	ret, specificReturn := fake.instanceGroupManifestJobReturnsOnCall[len(fake.instanceGroupManifestJobArgsForCall)]
 	fake.instanceGroupManifestJobArgsForCall = append(fake.instanceGroupManifestJobArgsForCall, struct {
 		arg1 string
-		arg2 manifest.Manifest
-		arg3 converter.LinkInfos
-		arg4 bool
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("InstanceGroupManifestJob", []interface{}{arg1, arg2, arg3, arg4})
+		arg2 string
+		arg3 manifest.Manifest
+		arg4 converter.LinkInfos
+		arg5 bool
+		arg6 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("InstanceGroupManifestJob", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.instanceGroupManifestJobMutex.Unlock()
 	if fake.InstanceGroupManifestJobStub != nil {
-		return fake.InstanceGroupManifestJobStub(arg1, arg2, arg3, arg4)
+		return fake.InstanceGroupManifestJobStub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -72,17 +77,17 @@ func (fake *FakeJobFactory) InstanceGroupManifestJobCallCount() int {
 	return len(fake.instanceGroupManifestJobArgsForCall)
 }
 
-func (fake *FakeJobFactory) InstanceGroupManifestJobCalls(stub func(string, manifest.Manifest, converter.LinkInfos, bool) (*v1alpha1.QuarksJob, error)) {
+func (fake *FakeJobFactory) InstanceGroupManifestJobCalls(stub func(string, string, manifest.Manifest, converter.LinkInfos, bool, string) (*v1alpha1.QuarksJob, error)) {
 	fake.instanceGroupManifestJobMutex.Lock()
 	defer fake.instanceGroupManifestJobMutex.Unlock()
 	fake.InstanceGroupManifestJobStub = stub
 }
 
-func (fake *FakeJobFactory) InstanceGroupManifestJobArgsForCall(i int) (string, manifest.Manifest, converter.LinkInfos, bool) {
+func (fake *FakeJobFactory) InstanceGroupManifestJobArgsForCall(i int) (string, string, manifest.Manifest, converter.LinkInfos, bool, string) {
 	fake.instanceGroupManifestJobMutex.RLock()
 	defer fake.instanceGroupManifestJobMutex.RUnlock()
 	argsForCall := fake.instanceGroupManifestJobArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeJobFactory) InstanceGroupManifestJobReturns(result1 *v1alpha1.QuarksJob, result2 error) {
@@ -111,17 +116,18 @@ func (fake *FakeJobFactory) InstanceGroupManifestJobReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeJobFactory) VariableInterpolationJob(arg1 string, arg2 manifest.Manifest) (*v1alpha1.QuarksJob, error) {
+func (fake *FakeJobFactory) VariableInterpolationJob(arg1 string, arg2 string, arg3 manifest.Manifest) (*v1alpha1.QuarksJob, error) {
 	fake.variableInterpolationJobMutex.Lock()
 	ret, specificReturn := fake.variableInterpolationJobReturnsOnCall[len(fake.variableInterpolationJobArgsForCall)]
 	fake.variableInterpolationJobArgsForCall = append(fake.variableInterpolationJobArgsForCall, struct {
 		arg1 string
-		arg2 manifest.Manifest
-	}{arg1, arg2})
-	fake.recordInvocation("VariableInterpolationJob", []interface{}{arg1, arg2})
+		arg2 string
+		arg3 manifest.Manifest
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("VariableInterpolationJob", []interface{}{arg1, arg2, arg3})
 	fake.variableInterpolationJobMutex.Unlock()
 	if fake.VariableInterpolationJobStub != nil {
-		return fake.VariableInterpolationJobStub(arg1, arg2)
+		return fake.VariableInterpolationJobStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -136,17 +142,17 @@ func (fake *FakeJobFactory) VariableInterpolationJobCallCount() int {
 	return len(fake.variableInterpolationJobArgsForCall)
 }
 
-func (fake *FakeJobFactory) VariableInterpolationJobCalls(stub func(string, manifest.Manifest) (*v1alpha1.QuarksJob, error)) {
+func (fake *FakeJobFactory) VariableInterpolationJobCalls(stub func(string, string, manifest.Manifest) (*v1alpha1.QuarksJob, error)) {
 	fake.variableInterpolationJobMutex.Lock()
 	defer fake.variableInterpolationJobMutex.Unlock()
 	fake.VariableInterpolationJobStub = stub
 }
 
-func (fake *FakeJobFactory) VariableInterpolationJobArgsForCall(i int) (string, manifest.Manifest) {
+func (fake *FakeJobFactory) VariableInterpolationJobArgsForCall(i int) (string, string, manifest.Manifest) {
 	fake.variableInterpolationJobMutex.RLock()
 	defer fake.variableInterpolationJobMutex.RUnlock()
 	argsForCall := fake.variableInterpolationJobArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeJobFactory) VariableInterpolationJobReturns(result1 *v1alpha1.QuarksJob, result2 error) {
@@ -10,11 +10,12 @@ import (
 )
 
 type FakeVariablesConverter struct {
-	VariablesStub        func(string, []manifest.Variable) ([]v1alpha1.QuarksSecret, error)
+	VariablesStub        func(string, string, []manifest.Variable) ([]v1alpha1.QuarksSecret, error)
 	variablesMutex       sync.RWMutex
 	variablesArgsForCall []struct {
 		arg1 string
-		arg2 []manifest.Variable
+		arg2 string
+		arg3 []manifest.Variable
 	}
 	variablesReturns struct {
 		result1 []v1alpha1.QuarksSecret
@@ -28,22 +29,23 @@ type FakeVariablesConverter struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeVariablesConverter) Variables(arg1 string, arg2 []manifest.Variable) ([]v1alpha1.QuarksSecret, error) {
-	var arg2Copy []manifest.Variable
-	if arg2 != nil {
-		arg2Copy = make([]manifest.Variable, len(arg2))
-		copy(arg2Copy, arg2)
+func (fake *FakeVariablesConverter) Variables(arg1 string, arg2 string, arg3 []manifest.Variable) ([]v1alpha1.QuarksSecret, error) {
+	var arg3Copy []manifest.Variable
+	if arg3 != nil {
+		arg3Copy = make([]manifest.Variable, len(arg3))
+		copy(arg3Copy, arg3)
 	}
 	fake.variablesMutex.Lock()
 	ret, specificReturn := fake.variablesReturnsOnCall[len(fake.variablesArgsForCall)]
 	fake.variablesArgsForCall = append(fake.variablesArgsForCall, struct {
 		arg1 string
-		arg2 []manifest.Variable
-	}{arg1, arg2Copy})
-	fake.recordInvocation("Variables", []interface{}{arg1, arg2Copy})
+		arg2 string
+		arg3 []manifest.Variable
+	}{arg1, arg2, arg3Copy})
+	fake.recordInvocation("Variables", []interface{}{arg1, arg2, arg3Copy})
 	fake.variablesMutex.Unlock()
 	if fake.VariablesStub != nil {
-		return fake.VariablesStub(arg1, arg2)
+		return fake.VariablesStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -58,17 +60,17 @@ func (fake *FakeVariablesConverter) VariablesCallCount() int {
 	return len(fake.variablesArgsForCall)
 }
 
-func (fake *FakeVariablesConverter) VariablesCalls(stub func(string, []manifest.Variable) ([]v1alpha1.QuarksSecret, error)) {
+func (fake *FakeVariablesConverter) VariablesCalls(stub func(string, string, []manifest.Variable) ([]v1alpha1.QuarksSecret, error)) {
 	fake.variablesMutex.Lock()
 	defer fake.variablesMutex.Unlock()
 	fake.VariablesStub = stub
 }
 
-func (fake *FakeVariablesConverter) VariablesArgsForCall(i int) (string, []manifest.Variable) {
+func (fake *FakeVariablesConverter) VariablesArgsForCall(i int) (string, string, []manifest.Variable) {
 	fake.variablesMutex.RLock()
 	defer fake.variablesMutex.RUnlock()
 	argsForCall := fake.variablesArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeVariablesConverter) VariablesReturns(result1 []v1alpha1.QuarksSecret, result2 error) {
@@ -18,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/reference"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
@@ -33,7 +34,7 @@ func AddRestart(ctx context.Context, config *config.Config, mgr manager.Manager)
 	ctx = ctxlog.NewContextWithRecorder(ctx, name+"-reconciler", mgr.GetEventRecorderFor(name+"-recorder"))
 	r := NewRestartReconciler(ctx, config, mgr)
 
-	c, err := controller.New(name+"-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, name+"-controller", mgr, controller.Options{
 		Reconciler: r,
 	})
 	if err != nil {
@@ -16,9 +16,10 @@ import (
 
 	"code.cloudfoundry.org/cf-operator/pkg/kube/apis"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
-	"code.cloudfoundry.org/quarks-utils/pkg/meltdown"
 )
 
 // RestartKey has the timestamp of the last restart triggered by this reconciler
@@ -45,7 +46,7 @@ func (r *ReconcileRestart) Reconcile(request reconcile.Request) (reconcile.Resul
 	pod := &corev1.Pod{}
 
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	log.Info(ctx, "Reconciling entangled pod ", request.NamespacedName)
@@ -61,11 +62,6 @@ func (r *ReconcileRestart) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
-	if meltdown.NewAnnotationWindow(r.config.MeltdownDuration, pod.ObjectMeta.Annotations).Contains(time.Now()) {
-		log.WithEvent(pod, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", pod.Name, r.config.MeltdownRequeueAfter)
-		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
-	}
-
 	// make sure this pod still has a valid entanglement
 	if !validEntanglement(pod.GetAnnotations()) {
 		return reconcile.Result{}, nil
@@ -79,21 +75,17 @@ func (r *ReconcileRestart) Reconcile(request reconcile.Request) (reconcile.Resul
 				log.Debugf(ctx, "Skip pod reconcile: %s", err)
 				return reconcile.Result{}, nil
 			}
+			metrics.QuarksLinkRestarts.WithLabelValues(request.Namespace, "StatefulSet").Inc()
 		} else if or.Kind == "ReplicaSet" {
 			err := r.touchDeployment(ctx, request.Namespace, or.Name)
 			if err != nil {
 				log.Debugf(ctx, "Skip pod reconcile: %s", err)
 				return reconcile.Result{}, nil
 			}
+			metrics.QuarksLinkRestarts.WithLabelValues(request.Namespace, "Deployment").Inc()
 		}
 	}
 
-	meltdown.SetLastReconcile(&pod.ObjectMeta, time.Now())
-	err = r.client.Update(ctx, pod)
-	if err != nil {
-		log.WithEvent(pod, "UpdateError").Errorf(ctx, "Failed to update reconcile timestamp on quarks-link annotated pod '%s' (%v): %s", pod.Name, pod.ResourceVersion, err)
-		return reconcile.Result{}, nil
-	}
 	return reconcile.Result{}, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	certv1 "k8s.io/api/certificates/v1beta1"
 	certv1client "k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -15,6 +16,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 )
@@ -30,7 +33,7 @@ func AddCertificateSigningRequest(ctx context.Context, config *config.Config, mg
 	r := NewCertificateSigningRequestReconciler(ctx, config, mgr, certClient, controllerutil.SetControllerReference)
 
 	// Create a new controller
-	c, err := controller.New("certificate-signing-request-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "certificate-signing-request-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
 	})
@@ -43,14 +46,14 @@ func AddCertificateSigningRequest(ctx context.Context, config *config.Config, mg
 		CreateFunc: func(e event.CreateEvent) bool {
 			o := e.Object.(*certv1.CertificateSigningRequest)
 
-			return ownedByQuarksSecret(config.Namespace, o.Annotations)
+			return ownedByQuarksSecret(ctx, mgr.GetClient(), config, o.Annotations)
 		},
 		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
 		GenericFunc: func(e event.GenericEvent) bool { return false },
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			o := e.ObjectNew.(*certv1.CertificateSigningRequest)
 
-			return ownedByQuarksSecret(config.Namespace, o.Annotations)
+			return ownedByQuarksSecret(ctx, mgr.GetClient(), config, o.Annotations)
 
 		},
 	}
@@ -62,10 +65,15 @@ func AddCertificateSigningRequest(ctx context.Context, config *config.Config, mg
 	return nil
 }
 
-func ownedByQuarksSecret(namespace string, annotations map[string]string) bool {
+func ownedByQuarksSecret(ctx context.Context, c client.Client, config *config.Config, annotations map[string]string) bool {
 	if _, ok := annotations[qsv1a1.AnnotationCertSecretName]; ok {
 		if ns, ok := annotations[qsv1a1.AnnotationQSecNamespace]; ok {
-			return ns == namespace
+			watched, err := watchnamespaces.IsWatchedNamespace(ctx, c, config, ns)
+			if err != nil {
+				ctxlog.Debugf(ctx, "Skipping certificate signing request of QuarksSecret in namespace '%s': %s", ns, err)
+				return false
+			}
+			return watched
 		}
 	}
 	return false
@@ -20,6 +20,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 	"code.cloudfoundry.org/quarks-utils/pkg/names"
@@ -52,7 +54,7 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(request reconcile.Request
 	csr := &certv1.CertificateSigningRequest{}
 
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	ctxlog.Infof(ctx, "Reconciling CSR '%s'", request.Name)
@@ -148,6 +150,10 @@ func (r *ReconcileCertificateSigningRequest) Reconcile(request reconcile.Request
 			return reconcile.Result{}, err
 		}
 
+		if err := metrics.SetCertificateExpiry(namespace, secretName, csr.Status.Certificate); err != nil {
+			ctxlog.Debugf(ctx, "Not recording certificate expiry: %s", err)
+		}
+
 		// Clean up CSR and private key, no longer needed
 		err = r.deleteSecret(ctx, privateKeySecret)
 		if err != nil {
@@ -18,6 +18,7 @@ import (
 
 	credsgen "code.cloudfoundry.org/cf-operator/pkg/credsgen/in_memory_generator"
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 )
@@ -30,7 +31,7 @@ func AddQuarksSecret(ctx context.Context, config *config.Config, mgr manager.Man
 	r := NewQuarksSecretReconciler(ctx, config, mgr, credsgen.NewInMemoryGenerator(log), controllerutil.SetControllerReference)
 
 	// Create a new controller
-	c, err := controller.New("quarks-secret-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "quarks-secret-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxQuarksSecretWorkers,
 	})
@@ -20,10 +20,12 @@ import (
 
 	"code.cloudfoundry.org/cf-operator/pkg/credsgen"
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/mutate"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
-	"code.cloudfoundry.org/quarks-utils/pkg/meltdown"
 	"code.cloudfoundry.org/quarks-utils/pkg/names"
 )
 
@@ -80,7 +82,7 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 	instance := &qsv1a1.QuarksSecret{}
 
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	ctxlog.Infof(ctx, "Reconciling QuarksSecret %s", request.NamespacedName)
@@ -98,10 +100,8 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, errors.Wrap(err, "Error reading quarksSecret")
 	}
 
-	if meltdown.NewWindow(r.config.MeltdownDuration, instance.Status.LastReconcile).Contains(time.Now()) {
-		ctxlog.WithEvent(instance, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", instance.Name, r.config.MeltdownRequeueAfter)
-		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
-	}
+	ctx, span := tracing.StartForObject(ctx, "quarkssecret-reconcile", instance)
+	defer span.End()
 
 	// Check if allowed to generate secret, could be already done or
 	// created manually by a user
@@ -122,6 +122,7 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 		err = r.createPasswordSecret(ctx, instance)
 		if err != nil {
 			ctxlog.Infof(ctx, "Error generating password secret: %s", err.Error())
+			metrics.QuarksSecretGenerationFailures.WithLabelValues(instance.Namespace, string(instance.Spec.Type)).Inc()
 			return reconcile.Result{}, errors.Wrap(err, "generating password secret failed.")
 		}
 	case qsv1a1.RSAKey:
@@ -129,6 +130,7 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 		err = r.createRSASecret(ctx, instance)
 		if err != nil {
 			ctxlog.Infof(ctx, "Error generating RSA key secret: %s", err.Error())
+			metrics.QuarksSecretGenerationFailures.WithLabelValues(instance.Namespace, string(instance.Spec.Type)).Inc()
 			return reconcile.Result{}, errors.Wrap(err, "generating RSA key secret failed.")
 		}
 	case qsv1a1.SSHKey:
@@ -136,6 +138,7 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 		err = r.createSSHSecret(ctx, instance)
 		if err != nil {
 			ctxlog.Infof(ctx, "Error generating SSH key secret: %s", err.Error())
+			metrics.QuarksSecretGenerationFailures.WithLabelValues(instance.Namespace, string(instance.Spec.Type)).Inc()
 			return reconcile.Result{}, errors.Wrap(err, "generating SSH key secret failed.")
 		}
 	case qsv1a1.Certificate:
@@ -147,6 +150,7 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 				return reconcile.Result{RequeueAfter: time.Second * 5}, nil
 			}
 			ctxlog.Info(ctx, "Error generating certificate secret: "+err.Error())
+			metrics.QuarksSecretGenerationFailures.WithLabelValues(instance.Namespace, string(instance.Spec.Type)).Inc()
 			return reconcile.Result{}, errors.Wrap(err, "generating certificate secret.")
 		}
 	default:
@@ -161,8 +165,6 @@ func (r *ReconcileQuarksSecret) Reconcile(request reconcile.Request) (reconcile.
 func (r *ReconcileQuarksSecret) updateStatus(ctx context.Context, instance *qsv1a1.QuarksSecret) {
 	instance.Status.Generated = true
 
-	now := metav1.Now()
-	instance.Status.LastReconcile = &now
 	err := r.client.Status().Update(ctx, instance)
 	if err != nil {
 		ctxlog.Errorf(ctx, "could not create or update QuarksSecret status '%s': %v", instance.GetName(), err)
@@ -232,7 +234,7 @@ func (r *ReconcileQuarksSecret) createCertificateSecret(ctx context.Context, ins
 	for _, serviceRef := range instance.Spec.Request.CertificateRequest.ServiceRef {
 		service := &corev1.Service{}
 
-		err := r.client.Get(ctx, types.NamespacedName{Namespace: r.config.Namespace, Name: serviceRef.Name}, service)
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: serviceRef.Name}, service)
 
 		if err != nil {
 			return errors.Wrapf(err, "Failed to get service reference '%s' for QuarksSecret '%s'", serviceRef.Name, instance.Name)
@@ -321,7 +323,15 @@ func (r *ReconcileQuarksSecret) createCertificateSecret(ctx context.Context, ins
 			secret.StringData["ca"] = string(generationRequest.CA.Certificate)
 		}
 
-		return r.createSecret(ctx, instance, secret)
+		err = r.createSecret(ctx, instance, secret)
+		if err != nil {
+			return err
+		}
+
+		if err := metrics.SetCertificateExpiry(secret.Namespace, secret.Name, cert.Certificate); err != nil {
+			ctxlog.Debugf(ctx, "Not recording certificate expiry: %s", err)
+		}
+		return nil
 	default:
 		return fmt.Errorf("unrecognized signer type: %s", instance.Spec.Request.CertificateRequest.SignerType)
 	}
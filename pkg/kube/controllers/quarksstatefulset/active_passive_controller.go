@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 	"github.com/pkg/errors"
@@ -31,7 +32,7 @@ func AddStatefulSetActivePassive(ctx context.Context, config *config.Config, mgr
 	r := NewActivePassiveReconciler(ctx, config, mgr, kclient)
 
 	// Create new controller
-	c, err := controller.New("active-passive-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "active-passive-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxQuarksStatefulSetWorkers,
 	})
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 	podutil "code.cloudfoundry.org/quarks-utils/pkg/pod"
@@ -58,7 +60,7 @@ func (r *ReconcileStatefulSetActivePassive) Reconcile(request reconcile.Request)
 	qSts := &qstsv1a1.QuarksStatefulSet{}
 
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	ctxlog.Info(ctx, "Reconciling for active/passive QuarksStatefulSet", request.NamespacedName)
@@ -118,6 +120,7 @@ func (r *ReconcileStatefulSetActivePassive) markActiveContainers(ctx context.Con
 	for _, pod := range pods.Items {
 		ctxlog.WithEvent(qSts, "active-passive").Debugf(ctx, "validating probe in pod: %s", pod.Name)
 		if err := r.execContainerCmd(&pod, container, probeCmd); err != nil {
+			metrics.ActivePassiveProbes.WithLabelValues(qSts.Namespace, qSts.Name, "failure").Inc()
 			ctxlog.WithEvent(qSts, "active-passive").Debugf(
 				ctx,
 				"failed to execute active/passive probe: %s",
@@ -129,6 +132,7 @@ func (r *ReconcileStatefulSetActivePassive) markActiveContainers(ctx context.Con
 				return errors.Wrapf(err, "couldn't remove label from active pod %s", pod.Name)
 			}
 		} else {
+			metrics.ActivePassiveProbes.WithLabelValues(qSts.Namespace, qSts.Name, "success").Inc()
 			if podutil.IsPodReady(&pod) {
 				// mark as active
 				err := r.addActiveLabel(ctx, &pod, qSts)
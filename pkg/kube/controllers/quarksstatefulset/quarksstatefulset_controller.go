@@ -18,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/reference"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
@@ -32,7 +33,7 @@ func AddQuarksStatefulSet(ctx context.Context, config *config.Config, mgr manage
 	r := NewReconciler(ctx, config, mgr, controllerutil.SetControllerReference, store)
 
 	// Create a new controller
-	c, err := controller.New("quarks-statefulset-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "quarks-statefulset-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxQuarksStatefulSetWorkers,
 	})
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/pkg/errors"
 
@@ -25,9 +24,10 @@ import (
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/statefulset"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/mutate"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
-	"code.cloudfoundry.org/quarks-utils/pkg/meltdown"
 	vss "code.cloudfoundry.org/quarks-utils/pkg/versionedsecretstore"
 )
 
@@ -82,7 +82,7 @@ func (r *ReconcileQuarksStatefulSet) Reconcile(request reconcile.Request) (recon
 	qStatefulSet := &qstsv1a1.QuarksStatefulSet{}
 
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	ctxlog.Info(ctx, "Reconciling QuarksStatefulSet ", request.NamespacedName)
@@ -100,17 +100,15 @@ func (r *ReconcileQuarksStatefulSet) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{}, err
 	}
 
+	ctx, span := tracing.StartForObject(ctx, "quarksstatefulset-reconcile", qStatefulSet)
+	defer span.End()
+
 	// Update labels of versioned secrets in quarksStatefulSet spec
 	err = r.UpdateVersions(ctx, qStatefulSet)
 	if err != nil {
 		return reconcile.Result{}, ctxlog.WithEvent(qStatefulSet, "IncrementVersionError").Error(ctx, "Could not update labels of versioned secrets in QuarksStatefulSet '", request.NamespacedName, "': ", err)
 	}
 
-	if meltdown.NewWindow(r.config.MeltdownDuration, qStatefulSet.Status.LastReconcile).Contains(time.Now()) {
-		ctxlog.WithEvent(qStatefulSet, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", qStatefulSet.Name, r.config.MeltdownRequeueAfter)
-		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
-	}
-
 	// Calculate the desired statefulSets
 	desiredStatefulSets, err := r.calculateDesiredStatefulSets(ctx, qStatefulSet)
 	if err != nil {
@@ -129,14 +127,6 @@ func (r *ReconcileQuarksStatefulSet) Reconcile(request reconcile.Request) (recon
 		}
 	}
 
-	now := metav1.Now()
-	qStatefulSet.Status.LastReconcile = &now
-	err = r.client.Status().Update(ctx, qStatefulSet)
-	if err != nil {
-		ctxlog.WithEvent(qStatefulSet, "UpdateStatusError").Errorf(ctx, "Failed to update reconcile timestamp on QuarksStatefulSet '%s' (%v): %s", qStatefulSet.Name, qStatefulSet.ResourceVersion, err)
-		return reconcile.Result{Requeue: false}, nil
-	}
-
 	return reconcile.Result{}, nil
 }
 
@@ -153,7 +143,7 @@ func (r *ReconcileQuarksStatefulSet) UpdateVersions(ctx context.Context, qStatef
 			}
 			if vss.IsVersionedSecret(*secret) {
 				secretNameSplitted := strings.Split(secret.GetName(), "-")
-				latestSecret, err := r.versionedSecretStore.Latest(ctx, r.config.Namespace, strings.Join(secretNameSplitted[0:len(secretNameSplitted)-1], "-"))
+				latestSecret, err := r.versionedSecretStore.Latest(ctx, qStatefulSet.GetNamespace(), strings.Join(secretNameSplitted[0:len(secretNameSplitted)-1], "-"))
 				if err != nil {
 					return errors.Wrapf(err, "failed to read latest versioned secret %s for QuarksStatefulSet %s", secret.GetName(), qStatefulSet.GetName())
 				}
@@ -217,6 +207,8 @@ func (r *ReconcileQuarksStatefulSet) createStatefulSet(ctx context.Context, qSta
 		return errors.Wrapf(err, "could not set owner for StatefulSet '%s' to QuarksStatefulSet '%s' in namespace '%s'", statefulSet.Name, qStatefulSet.Name, qStatefulSet.Namespace)
 	}
 
+	tracing.Annotate(ctx, statefulSet)
+
 	// Create or update the StatefulSet
 	if _, err := controllerutil.CreateOrUpdate(ctx, r.client, statefulSet, mutate.StatefulSetMutateFn(statefulSet)); err != nil {
 		return errors.Wrapf(err, "could not create or update StatefulSet '%s' for QuarksStatefulSet '%s' in namespace '%s'", statefulSet.Name, qStatefulSet.Name, qStatefulSet.Namespace)
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 )
@@ -25,7 +26,7 @@ func AddStatefulSetRollout(ctx context.Context, config *config.Config, mgr manag
 	r := NewStatefulSetRolloutReconciler(ctx, config, mgr)
 
 	// Create a new controller
-	c, err := controller.New("statefulset-rollout-controller", mgr, controller.Options{
+	c, err := ratelimit.NewController(ctx, "statefulset-rollout-controller", mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: config.MaxQuarksStatefulSetWorkers,
 	})
@@ -6,9 +6,10 @@ import (
 	"strconv"
 	"time"
 
-	"code.cloudfoundry.org/quarks-utils/pkg/meltdown"
-
 	"code.cloudfoundry.org/cf-operator/pkg/kube/apis"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
@@ -65,7 +66,7 @@ type ReconcileStatefulSetRollout struct {
 // Reconcile cleans up old versions and volumeManagement statefulSet of the QuarksStatefulSet
 func (r *ReconcileStatefulSetRollout) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	// Set the ctx to be Background, as the top-level context for incoming requests.
-	ctx, cancel := context.WithTimeout(r.ctx, r.config.CtxTimeOut)
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
 	defer cancel()
 
 	ctxlog.Debug(ctx, "Reconciling StatefulSet ", request.NamespacedName)
@@ -78,10 +79,8 @@ func (r *ReconcileStatefulSetRollout) Reconcile(request reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	if meltdown.NewAnnotationWindow(r.config.MeltdownDuration, statefulSet.Annotations).Contains(time.Now()) {
-		ctxlog.WithEvent(&statefulSet, "Meltdown").Debugf(ctx, "Resource '%s' is in meltdown, requeue reconcile after %s", statefulSet.Name, r.config.MeltdownRequeueAfter)
-		return reconcile.Result{RequeueAfter: r.config.MeltdownRequeueAfter}, nil
-	}
+	ctx, span := tracing.StartForObject(ctx, "statefulset-rollout-reconcile", &statefulSet)
+	defer span.End()
 
 	var status = statefulSet.Annotations[AnnotationCanaryRollout]
 	if status == rolloutStateFailed || status == rolloutStateDone {
@@ -117,6 +116,7 @@ func (r *ReconcileStatefulSetRollout) Reconcile(request reconcile.Request) (reco
 		}
 	case rolloutStateCanary:
 		if getTimeOut(ctx, statefulSet, AnnotationCanaryWatchTime) < 0 {
+			metrics.StatefulSetRolloutTimeouts.WithLabelValues(statefulSet.Namespace, "canary").Inc()
 			newStatus = rolloutStateFailed
 			break
 		}
@@ -163,16 +163,22 @@ func (r *ReconcileStatefulSetRollout) Reconcile(request reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 	}
+	if statusChanged {
+		metrics.StatefulSetRolloutTransitions.WithLabelValues(status, newStatus).Inc()
+	}
 	return resultWithRetrigger, nil
 }
 
 func (r *ReconcileStatefulSetRollout) failIfTimedOut(ctx context.Context, statefulSet appsv1.StatefulSet, timeout string) (bool, error) {
 	if getTimeOut(ctx, statefulSet, timeout) < 0 {
+		status := statefulSet.Annotations[AnnotationCanaryRollout]
 		statefulSet.Annotations[AnnotationCanaryRollout] = rolloutStateFailed
 		if err := r.updateStatefulSet(ctx, &statefulSet); err != nil {
 			ctxlog.Debug(ctx, "Error updating StatefulSet ", statefulSet.Name, err)
 			return true, err
 		}
+		metrics.StatefulSetRolloutTimeouts.WithLabelValues(statefulSet.Namespace, "update").Inc()
+		metrics.StatefulSetRolloutTransitions.WithLabelValues(status, rolloutStateFailed).Inc()
 		return true, nil
 	}
 	return false, nil
@@ -218,8 +224,6 @@ func getTimeOut(ctx context.Context, statefulSet appsv1.StatefulSet, watchTimeAn
 }
 
 func (r *ReconcileStatefulSetRollout) updateStatefulSet(ctx context.Context, statefulSet *appsv1.StatefulSet) error {
-	meltdown.SetLastReconcile(&statefulSet.ObjectMeta, time.Now())
-
 	partition := *statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
 	state := statefulSet.Annotations[AnnotationCanaryRollout]
 	_, err := controllerutil.CreateOrUpdate(ctx, r.client, statefulSet, func() error {
@@ -40,6 +40,11 @@ func ConfigureStatefulSetForInitialRollout(statefulSet *appsv1.StatefulSet) {
 	statefulSet.Annotations[AnnotationUpdateStartTime] = strconv.FormatInt(time.Now().Unix(), 10)
 }
 
+// IsRolloutDone returns true if the canary rollout of the stateful set finished successfully
+func IsRolloutDone(statefulSet *appsv1.StatefulSet) bool {
+	return statefulSet.Annotations[AnnotationCanaryRollout] == rolloutStateDone
+}
+
 // FilterLabels filters out labels, that are not suitable for StatefulSet updates
 func FilterLabels(labels map[string]string) map[string]string {
 
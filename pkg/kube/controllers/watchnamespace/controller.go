@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 
+	"github.com/pkg/errors"
+
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -13,7 +15,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 )
 
 type Reconcile struct {
@@ -25,8 +29,14 @@ func (r *Reconcile) Reconcile(request reconcile.Request) (reconcile.Result, erro
 	return reconcile.Result{}, nil
 }
 
-// AddTerminate terminates the operator if the watch namespace disappears
-func AddTerminate(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+// AddWatchNamespace terminates the operator if the watch namespace
+// disappears. When watching several namespaces, it labels the selected
+// namespaces instead, as namespaces are created, relabeled and deleted.
+func AddWatchNamespace(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	if watchnamespaces.Enabled() {
+		return addNamespaceLabel(ctx, config, mgr)
+	}
+
 	c, err := controller.New("watch-namespace-terminate-controller", mgr, controller.Options{
 		MaxConcurrentReconciles: 1,
 		Reconciler:              &Reconcile{},
@@ -56,3 +66,39 @@ func AddTerminate(ctx context.Context, config *config.Config, mgr manager.Manage
 
 	return nil
 }
+
+func addNamespaceLabel(ctx context.Context, config *config.Config, mgr manager.Manager) error {
+	ctx = ctxlog.NewContextWithRecorder(ctx, "watch-namespace-reconciler", mgr.GetEventRecorderFor("watch-namespace-recorder"))
+	r := NewNamespaceLabelReconciler(ctx, config, mgr)
+
+	c, err := controller.New("watch-namespace-label-controller", mgr, controller.Options{
+		MaxConcurrentReconciles: 1,
+		Reconciler:              r,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Adding watch namespace controller to manager failed.")
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Namespace{}},
+		&handler.EnqueueRequestForObject{},
+		predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return true },
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				if watchnamespaces.IsWatched(e.Meta, config.OperatorNamespace) {
+					ctxlog.Infof(ctx, "Watched namespace '%s' is going away", e.Meta.GetName())
+				}
+				return false
+			},
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return watchnamespaces.Selected(e.MetaNew, config.OperatorNamespace) != watchnamespaces.IsWatched(e.MetaNew, config.OperatorNamespace)
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "Watching namespaces failed in watch namespace controller.")
+	}
+
+	return nil
+}
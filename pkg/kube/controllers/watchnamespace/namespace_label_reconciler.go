@@ -0,0 +1,88 @@
+package watchnamespace
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	log "code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// NewNamespaceLabelReconciler returns a new reconciler, which sets the watch
+// label on the selected namespaces
+func NewNamespaceLabelReconciler(ctx context.Context, config *config.Config, mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileNamespaceLabel{
+		ctx:    ctx,
+		config: config,
+		client: mgr.GetClient(),
+	}
+}
+
+// ReconcileNamespaceLabel contains necessary state for the reconcile
+type ReconcileNamespaceLabel struct {
+	ctx    context.Context
+	client client.Client
+	config *config.Config
+}
+
+// Reconcile adds the watch label to namespaces, which are selected by the
+// namespace list or selector, and removes it from namespaces, which are no
+// longer selected. The webhooks and the operator's cache only act on
+// namespaces with the label.
+func (r *ReconcileNamespaceLabel) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ns := &corev1.Namespace{}
+
+	ctx, cancel := context.WithTimeout(r.ctx, operatorconfig.CtxTimeOut(request.Namespace, r.config.CtxTimeOut))
+	defer cancel()
+
+	log.Debug(ctx, "Reconciling namespace ", request.Name)
+	err := r.client.Get(ctx, request.NamespacedName, ns)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug(ctx, "Skip namespace reconcile: namespace not found")
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	selected := watchnamespaces.Selected(ns, r.config.OperatorNamespace)
+	watched := watchnamespaces.IsWatched(ns, r.config.OperatorNamespace)
+	if selected == watched {
+		return reconcile.Result{}, nil
+	}
+
+	labels := ns.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	if selected {
+		if owner, ok := labels[webhook.LabelWatchNamespace]; ok {
+			log.WithEvent(ns, "WatchNamespaceError").Errorf(ctx, "Namespace '%s' is already watched by the operator in namespace '%s'", ns.Name, owner)
+			return reconcile.Result{}, nil
+		}
+
+		log.Infof(ctx, "Watching namespace '%s'", ns.Name)
+		labels[webhook.LabelWatchNamespace] = r.config.OperatorNamespace
+	} else {
+		log.Infof(ctx, "Stopped watching namespace '%s'", ns.Name)
+		delete(labels, webhook.LabelWatchNamespace)
+	}
+	ns.SetLabels(labels)
+
+	err = r.client.Update(ctx, ns)
+	if err != nil {
+		return reconcile.Result{}, log.WithEvent(ns, "UpdateError").Errorf(ctx, "Failed to update watch label of namespace '%s': %s", ns.Name, err)
+	}
+
+	return reconcile.Result{}, nil
+}
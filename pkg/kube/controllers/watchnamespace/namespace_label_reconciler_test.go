@@ -0,0 +1,111 @@
+package watchnamespace_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cfakes "code.cloudfoundry.org/cf-operator/pkg/kube/controllers/fakes"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers/watchnamespace"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
+	cfcfg "code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("ReconcileNamespaceLabel", func() {
+	var (
+		manager    *cfakes.FakeManager
+		client     client.Client
+		reconciler reconcile.Reconciler
+		namespaces []runtime.Object
+	)
+
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	reconcileNamespace := func(name string) *corev1.Namespace {
+		result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+
+		ns := &corev1.Namespace{}
+		Expect(client.Get(context.Background(), types.NamespacedName{Name: name}, ns)).To(Succeed())
+		return ns
+	}
+
+	BeforeEach(func() {
+		Expect(watchnamespaces.SetSelector("cf-operator/watch=true")).To(Succeed())
+		namespaces = []runtime.Object{}
+	})
+
+	JustBeforeEach(func() {
+		client = fake.NewFakeClient(namespaces...)
+		manager = &cfakes.FakeManager{}
+		manager.GetClientReturns(client)
+
+		config := &cfcfg.Config{CtxTimeOut: 10 * time.Second, OperatorNamespace: "cf-operator"}
+		_, log := helper.NewTestLogger()
+		reconciler = watchnamespace.NewNamespaceLabelReconciler(ctxlog.NewParentContext(log), config, manager)
+	})
+
+	AfterEach(func() {
+		Expect(watchnamespaces.SetSelector("")).To(Succeed())
+	})
+
+	Context("when the namespace is selected", func() {
+		BeforeEach(func() {
+			namespaces = append(namespaces, namespace("cf1", map[string]string{"cf-operator/watch": "true"}))
+		})
+
+		It("sets the watch label", func() {
+			ns := reconcileNamespace("cf1")
+			Expect(ns.Labels).To(HaveKeyWithValue(webhook.LabelWatchNamespace, "cf-operator"))
+		})
+	})
+
+	Context("when the namespace is watched by another operator", func() {
+		BeforeEach(func() {
+			namespaces = append(namespaces, namespace("cf1", map[string]string{
+				"cf-operator/watch":         "true",
+				webhook.LabelWatchNamespace: "other-operator",
+			}))
+		})
+
+		It("keeps the watch label", func() {
+			ns := reconcileNamespace("cf1")
+			Expect(ns.Labels).To(HaveKeyWithValue(webhook.LabelWatchNamespace, "other-operator"))
+		})
+	})
+
+	Context("when the namespace is no longer selected", func() {
+		BeforeEach(func() {
+			namespaces = append(namespaces, namespace("cf1", map[string]string{webhook.LabelWatchNamespace: "cf-operator"}))
+		})
+
+		It("removes the watch label", func() {
+			ns := reconcileNamespace("cf1")
+			Expect(ns.Labels).ToNot(HaveKey(webhook.LabelWatchNamespace))
+		})
+	})
+
+	Context("when the namespace doesn't exist", func() {
+		It("skips the reconcile", func() {
+			result, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "cf1"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package watchnamespace_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWatchNamespace(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "WatchNamespace Suite")
+}
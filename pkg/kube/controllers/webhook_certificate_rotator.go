@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	wh "code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+const (
+	// WebhookCertificateRenewBefore is the duration before the expiry of the
+	// webhook server certificate or its CA, when they are rotated
+	WebhookCertificateRenewBefore = 30 * 24 * time.Hour
+	// WebhookCertificateCheckInterval is the interval in which the expiry of
+	// the webhook server certificate is checked
+	WebhookCertificateCheckInterval = time.Hour
+)
+
+// WebhookCertificateRotator regenerates the webhook server certificate and
+// its CA before they expire. It runs on all operator replicas: the first
+// replica noticing the expiry rotates the certificate in the secret, all
+// replicas update the CA bundle of the webhook configurations and then write
+// the new certificate to the cert dir, from which the webhook server reloads
+// it.
+// It implements the Runnable interface of the controller-runtime manager.
+type WebhookCertificateRotator struct {
+	// RenewBefore is the duration before the expiry, when the certificate is rotated
+	RenewBefore time.Duration
+	// Interval is the duration between checks of the expiry
+	Interval time.Duration
+
+	ctx                context.Context
+	webhookConfig      *WebhookConfig
+	validatingWebhooks []*wh.OperatorWebhook
+	mutatingWebhooks   []*wh.OperatorWebhook
+	crdNames           []string
+}
+
+// NewWebhookCertificateRotator returns a new WebhookCertificateRotator
+func NewWebhookCertificateRotator(ctx context.Context, webhookConfig *WebhookConfig, validatingWebhooks []*wh.OperatorWebhook, mutatingWebhooks []*wh.OperatorWebhook, crdNames []string) *WebhookCertificateRotator {
+	return &WebhookCertificateRotator{
+		RenewBefore:        WebhookCertificateRenewBefore,
+		Interval:           WebhookCertificateCheckInterval,
+		ctx:                ctx,
+		webhookConfig:      webhookConfig,
+		validatingWebhooks: validatingWebhooks,
+		mutatingWebhooks:   mutatingWebhooks,
+		crdNames:           crdNames,
+	}
+}
+
+// Start checks the certificate on start and periodically, until the stop
+// channel is closed
+func (r *WebhookCertificateRotator) Start(stop <-chan struct{}) error {
+	err := r.Rotate(r.ctx)
+	if err != nil {
+		ctxlog.Errorf(r.ctx, "Failed to rotate the webhook server certificate: %s", err)
+	}
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			err := r.Rotate(r.ctx)
+			if err != nil {
+				ctxlog.Errorf(r.ctx, "Failed to rotate the webhook server certificate: %s", err)
+			}
+		}
+	}
+}
+
+// Rotate regenerates the certificate in the secret, if it expires within
+// RenewBefore, and reloads it, if it differs from the one in use
+func (r *WebhookCertificateRotator) Rotate(ctx context.Context) error {
+	f := r.webhookConfig
+
+	secret, err := r.getSecret(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := secretData(secret)
+	if err != nil {
+		return err
+	}
+
+	expiry, err := certificateExpiry(data["certificate"], data["ca_certificate"])
+	if err != nil {
+		return err
+	}
+
+	if time.Until(expiry) < r.RenewBefore {
+		ctxlog.Infof(ctx, "Rotating webhook server certificate, which expires at %s", expiry.Format(time.RFC3339))
+
+		newSecret, err := f.newCertificateSecret(f.certificateSecretName(), data["ca_certificate"])
+		if err != nil {
+			return errors.Wrap(err, "generating the webhook server certificate")
+		}
+		newSecret.ResourceVersion = secret.GetResourceVersion()
+
+		err = f.client.Update(ctx, newSecret)
+		if apierrors.IsConflict(err) {
+			ctxlog.Info(ctx, "Webhook server certificate was rotated by another replica")
+			secret, err = r.getSecret(ctx)
+			if err != nil {
+				return err
+			}
+			data, err = secretData(secret)
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
+			return errors.Wrap(err, "updating the webhook server certificate")
+		} else {
+			data = newSecret.Data
+		}
+	}
+
+	if bytes.Equal(data["certificate"], f.Certificate) && bytes.Equal(data["ca_certificate"], f.CaCertificate) {
+		return nil
+	}
+
+	ctxlog.Info(ctx, "Reloading rotated webhook server certificate")
+	previous := f.certificateData()
+	f.setCertificate(data)
+
+	// The API server has to trust the new CA before the webhook server
+	// presents the new certificate. The CA bundle contains the new and the
+	// previous CA, so it's valid for both certificates.
+	err = r.publishCABundle(ctx)
+	if err != nil {
+		// Keep using the previous certificate, the next check retries
+		f.setCertificate(previous)
+		return err
+	}
+
+	err = f.writeSecretFiles()
+	if err != nil {
+		return errors.Wrap(err, "writing webhook certificate files to disk")
+	}
+
+	name := f.certificateSecretName()
+	err = metrics.SetCertificateExpiry(name.Namespace, name.Name, f.Certificate)
+	if err != nil {
+		ctxlog.Debugf(ctx, "Not recording expiry of the webhook server certificate: %s", err)
+	}
+
+	return nil
+}
+
+// publishCABundle updates the CA bundle of the webhook configurations
+func (r *WebhookCertificateRotator) publishCABundle(ctx context.Context) error {
+	f := r.webhookConfig
+
+	err := f.generateValidationWebhookServerConfig(ctx, r.validatingWebhooks)
+	if err != nil {
+		return errors.Wrap(err, "updating the validating webhook server configuration")
+	}
+
+	err = f.generateMutationWebhookServerConfig(ctx, r.mutatingWebhooks)
+	if err != nil {
+		return errors.Wrap(err, "updating the mutating webhook server configuration")
+	}
+
+	err = f.generateConversionWebhookConfig(ctx, ConversionWebhookPath, r.crdNames)
+	if err != nil {
+		return errors.Wrap(err, "updating the conversion webhook configuration")
+	}
+
+	return nil
+}
+
+// getSecret reads the certificate secret. Unstructured objects are read
+// directly from the API, the cache doesn't watch the operator namespace.
+func (r *WebhookCertificateRotator) getSecret(ctx context.Context) (*unstructured.Unstructured, error) {
+	secret := &unstructured.Unstructured{}
+	secret.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "",
+		Kind:    "Secret",
+		Version: "v1",
+	})
+
+	err := r.webhookConfig.client.Get(ctx, r.webhookConfig.certificateSecretName(), secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the webhook server certificate")
+	}
+
+	return secret, nil
+}
+
+// certificateExpiry returns the earliest expiry of the PEM encoded certificates
+func certificateExpiry(certificates ...[]byte) (time.Time, error) {
+	var expiry time.Time
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		if block == nil {
+			return expiry, errors.New("failed to decode webhook server certificate")
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return expiry, errors.Wrap(err, "failed to parse webhook server certificate")
+		}
+
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+
+	return expiry, nil
+}
@@ -0,0 +1,268 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	admissionregistration "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	crc "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"code.cloudfoundry.org/cf-operator/pkg/credsgen"
+	inmemorygenerator "code.cloudfoundry.org/cf-operator/pkg/credsgen/in_memory_generator"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers"
+	cfakes "code.cloudfoundry.org/cf-operator/pkg/kube/controllers/fakes"
+	"code.cloudfoundry.org/cf-operator/testing"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	cmdhelper "code.cloudfoundry.org/quarks-utils/testing"
+)
+
+var _ = Describe("WebhookCertificateRotator", func() {
+	var (
+		manager *cfakes.FakeManager
+		client  *cfakes.FakeClient
+		ctx     context.Context
+		config  *config.Config
+		env     testing.Catalog
+		rotator *controllers.WebhookCertificateRotator
+
+		// secret is the certificate secret stored in the fake client
+		secret *corev1.Secret
+		// updatedSecrets are the secrets written by the rotator
+		updatedSecrets []*corev1.Secret
+		// caBundles are the CA bundles of the updated validating webhook configurations
+		caBundles [][]byte
+	)
+
+	certDir := func() string {
+		return "/tmp/cf-operator-hook-" + config.OperatorNamespace
+	}
+
+	BeforeEach(func() {
+		client = &cfakes.FakeClient{}
+		restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+
+		manager = &cfakes.FakeManager{}
+		controllers.AddToScheme(scheme.Scheme)
+		manager.GetSchemeReturns(scheme.Scheme)
+		manager.GetClientReturns(client)
+		manager.GetRESTMapperReturns(restMapper)
+		manager.GetWebhookServerReturns(&webhook.Server{})
+
+		config = env.DefaultConfig()
+		ctx = cmdhelper.NewContext()
+		generator := inmemorygenerator.NewInMemoryGenerator(ctxlog.ExtractLogger(ctx))
+
+		secret = nil
+		updatedSecrets = []*corev1.Secret{}
+		caBundles = [][]byte{}
+
+		client.GetCalls(func(context context.Context, nn types.NamespacedName, object runtime.Object) error {
+			u, ok := object.(*unstructured.Unstructured)
+			if !ok || u.GetKind() != "Secret" {
+				return nil
+			}
+			if secret == nil {
+				return apierrors.NewNotFound(schema.GroupResource{}, nn.Name)
+			}
+			data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+			Expect(err).ToNot(HaveOccurred())
+			u.Object = data
+			u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+			return nil
+		})
+		client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+			if s, ok := object.(*corev1.Secret); ok {
+				secret = s.DeepCopy()
+			}
+			return nil
+		})
+		client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+			switch object := object.(type) {
+			case *corev1.Secret:
+				updatedSecrets = append(updatedSecrets, object)
+				secret = object.DeepCopy()
+			case *admissionregistration.ValidatingWebhookConfiguration:
+				caBundles = append(caBundles, object.Webhooks[0].ClientConfig.CABundle)
+			}
+			return nil
+		})
+
+		err := controllers.AddHooks(ctx, config, manager, generator)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(manager.AddCallCount()).To(Equal(1))
+		runnable := manager.AddArgsForCall(0)
+		Expect(runnable).To(BeAssignableToTypeOf(&controllers.WebhookCertificateRotator{}))
+		rotator = runnable.(*controllers.WebhookCertificateRotator)
+
+		// Webhook configurations exist already from now on
+		client.CreateCalls(func(context context.Context, object runtime.Object, _ ...crc.CreateOption) error {
+			return apierrors.NewAlreadyExists(schema.GroupResource{}, "cf-operator-hook")
+		})
+	})
+
+	Context("when the certificate does not expire soon", func() {
+		It("keeps the certificate", func() {
+			err := rotator.Rotate(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(updatedSecrets).To(BeEmpty())
+			Expect(caBundles).To(BeEmpty())
+		})
+	})
+
+	Context("when the certificate expires soon", func() {
+		BeforeEach(func() {
+			// The generated certificates are valid for a year
+			rotator.RenewBefore = 2 * 365 * 24 * time.Hour
+		})
+
+		It("rotates the certificate and reloads it", func() {
+			oldCA := secret.Data["ca_certificate"]
+			oldCert := secret.Data["certificate"]
+
+			err := rotator.Rotate(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(updatedSecrets).To(HaveLen(1))
+			newSecret := updatedSecrets[0]
+			Expect(newSecret.Data["certificate"]).ToNot(Equal(oldCert))
+			Expect(newSecret.Data["ca_certificate"]).ToNot(Equal(oldCA))
+			Expect(newSecret.Data["previous_ca_certificate"]).To(Equal(oldCA))
+
+			cert, err := afero.ReadFile(config.Fs, certDir()+"/tls.crt")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert).To(Equal(newSecret.Data["certificate"]))
+		})
+
+		It("keeps the previous CA in the CA bundle of the webhook configurations", func() {
+			oldCA := string(secret.Data["ca_certificate"])
+
+			err := rotator.Rotate(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(caBundles).To(HaveLen(1))
+			Expect(string(caBundles[0])).To(HavePrefix(string(updatedSecrets[0].Data["ca_certificate"])))
+			Expect(string(caBundles[0])).To(ContainSubstring(oldCA))
+		})
+
+		It("publishes the CA bundle before the webhook server uses the new certificate", func() {
+			oldCert := secret.Data["certificate"]
+			served := [][]byte{}
+			client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+				switch object := object.(type) {
+				case *corev1.Secret:
+					updatedSecrets = append(updatedSecrets, object)
+					secret = object.DeepCopy()
+				case *admissionregistration.ValidatingWebhookConfiguration:
+					cert, err := afero.ReadFile(config.Fs, certDir()+"/tls.crt")
+					Expect(err).ToNot(HaveOccurred())
+					served = append(served, cert)
+				}
+				return nil
+			})
+
+			err := rotator.Rotate(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(served).To(Equal([][]byte{oldCert}))
+		})
+
+		It("keeps the previous certificate, if the CA bundle can't be published", func() {
+			oldCert := secret.Data["certificate"]
+			client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+				switch object := object.(type) {
+				case *corev1.Secret:
+					updatedSecrets = append(updatedSecrets, object)
+					secret = object.DeepCopy()
+				case *admissionregistration.ValidatingWebhookConfiguration:
+					return apierrors.NewInternalError(errors.New("fake-error"))
+				}
+				return nil
+			})
+
+			err := rotator.Rotate(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("updating the validating webhook server configuration"))
+
+			cert, err := afero.ReadFile(config.Fs, certDir()+"/tls.crt")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert).To(Equal(oldCert))
+
+			// The next check publishes the bundle and reloads the certificate
+			client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+				return nil
+			})
+			rotator.RenewBefore = 0
+			err = rotator.Rotate(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			cert, err = afero.ReadFile(config.Fs, certDir()+"/tls.crt")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert).To(Equal(updatedSecrets[0].Data["certificate"]))
+		})
+
+		Context("when another replica rotates the certificate at the same time", func() {
+			It("uses the certificate of the other replica", func() {
+				other := secret.DeepCopy()
+				other.Data["certificate"] = updatedCertificate()
+
+				client.UpdateCalls(func(context context.Context, object runtime.Object, _ ...crc.UpdateOption) error {
+					if _, ok := object.(*corev1.Secret); ok {
+						secret = other
+						return apierrors.NewConflict(schema.GroupResource{}, object.(*corev1.Secret).Name, nil)
+					}
+					return nil
+				})
+
+				err := rotator.Rotate(ctx)
+				Expect(err).ToNot(HaveOccurred())
+
+				cert, err := afero.ReadFile(config.Fs, certDir()+"/tls.crt")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cert).To(Equal(other.Data["certificate"]))
+			})
+		})
+	})
+
+	Context("when another replica rotated the certificate", func() {
+		It("reloads the certificate", func() {
+			secret.Data["certificate"] = updatedCertificate()
+
+			err := rotator.Rotate(ctx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(updatedSecrets).To(BeEmpty())
+			cert, err := afero.ReadFile(config.Fs, certDir()+"/tls.crt")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert).To(Equal(secret.Data["certificate"]))
+			Expect(caBundles).To(HaveLen(1))
+		})
+	})
+})
+
+// updatedCertificate returns a new certificate, which does not expire soon
+func updatedCertificate() []byte {
+	generator := inmemorygenerator.NewInMemoryGenerator(ctxlog.ExtractLogger(cmdhelper.NewContext()))
+	cert, err := generator.GenerateCertificate("other", credsgen.CertificateGenerationRequest{
+		CommonName: "other",
+		IsCA:       true,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	return cert.Certificate
+}
@@ -14,19 +14,29 @@ import (
 
 	admissionregistration "k8s.io/api/admissionregistration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	machinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"code.cloudfoundry.org/cf-operator/pkg/credsgen"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
 )
 
+// webhookConfiguration is a validating or mutating webhook configuration
+type webhookConfiguration interface {
+	runtime.Object
+	metav1.Object
+}
+
 // WebhookConfig generates certificates and the configuration for the webhook server
 type WebhookConfig struct {
 	ConfigName string
@@ -36,6 +46,8 @@ type WebhookConfig struct {
 	Key           []byte
 	CaCertificate []byte
 	CaKey         []byte
+	// PreviousCaCertificate is the CA before the last rotation
+	PreviousCaCertificate []byte
 
 	client    client.Client
 	config    *config.Config
@@ -54,12 +66,11 @@ func NewWebhookConfig(c client.Client, config *config.Config, generator credsgen
 }
 
 // SetupCertificate ensures that a CA and a certificate is available for the
-// webhook server
+// webhook server. Several operator replicas might race to create the
+// certificate secret, the ones losing the race use the certificate of the
+// winner.
 func (f *WebhookConfig) setupCertificate(ctx context.Context) error {
-	secretNamespacedName := machinerytypes.NamespacedName{
-		Name:      "cf-operator-webhook-server-cert",
-		Namespace: f.config.OperatorNamespace,
-	}
+	secretNamespacedName := f.certificateSecretName()
 
 	// We have to query for the Secret using an unstructured object because the cache for the structured
 	// client is not initialized yet at this point in time. See https://github.com/kubernetes-sigs/controller-runtime/issues/180
@@ -73,83 +84,26 @@ func (f *WebhookConfig) setupCertificate(ctx context.Context) error {
 	err := f.client.Get(ctx, secretNamespacedName, secret)
 	if err != nil && apierrors.IsNotFound(err) {
 		ctxlog.Info(ctx, "Creating webhook server certificate")
-
-		// Generate CA
-		caRequest := credsgen.CertificateGenerationRequest{
-			CommonName: "SCF CA",
-			IsCA:       true,
-		}
-		caCert, err := f.generator.GenerateCertificate("webhook-server-ca", caRequest)
-		if err != nil {
-			return err
-		}
-
-		commonName := f.config.WebhookServerHost
-		// If provider is GKE, use service address
-		if f.config.WebhookUseServiceRef {
-			commonName = "cf-operator-webhook." + f.config.OperatorNamespace + ".svc"
-		}
-
-		// Generate Certificate
-		request := credsgen.CertificateGenerationRequest{
-			IsCA:       false,
-			CommonName: commonName,
-			CA: credsgen.Certificate{
-				IsCA:        true,
-				PrivateKey:  caCert.PrivateKey,
-				Certificate: caCert.Certificate,
-			},
-		}
-		cert, err := f.generator.GenerateCertificate("webhook-server-cert", request)
-		if err != nil {
-			return err
-		}
-
-		newSecret := &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      secretNamespacedName.Name,
-				Namespace: secretNamespacedName.Namespace,
-			},
-			Data: map[string][]byte{
-				"certificate":    cert.Certificate,
-				"private_key":    cert.PrivateKey,
-				"ca_certificate": caCert.Certificate,
-				"ca_private_key": caCert.PrivateKey,
-			},
+		err = f.createCertificate(ctx, secretNamespacedName)
+		if apierrors.IsAlreadyExists(err) {
+			ctxlog.Info(ctx, "Webhook server certificate was created by another replica")
+			err = f.client.Get(ctx, secretNamespacedName, secret)
+			if err != nil {
+				return errors.Wrap(err, "getting the webhook server certificate")
+			}
+			err = f.loadCertificate(secret)
 		}
-		err = f.client.Create(ctx, newSecret)
 		if err != nil {
 			return err
 		}
-
-		f.CaKey = caCert.PrivateKey
-		f.CaCertificate = caCert.Certificate
-		f.Key = cert.PrivateKey
-		f.Certificate = cert.Certificate
+	} else if err != nil {
+		return errors.Wrap(err, "getting the webhook server certificate")
 	} else {
 		ctxlog.Info(ctx, "Not creating the webhook server certificate because it already exists")
-		data := secret.Object["data"].(map[string]interface{})
-		caKey, err := base64.StdEncoding.DecodeString(data["ca_private_key"].(string))
-		if err != nil {
-			return err
-		}
-		caCert, err := base64.StdEncoding.DecodeString(data["ca_certificate"].(string))
-		if err != nil {
-			return err
-		}
-		key, err := base64.StdEncoding.DecodeString(data["private_key"].(string))
-		if err != nil {
-			return err
-		}
-		cert, err := base64.StdEncoding.DecodeString(data["certificate"].(string))
+		err = f.loadCertificate(secret)
 		if err != nil {
 			return err
 		}
-
-		f.CaKey = caKey
-		f.CaCertificate = caCert
-		f.Key = key
-		f.Certificate = cert
 	}
 
 	err = f.writeSecretFiles()
@@ -157,9 +111,165 @@ func (f *WebhookConfig) setupCertificate(ctx context.Context) error {
 		return errors.Wrap(err, "writing webhook certificate files to disk")
 	}
 
+	err = metrics.SetCertificateExpiry(secretNamespacedName.Namespace, secretNamespacedName.Name, f.Certificate)
+	if err != nil {
+		ctxlog.Debugf(ctx, "Not recording expiry of the webhook server certificate: %s", err)
+	}
+
 	return nil
 }
 
+// certificateSecretName returns the name of the secret, which holds the
+// webhook server certificate
+func (f *WebhookConfig) certificateSecretName() machinerytypes.NamespacedName {
+	return machinerytypes.NamespacedName{
+		Name:      "cf-operator-webhook-server-cert",
+		Namespace: f.config.OperatorNamespace,
+	}
+}
+
+// createCertificate generates a CA and a certificate and persists them in
+// the secret
+func (f *WebhookConfig) createCertificate(ctx context.Context, secretNamespacedName machinerytypes.NamespacedName) error {
+	newSecret, err := f.newCertificateSecret(secretNamespacedName, nil)
+	if err != nil {
+		return err
+	}
+
+	err = f.client.Create(ctx, newSecret)
+	if err != nil {
+		return err
+	}
+
+	f.setCertificate(newSecret.Data)
+
+	return nil
+}
+
+// newCertificateSecret generates a CA and a certificate signed by it. The
+// previous CA is kept in the secret, so it stays in the CA bundle of the
+// webhook configurations while replicas still serve the old certificate.
+func (f *WebhookConfig) newCertificateSecret(secretNamespacedName machinerytypes.NamespacedName, previousCaCertificate []byte) (*corev1.Secret, error) {
+	// Generate CA
+	caRequest := credsgen.CertificateGenerationRequest{
+		CommonName: "SCF CA",
+		IsCA:       true,
+	}
+	caCert, err := f.generator.GenerateCertificate("webhook-server-ca", caRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	commonName := f.config.WebhookServerHost
+	// If provider is GKE, use service address
+	if f.config.WebhookUseServiceRef {
+		commonName = "cf-operator-webhook." + f.config.OperatorNamespace + ".svc"
+	}
+
+	// Generate Certificate
+	request := credsgen.CertificateGenerationRequest{
+		IsCA:       false,
+		CommonName: commonName,
+		CA: credsgen.Certificate{
+			IsCA:        true,
+			PrivateKey:  caCert.PrivateKey,
+			Certificate: caCert.Certificate,
+		},
+	}
+	cert, err := f.generator.GenerateCertificate("webhook-server-cert", request)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{
+		"certificate":    cert.Certificate,
+		"private_key":    cert.PrivateKey,
+		"ca_certificate": caCert.Certificate,
+		"ca_private_key": caCert.PrivateKey,
+	}
+	if len(previousCaCertificate) > 0 {
+		data["previous_ca_certificate"] = previousCaCertificate
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretNamespacedName.Name,
+			Namespace: secretNamespacedName.Namespace,
+		},
+		Data: data,
+	}, nil
+}
+
+// loadCertificate reads the CA and the certificate from the existing secret
+func (f *WebhookConfig) loadCertificate(secret *unstructured.Unstructured) error {
+	data, err := secretData(secret)
+	if err != nil {
+		return err
+	}
+
+	f.setCertificate(data)
+
+	return nil
+}
+
+func (f *WebhookConfig) setCertificate(data map[string][]byte) {
+	f.CaKey = data["ca_private_key"]
+	f.CaCertificate = data["ca_certificate"]
+	f.PreviousCaCertificate = data["previous_ca_certificate"]
+	f.Key = data["private_key"]
+	f.Certificate = data["certificate"]
+}
+
+// certificateData returns the certificates in use, like they are stored in
+// the secret
+func (f *WebhookConfig) certificateData() map[string][]byte {
+	return map[string][]byte{
+		"ca_private_key":          f.CaKey,
+		"ca_certificate":          f.CaCertificate,
+		"previous_ca_certificate": f.PreviousCaCertificate,
+		"private_key":             f.Key,
+		"certificate":             f.Certificate,
+	}
+}
+
+// caBundle returns the CA and the previous CA, if the certificate was rotated
+func (f *WebhookConfig) caBundle() []byte {
+	bundle := append([]byte{}, f.CaCertificate...)
+	if len(f.PreviousCaCertificate) > 0 {
+		if len(bundle) > 0 && bundle[len(bundle)-1] != '\n' {
+			bundle = append(bundle, '\n')
+		}
+		bundle = append(bundle, f.PreviousCaCertificate...)
+	}
+	return bundle
+}
+
+// secretData decodes the data of the certificate secret
+func secretData(secret *unstructured.Unstructured) (map[string][]byte, error) {
+	data, ok := secret.Object["data"].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("webhook server certificate secret '%s' has no data", secret.GetName())
+	}
+
+	decoded := map[string][]byte{}
+	for _, key := range []string{"ca_private_key", "ca_certificate", "private_key", "certificate", "previous_ca_certificate"} {
+		value, ok := data[key].(string)
+		if !ok {
+			if key == "previous_ca_certificate" {
+				continue
+			}
+			return nil, errors.Errorf("webhook server certificate secret '%s' has no '%s'", secret.GetName(), key)
+		}
+		v, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding '%s' of webhook server certificate secret '%s'", key, secret.GetName())
+		}
+		decoded[key] = v
+	}
+
+	return decoded, nil
+}
+
 func (f *WebhookConfig) generateValidationWebhookServerConfig(ctx context.Context, webhooks []*webhook.OperatorWebhook) error {
 	if len(f.CaCertificate) == 0 {
 		return errors.Errorf("can not create a webhook server config with an empty ca certificate")
@@ -176,7 +286,7 @@ func (f *WebhookConfig) generateValidationWebhookServerConfig(ctx context.Contex
 
 		if f.config.WebhookUseServiceRef {
 			clientConfig := admissionregistration.WebhookClientConfig{
-				CABundle: f.CaCertificate,
+				CABundle: f.caBundle(),
 				Service: &admissionregistration.ServiceReference{
 					Name:      "cf-operator-webhook",
 					Namespace: f.config.OperatorNamespace,
@@ -193,15 +303,14 @@ func (f *WebhookConfig) generateValidationWebhookServerConfig(ctx context.Contex
 			urlString := url.String()
 
 			clientConfig := admissionregistration.WebhookClientConfig{
-				CABundle: f.CaCertificate,
+				CABundle: f.caBundle(),
 				URL:      &urlString,
 			}
 			config.Webhooks = append(config.Webhooks, f.newValidatingWebhook(webhook, clientConfig))
 		}
 	}
 	ctxlog.Debugf(ctx, "Creating validation webhook config '%s'", config.Name)
-	f.client.Delete(ctx, config)
-	return f.client.Create(ctx, config)
+	return f.applyWebhookConfig(ctx, config, "ValidatingWebhookConfiguration")
 }
 
 func (f *WebhookConfig) generateMutationWebhookServerConfig(ctx context.Context, webhooks []*webhook.OperatorWebhook) error {
@@ -225,7 +334,7 @@ func (f *WebhookConfig) generateMutationWebhookServerConfig(ctx context.Context,
 					Namespace: f.config.OperatorNamespace,
 					Path:      &webhook.Path,
 				},
-				CABundle: f.CaCertificate,
+				CABundle: f.caBundle(),
 			}
 			config.Webhooks = append(config.Webhooks, f.newMutatingWebhook(webhook, clientConfig))
 		} else {
@@ -237,7 +346,7 @@ func (f *WebhookConfig) generateMutationWebhookServerConfig(ctx context.Context,
 			urlString := url.String()
 
 			clientConfig := admissionregistration.WebhookClientConfig{
-				CABundle: f.CaCertificate,
+				CABundle: f.caBundle(),
 				URL:      &urlString,
 			}
 			config.Webhooks = append(config.Webhooks, f.newMutatingWebhook(webhook, clientConfig))
@@ -245,8 +354,97 @@ func (f *WebhookConfig) generateMutationWebhookServerConfig(ctx context.Context,
 	}
 
 	ctxlog.Debugf(ctx, "Creating mutating webhook config '%s'", config.Name)
-	f.client.Delete(ctx, &config)
-	return f.client.Create(ctx, &config)
+	return f.applyWebhookConfig(ctx, &config, "MutatingWebhookConfiguration")
+}
+
+// generateConversionWebhookConfig points the CRDs to the conversion webhook
+// and serves all their versions. Clusters without support for conversion
+// webhooks reject the CRD, those CRDs keep serving the storage version only.
+func (f *WebhookConfig) generateConversionWebhookConfig(ctx context.Context, webhookPath string, crdNames []string) error {
+	if len(f.CaCertificate) == 0 {
+		return errors.Errorf("can not create a conversion webhook config with an empty ca certificate")
+	}
+
+	clientConfig := &extv1.WebhookClientConfig{
+		CABundle: f.caBundle(),
+	}
+	if f.config.WebhookUseServiceRef {
+		clientConfig.Service = &extv1.ServiceReference{
+			Name:      "cf-operator-webhook",
+			Namespace: f.config.OperatorNamespace,
+			Path:      &webhookPath,
+		}
+	} else {
+		url := url.URL{
+			Scheme: "https",
+			Host:   net.JoinHostPort(f.config.WebhookServerHost, strconv.Itoa(int(f.config.WebhookServerPort))),
+			Path:   webhookPath,
+		}
+		urlString := url.String()
+		clientConfig.URL = &urlString
+	}
+
+	for _, name := range crdNames {
+		ctxlog.Debugf(ctx, "Setting conversion webhook of CRD '%s'", name)
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			// The cache is not started yet, see setupCertificate
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+			err := f.client.Get(ctx, machinerytypes.NamespacedName{Name: name}, existing)
+			if err != nil {
+				return err
+			}
+
+			crd := &extv1.CustomResourceDefinition{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, crd)
+			if err != nil {
+				return err
+			}
+
+			crd.Spec.Conversion = &extv1.CustomResourceConversion{
+				Strategy:                 extv1.WebhookConverter,
+				WebhookClientConfig:      clientConfig,
+				ConversionReviewVersions: []string{"v1beta1"},
+			}
+			for i := range crd.Spec.Versions {
+				crd.Spec.Versions[i].Served = true
+			}
+
+			return f.client.Update(ctx, crd)
+		})
+		if apierrors.IsInvalid(err) {
+			ctxlog.Errorf(ctx, "Only serving the storage version of CRD '%s', the cluster does not support conversion webhooks: %s", name, err)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "setting conversion webhook of CRD '%s'", name)
+		}
+	}
+
+	return nil
+}
+
+// applyWebhookConfig creates the webhook configuration or replaces an
+// existing one. All operator replicas write the same configuration on start,
+// so conflicting updates are retried.
+func (f *WebhookConfig) applyWebhookConfig(ctx context.Context, config webhookConfiguration, kind string) error {
+	err := f.client.Create(ctx, config)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(admissionregistration.SchemeGroupVersion.WithKind(kind))
+		err := f.client.Get(ctx, machinerytypes.NamespacedName{Name: config.GetName()}, existing)
+		if err != nil {
+			return err
+		}
+
+		config.SetResourceVersion(existing.GetResourceVersion())
+		return f.client.Update(ctx, config)
+	})
 }
 
 func (f *WebhookConfig) writeSecretFiles() error {
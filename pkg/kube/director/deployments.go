@@ -0,0 +1,363 @@
+package director
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/cf-operator/version"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// Error codes, as returned by the Director
+const (
+	codeDeploymentNotFound  = 70000
+	codeDeploymentInvalid   = 190014
+	codeInternalServerError = 100
+)
+
+// maxManifestSize limits the size of uploaded manifests, which have to fit
+// into a config map
+const maxManifestSize = 1024 * 1024
+
+type deploymentRelease struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type deploymentStemcell struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type deploymentResponse struct {
+	Name        string               `json:"name"`
+	CloudConfig string               `json:"cloud_config"`
+	Releases    []deploymentRelease  `json:"releases"`
+	Stemcells   []deploymentStemcell `json:"stemcells"`
+	Teams       []string             `json:"teams"`
+}
+
+type instanceResponse struct {
+	AgentID      string   `json:"agent_id"`
+	CID          string   `json:"cid"`
+	Job          string   `json:"job"`
+	Index        int      `json:"index"`
+	ID           string   `json:"id"`
+	AZ           string   `json:"az"`
+	IPs          []string `json:"ips"`
+	VMCreatedAt  string   `json:"vm_created_at"`
+	ExpectsVM    bool     `json:"expects_vm"`
+	Active       bool     `json:"active"`
+	Bootstrap    bool     `json:"bootstrap"`
+	ProcessState string   `json:"process_state"`
+}
+
+// info describes the emulated Director, the bosh CLI reads it before
+// every command
+func (s *Server) info(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authentication := map[string]interface{}{"type": "basic", "options": map[string]interface{}{}}
+	s.writeJSON(w, map[string]interface{}{
+		"name":                "cf-operator",
+		"uuid":                s.namespace,
+		"version":             version.Version,
+		"user":                nil,
+		"cpi":                 "kubernetes",
+		"stemcell_os":         "",
+		"stemcell_version":    "",
+		"user_authentication": authentication,
+		"features": map[string]interface{}{
+			"local_dns":        map[string]interface{}{"status": true},
+			"config_server":    map[string]interface{}{"status": true},
+			"snapshots":        map[string]interface{}{"status": false},
+			"dns":              map[string]interface{}{"status": false},
+			"compiled_package": map[string]interface{}{"status": false},
+		},
+	})
+}
+
+// deployments lists the BOSHDeployments on GET and creates or updates a
+// BOSHDeployment from the uploaded manifest on POST
+func (s *Server) deployments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listDeployments(w, r)
+	case http.MethodPost:
+		s.deploy(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) listDeployments(w http.ResponseWriter, r *http.Request) {
+	list, err := s.versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(s.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, codeInternalServerError, errors.Wrap(err, "failed to list BOSHDeployments"))
+		return
+	}
+
+	deployments := []deploymentResponse{}
+	for i := range list.Items {
+		bdpl := &list.Items[i]
+		deployment := deploymentResponse{
+			Name:        bdpl.Name,
+			CloudConfig: "none",
+			Releases:    []deploymentRelease{},
+			Stemcells:   []deploymentStemcell{},
+			Teams:       []string{},
+		}
+
+		// Deployments, whose manifest can't be read, are still listed
+		manifest, err := s.manifest(bdpl)
+		if err != nil {
+			ctxlog.Debugf(s.ctx, "Failed to read manifest of BOSHDeployment '%s': %v", bdpl.Name, err)
+		} else if manifest != nil {
+			for _, release := range manifest.Releases {
+				deployment.Releases = append(deployment.Releases, deploymentRelease{Name: release.Name, Version: release.Version})
+			}
+			for _, stemcell := range manifest.Stemcells {
+				name := stemcell.Name
+				if name == "" {
+					name = stemcell.OS
+				}
+				deployment.Stemcells = append(deployment.Stemcells, deploymentStemcell{Name: name, Version: stemcell.Version})
+			}
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	s.writeJSON(w, deployments)
+}
+
+// getDeployment returns the manifest as it was uploaded, ops files and
+// variables are not applied
+func (s *Server) getDeployment(w http.ResponseWriter, r *http.Request, name string) {
+	bdpl, ok := s.getBOSHDeployment(w, name)
+	if !ok {
+		return
+	}
+
+	raw, err := s.rawManifest(bdpl)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, codeInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, map[string]string{"manifest": string(raw)})
+}
+
+// instances lists the pods of the deployment as BOSH instances or VMs
+func (s *Server) instances(w http.ResponseWriter, r *http.Request, name string, vms bool) {
+	if _, ok := s.getBOSHDeployment(w, name); !ok {
+		return
+	}
+
+	instances, err := s.cli.Instances(name, "")
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, codeInternalServerError, err)
+		return
+	}
+
+	response := []instanceResponse{}
+	for _, instance := range instances {
+		processState := "failing"
+		if instance.Ready {
+			processState = "running"
+		}
+		ips := []string{}
+		if instance.IP != "" {
+			ips = append(ips, instance.IP)
+		}
+		response = append(response, instanceResponse{
+			AgentID:      instance.PodUID,
+			CID:          instance.Pod,
+			Job:          instance.InstanceGroup,
+			Index:        instance.Index,
+			ID:           instance.PodUID,
+			AZ:           instance.AZ,
+			IPs:          ips,
+			VMCreatedAt:  instance.Created.UTC().Format("2006-01-02T15:04:05Z"),
+			ExpectsVM:    true,
+			Active:       true,
+			Bootstrap:    instance.Bootstrap,
+			ProcessState: processState,
+		})
+	}
+
+	// /vms is the older endpoint, which has no process state for its entries
+	if vms {
+		for i := range response {
+			response[i].ProcessState = ""
+		}
+	}
+	s.writeJSON(w, response)
+}
+
+// diff is called by `bosh deploy` before uploading the manifest. The operator
+// doesn't compute diffs, so it's always empty.
+func (s *Server) diff(w http.ResponseWriter, r *http.Request, name string) {
+	s.writeJSON(w, map[string]interface{}{
+		"context": map[string]interface{}{},
+		"diff":    [][]string{},
+	})
+}
+
+// deploy creates or updates a BOSHDeployment. The manifest is stored in a
+// config map, the name of the deployment is read from the manifest.
+func (s *Server) deploy(w http.ResponseWriter, r *http.Request) {
+	raw, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxManifestSize))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, codeDeploymentInvalid, errors.Wrap(err, "failed to read manifest"))
+		return
+	}
+
+	meta := struct {
+		Name string `json:"name"`
+	}{}
+	err = yaml.Unmarshal(raw, &meta)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, codeDeploymentInvalid, errors.Wrap(err, "failed to parse manifest"))
+		return
+	}
+	if meta.Name == "" {
+		s.writeError(w, http.StatusBadRequest, codeDeploymentInvalid, errors.New("manifest must have a name"))
+		return
+	}
+
+	err = s.applyManifest(meta.Name, raw)
+
+	username, _, _ := r.BasicAuth()
+	task := s.tasks.add(username, meta.Name, "create deployment", err)
+	if err != nil {
+		ctxlog.Errorf(s.ctx, "Director API failed to deploy '%s': %v", meta.Name, err)
+	}
+
+	w.Header().Set("Location", "/tasks/"+strconv.Itoa(task.ID))
+	w.WriteHeader(http.StatusFound)
+}
+
+// applyManifest creates the BOSHDeployment and its manifest config map, or
+// updates the manifest referenced by an existing BOSHDeployment
+func (s *Server) applyManifest(name string, raw []byte) error {
+	client := s.versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(s.namespace)
+	bdpl, err := client.Get(name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get BOSHDeployment '%s'", name)
+	}
+
+	if apierrors.IsNotFound(err) {
+		configMapName := fmt.Sprintf("%s-manifest", name)
+		err = s.applyConfigMap(configMapName, raw)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Create(&bdv1.BOSHDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace},
+			Spec: bdv1.BOSHDeploymentSpec{
+				Manifest: bdv1.ResourceReference{Name: configMapName, Type: bdv1.ConfigMapReference},
+			},
+		})
+		return errors.Wrapf(err, "failed to create BOSHDeployment '%s'", name)
+	}
+
+	ref := bdpl.Spec.Manifest
+	switch ref.Type {
+	case bdv1.ConfigMapReference:
+		return s.applyConfigMap(ref.Name, raw)
+	case bdv1.SecretReference:
+		secret, err := s.clientSet.CoreV1().Secrets(s.namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get manifest secret '%s'", ref.Name)
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[bdv1.ManifestSpecName] = raw
+		_, err = s.clientSet.CoreV1().Secrets(s.namespace).Update(secret)
+		return errors.Wrapf(err, "failed to update manifest secret '%s'", ref.Name)
+	default:
+		return errors.Errorf("BOSHDeployment '%s' references its manifest by %s, which can't be updated", name, ref.Type)
+	}
+}
+
+func (s *Server) applyConfigMap(name string, raw []byte) error {
+	client := s.clientSet.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := client.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace},
+			Data:       map[string]string{bdv1.ManifestSpecName: string(raw)},
+		})
+		return errors.Wrapf(err, "failed to create manifest config map '%s'", name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get manifest config map '%s'", name)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[bdv1.ManifestSpecName] = string(raw)
+	_, err = client.Update(configMap)
+	return errors.Wrapf(err, "failed to update manifest config map '%s'", name)
+}
+
+// getBOSHDeployment writes the Director's error response, if the deployment
+// doesn't exist
+func (s *Server) getBOSHDeployment(w http.ResponseWriter, name string) (*bdv1.BOSHDeployment, bool) {
+	bdpl, err := s.versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(s.namespace).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		s.writeError(w, http.StatusNotFound, codeDeploymentNotFound, errors.Errorf("Deployment '%s' doesn't exist", name))
+		return nil, false
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, codeInternalServerError, errors.Wrapf(err, "failed to get BOSHDeployment '%s'", name))
+		return nil, false
+	}
+	return bdpl, true
+}
+
+// manifest loads the manifest referenced by the BOSHDeployment. It's nil for
+// manifests, which are referenced by URL.
+func (s *Server) manifest(bdpl *bdv1.BOSHDeployment) (*bdm.Manifest, error) {
+	raw, err := s.rawManifest(bdpl)
+	if err != nil || len(raw) == 0 {
+		return nil, err
+	}
+	return bdm.LoadYAML(raw)
+}
+
+// rawManifest reads the manifest referenced by the BOSHDeployment
+func (s *Server) rawManifest(bdpl *bdv1.BOSHDeployment) ([]byte, error) {
+	ref := bdpl.Spec.Manifest
+	switch ref.Type {
+	case bdv1.ConfigMapReference:
+		configMap, err := s.clientSet.CoreV1().ConfigMaps(s.namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get manifest config map '%s'", ref.Name)
+		}
+		return []byte(configMap.Data[bdv1.ManifestSpecName]), nil
+	case bdv1.SecretReference:
+		secret, err := s.clientSet.CoreV1().Secrets(s.namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get manifest secret '%s'", ref.Name)
+		}
+		return secret.Data[bdv1.ManifestSpecName], nil
+	default:
+		return nil, nil
+	}
+}
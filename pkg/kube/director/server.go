@@ -0,0 +1,203 @@
+// Package director serves a subset of the BOSH Director API, which maps onto
+// BOSHDeployment resources, so existing BOSH tooling can be used with the
+// operator.
+package director
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/client/clientset/versioned"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/quarkscli"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// Options configure the Director API server
+type Options struct {
+	// Address to listen on, e.g. ':25555'
+	Address string
+	// CertFile and KeyFile enable TLS, the bosh CLI requires it
+	CertFile string
+	KeyFile  string
+	// Username and Password for basic authentication, both are required
+	Username string
+	Password string
+}
+
+// Validate checks the options. Credentials are always required, TLS unless
+// the server only listens on the loopback interface.
+func (o Options) Validate() error {
+	if o.Username == "" || o.Password == "" {
+		return errors.New("the director API requires a username and password")
+	}
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return errors.New("the director API requires both a TLS certificate and key")
+	}
+	if o.CertFile == "" && !isLoopback(o.Address) {
+		return errors.Errorf("the director API requires TLS, unless it listens on localhost, address is '%s'", o.Address)
+	}
+	return nil
+}
+
+func isLoopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Server serves the Director API for the BOSHDeployments of a namespace
+type Server struct {
+	ctx                context.Context
+	namespace          string
+	options            Options
+	clientSet          kubernetes.Interface
+	versionedClientSet versioned.Interface
+	cli                *quarkscli.CLI
+	tasks              *tasks
+}
+
+// NewServer constructs a new Director API server
+func NewServer(ctx context.Context, namespace string, clientSet kubernetes.Interface, versionedClientSet versioned.Interface, options Options) *Server {
+	return &Server{
+		ctx:                ctx,
+		namespace:          namespace,
+		options:            options,
+		clientSet:          clientSet,
+		versionedClientSet: versionedClientSet,
+		cli:                quarkscli.NewCLI(namespace, clientSet, versionedClientSet, nil, nil),
+		tasks:              newTasks(),
+	}
+}
+
+// NewServerForConfig constructs a new Director API server, which uses
+// clients for the rest config
+func NewServerForConfig(ctx context.Context, namespace string, restConfig *rest.Config, options Options) (*Server, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube client for director API")
+	}
+	versionedClientSet, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create quarks client for director API")
+	}
+	return NewServer(ctx, namespace, clientSet, versionedClientSet, options), nil
+}
+
+// Start runs the server until stop is closed. It implements the Runnable
+// interface of the controller-runtime manager.
+func (s *Server) Start(stop <-chan struct{}) error {
+	srv := &http.Server{Addr: s.options.Address, Handler: s.Handler()}
+
+	errCh := make(chan error, 1)
+	go func() {
+		ctxlog.Infof(s.ctx, "Starting director API on %s", s.options.Address)
+		if s.options.CertFile != "" {
+			errCh <- srv.ListenAndServeTLS(s.options.CertFile, s.options.KeyFile)
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case <-stop:
+		return srv.Shutdown(context.Background())
+	case err := <-errCh:
+		return errors.Wrap(err, "director API failed")
+	}
+}
+
+// Handler returns the handler of the Director API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", s.info)
+	mux.Handle("/deployments", s.authenticated(http.HandlerFunc(s.deployments)))
+	mux.Handle("/deployments/", s.authenticated(http.HandlerFunc(s.deployment)))
+	mux.Handle("/tasks", s.authenticated(http.HandlerFunc(s.listTasks)))
+	mux.Handle("/tasks/", s.authenticated(http.HandlerFunc(s.task)))
+	return mux
+}
+
+// authenticated checks the basic auth credentials, like the Director does for
+// local users
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || !s.validCredentials(username, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="BOSH Director"`)
+			http.Error(w, "Not authorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validCredentials compares the credentials in constant time
+func (s *Server) validCredentials(username string, password string) bool {
+	if s.options.Password == "" {
+		return false
+	}
+	validUsername := subtle.ConstantTimeCompare([]byte(username), []byte(s.options.Username))
+	validPassword := subtle.ConstantTimeCompare([]byte(password), []byte(s.options.Password))
+	return validUsername&validPassword == 1
+}
+
+// deployment routes the requests below /deployments/:name
+func (s *Server) deployment(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/deployments/"), "/")
+	name := parts[0]
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.getDeployment(w, r, name)
+	case len(parts) == 2 && parts[1] == "instances" && r.Method == http.MethodGet:
+		s.instances(w, r, name, false)
+	case len(parts) == 2 && parts[1] == "vms" && r.Method == http.MethodGet:
+		s.instances(w, r, name, true)
+	case len(parts) == 2 && parts[1] == "diff" && r.Method == http.MethodPost:
+		s.diff(w, r, name)
+	case len(parts) <= 2:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		ctxlog.Errorf(s.ctx, "Failed to write director API response: %v", err)
+	}
+}
+
+// writeError writes an error like the Director does
+func (s *Server) writeError(w http.ResponseWriter, status int, code int, err error) {
+	ctxlog.Debugf(s.ctx, "Director API request failed: %v", err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":        code,
+		"description": err.Error(),
+	})
+}
@@ -0,0 +1,274 @@
+package director_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	versionedfake "code.cloudfoundry.org/cf-operator/pkg/kube/client/clientset/versioned/fake"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/director"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+	helper "code.cloudfoundry.org/quarks-utils/testing/testhelper"
+)
+
+var _ = Describe("Server", func() {
+	const (
+		namespace = "default"
+		manifest  = `---
+name: cf
+releases:
+- name: nats
+  version: "26"
+stemcells:
+- alias: default
+  os: opensuse-42.3
+  version: "36.g03b4653"
+instance_groups:
+- name: nats
+  instances: 1
+`
+	)
+
+	var (
+		server             *director.Server
+		options            director.Options
+		clientSet          *kubefake.Clientset
+		versionedClientSet *versionedfake.Clientset
+	)
+
+	request := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.SetBasicAuth("admin", "secret")
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		options = director.Options{Username: "admin", Password: "secret"}
+
+		clientSet = kubefake.NewSimpleClientset(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cf-manifest", Namespace: namespace},
+				Data:       map[string]string{bdv1.ManifestSpecName: manifest},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cf-nats-0",
+					Namespace: namespace,
+					UID:       types.UID("5fd8a5d5"),
+					Labels: map[string]string{
+						bdm.LabelDeploymentName:    "cf",
+						bdm.LabelInstanceGroupName: "nats",
+						qstsv1a1.LabelAZIndex:      "0",
+						qstsv1a1.LabelPodOrdinal:   "0",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP:      "10.0.0.5",
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				},
+			},
+		)
+		versionedClientSet = versionedfake.NewSimpleClientset(
+			&bdv1.BOSHDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "cf", Namespace: namespace},
+				Spec: bdv1.BOSHDeploymentSpec{
+					Manifest: bdv1.ResourceReference{Name: "cf-manifest", Type: bdv1.ConfigMapReference},
+				},
+			},
+			&qstsv1a1.QuarksStatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cf-nats",
+					Namespace: namespace,
+					Labels: map[string]string{
+						bdm.LabelDeploymentName:    "cf",
+						bdm.LabelInstanceGroupName: "nats",
+					},
+				},
+				Spec: qstsv1a1.QuarksStatefulSetSpec{
+					Template: appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(1)}},
+				},
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		_, log := helper.NewTestLogger()
+		ctx := ctxlog.NewParentContext(log)
+		server = director.NewServer(ctx, namespace, clientSet, versionedClientSet, options)
+	})
+
+	Describe("authentication", func() {
+		It("serves /info without credentials", func() {
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			info := map[string]interface{}{}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &info)).To(Succeed())
+			Expect(info["cpi"]).To(Equal("kubernetes"))
+		})
+
+		It("rejects requests with wrong credentials", func() {
+			req := httptest.NewRequest(http.MethodGet, "/deployments", nil)
+			req.SetBasicAuth("admin", "wrong")
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("rejects requests without credentials", func() {
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deployments", nil))
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		Context("when no password is set", func() {
+			BeforeEach(func() {
+				options.Password = ""
+			})
+
+			It("rejects all requests", func() {
+				req := httptest.NewRequest(http.MethodGet, "/deployments", nil)
+				req.SetBasicAuth("admin", "")
+				rec := httptest.NewRecorder()
+				server.Handler().ServeHTTP(rec, req)
+				Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("Options", func() {
+		It("requires credentials", func() {
+			err := director.Options{Address: "127.0.0.1:25555", Username: "admin"}.Validate()
+			Expect(err).To(MatchError("the director API requires a username and password"))
+		})
+
+		It("requires TLS, unless it listens on localhost", func() {
+			Expect(director.Options{Address: ":25555", Username: "admin", Password: "secret"}.Validate()).ToNot(Succeed())
+			Expect(director.Options{Address: "0.0.0.0:25555", Username: "admin", Password: "secret"}.Validate()).ToNot(Succeed())
+			Expect(director.Options{Address: "localhost:25555", Username: "admin", Password: "secret"}.Validate()).To(Succeed())
+			Expect(director.Options{Address: "[::1]:25555", Username: "admin", Password: "secret"}.Validate()).To(Succeed())
+			Expect(director.Options{Address: ":25555", Username: "admin", Password: "secret", CertFile: "tls.crt", KeyFile: "tls.key"}.Validate()).To(Succeed())
+		})
+
+		It("requires both, certificate and key", func() {
+			Expect(director.Options{Address: "localhost:25555", Username: "admin", Password: "secret", CertFile: "tls.crt"}.Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("GET /deployments", func() {
+		It("lists the deployments with their releases and stemcells", func() {
+			rec := request(http.MethodGet, "/deployments", nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`[{
+				"name": "cf",
+				"cloud_config": "none",
+				"releases": [{"name": "nats", "version": "26"}],
+				"stemcells": [{"name": "opensuse-42.3", "version": "36.g03b4653"}],
+				"teams": []
+			}]`))
+		})
+	})
+
+	Describe("GET /deployments/:name", func() {
+		It("returns the uploaded manifest", func() {
+			rec := request(http.MethodGet, "/deployments/cf", nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			response := map[string]string{}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+			Expect(response["manifest"]).To(Equal(manifest))
+		})
+
+		It("returns the director error for unknown deployments", func() {
+			rec := request(http.MethodGet, "/deployments/unknown", nil)
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(rec.Body.String()).To(MatchJSON(`{"code": 70000, "description": "Deployment 'unknown' doesn't exist"}`))
+		})
+	})
+
+	Describe("GET /deployments/:name/instances", func() {
+		It("lists the pods as instances", func() {
+			rec := request(http.MethodGet, "/deployments/cf/instances", nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			instances := []map[string]interface{}{}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &instances)).To(Succeed())
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0]["job"]).To(Equal("nats"))
+			Expect(instances[0]["id"]).To(Equal("5fd8a5d5"))
+			Expect(instances[0]["cid"]).To(Equal("cf-nats-0"))
+			Expect(instances[0]["ips"]).To(ConsistOf("10.0.0.5"))
+			Expect(instances[0]["bootstrap"]).To(BeTrue())
+			Expect(instances[0]["process_state"]).To(Equal("running"))
+		})
+	})
+
+	Describe("POST /deployments", func() {
+		It("updates the manifest of an existing deployment", func() {
+			updated := manifest + "update: {}\n"
+			rec := request(http.MethodPost, "/deployments", []byte(updated))
+			Expect(rec.Code).To(Equal(http.StatusFound))
+			Expect(rec.Header().Get("Location")).To(Equal("/tasks/1"))
+
+			configMap, err := clientSet.CoreV1().ConfigMaps(namespace).Get("cf-manifest", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Data[bdv1.ManifestSpecName]).To(Equal(updated))
+
+			rec = request(http.MethodGet, "/tasks/1", nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			task := director.Task{}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &task)).To(Succeed())
+			Expect(task.State).To(Equal("done"))
+			Expect(task.Deployment).To(Equal("cf"))
+		})
+
+		It("creates a BOSHDeployment for a new deployment", func() {
+			rec := request(http.MethodPost, "/deployments", []byte("name: redis\n"))
+			Expect(rec.Code).To(Equal(http.StatusFound))
+
+			bdpl, err := versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(namespace).Get("redis", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bdpl.Spec.Manifest).To(Equal(bdv1.ResourceReference{Name: "redis-manifest", Type: bdv1.ConfigMapReference}))
+
+			configMap, err := clientSet.CoreV1().ConfigMaps(namespace).Get("redis-manifest", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Data[bdv1.ManifestSpecName]).To(Equal("name: redis\n"))
+		})
+
+		It("records a failed task for manifests referenced by URL", func() {
+			bdpl, err := versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(namespace).Get("cf", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			bdpl.Spec.Manifest = bdv1.ResourceReference{Name: "https://example.com/cf.yml", Type: bdv1.URLReference}
+			_, err = versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(namespace).Update(bdpl)
+			Expect(err).ToNot(HaveOccurred())
+
+			rec := request(http.MethodPost, "/deployments", []byte(manifest))
+			Expect(rec.Code).To(Equal(http.StatusFound))
+
+			rec = request(http.MethodGet, "/tasks/1/output?type=result", nil)
+			Expect(rec.Body.String()).To(ContainSubstring("can't be updated"))
+		})
+
+		It("rejects manifests without a name", func() {
+			rec := request(http.MethodPost, "/deployments", []byte("releases: []\n"))
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
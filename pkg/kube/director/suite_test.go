@@ -0,0 +1,13 @@
+package director_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDirector(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Director Suite")
+}
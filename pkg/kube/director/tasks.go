@@ -0,0 +1,127 @@
+package director
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTasks is the number of tasks kept in memory
+const maxTasks = 100
+
+// Task is a Director task. Changes to BOSHDeployments are applied
+// asynchronously by the operator, so tasks are done as soon as the resources
+// are updated.
+type Task struct {
+	ID          int    `json:"id"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	Timestamp   int64  `json:"timestamp"`
+	StartedAt   int64  `json:"started_at"`
+	Result      string `json:"result"`
+	User        string `json:"user"`
+	Deployment  string `json:"deployment"`
+	ContextID   string `json:"context_id"`
+}
+
+// tasks keeps the most recent tasks in memory
+type tasks struct {
+	sync.Mutex
+	nextID int
+	tasks  map[int]Task
+}
+
+func newTasks() *tasks {
+	return &tasks{nextID: 1, tasks: map[int]Task{}}
+}
+
+// add records a finished task
+func (t *tasks) add(user, deployment, description string, err error) Task {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now().Unix()
+	task := Task{
+		ID:          t.nextID,
+		State:       "done",
+		Description: description,
+		Timestamp:   now,
+		StartedAt:   now,
+		Result:      "/deployments/" + deployment,
+		User:        user,
+		Deployment:  deployment,
+	}
+	if err != nil {
+		task.State = "error"
+		task.Result = err.Error()
+	}
+
+	t.tasks[task.ID] = task
+	delete(t.tasks, task.ID-maxTasks)
+	t.nextID++
+
+	return task
+}
+
+func (t *tasks) get(id int) (Task, bool) {
+	t.Lock()
+	defer t.Unlock()
+	task, ok := t.tasks[id]
+	return task, ok
+}
+
+// list returns the tasks, most recent first
+func (t *tasks) list(deployment string) []Task {
+	t.Lock()
+	defer t.Unlock()
+
+	list := []Task{}
+	for _, task := range t.tasks {
+		if deployment == "" || task.Deployment == deployment {
+			list = append(list, task)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID > list[j].ID })
+	return list
+}
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.writeJSON(w, s.tasks.list(r.URL.Query().Get("deployment")))
+}
+
+// task serves /tasks/:id and /tasks/:id/output. Tasks don't have any output
+// besides their result.
+func (s *Server) task(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/tasks/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts) > 2 || (len(parts) == 2 && parts[1] != "output") {
+		http.NotFound(w, r)
+		return
+	}
+
+	task, ok := s.tasks.get(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(parts) == 2 {
+		if r.URL.Query().Get("type") == "result" && task.State == "error" {
+			w.Write([]byte(task.Result + "\n"))
+		}
+		return
+	}
+	s.writeJSON(w, task)
+}
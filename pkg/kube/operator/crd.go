@@ -0,0 +1,281 @@
+package operator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"code.cloudfoundry.org/quarks-utils/pkg/pointers"
+)
+
+// crdScheme converts CRDs between the versions of the apiextensions API
+var crdScheme = runtime.NewScheme()
+
+func init() {
+	install.Install(crdScheme)
+}
+
+// newCRD returns the v1beta1 CRD of the resource. It's the version the
+// schemas are written for and which all supported clusters serve. Additional
+// versions are not served, until the conversion webhook is set up.
+func newCRD(res resource) *extv1beta1.CustomResourceDefinition {
+	crd := &extv1beta1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: extv1beta1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: res.name,
+		},
+		Spec: extv1beta1.CustomResourceDefinitionSpec{
+			Group: res.groupVersion.Group,
+			Versions: []extv1beta1.CustomResourceDefinitionVersion{
+				{
+					Name:    res.groupVersion.Version,
+					Served:  true,
+					Storage: true,
+				},
+			},
+			Validation:               res.validation,
+			AdditionalPrinterColumns: res.printerColumns,
+			PreserveUnknownFields:    pointers.Bool(false),
+			Subresources: &extv1beta1.CustomResourceSubresources{
+				Status: &extv1beta1.CustomResourceSubresourceStatus{},
+			},
+			Scope: extv1beta1.NamespaceScoped,
+			Names: extv1beta1.CustomResourceDefinitionNames{
+				Kind:       res.kind,
+				ListKind:   res.kind + "List",
+				Plural:     res.plural,
+				Singular:   strings.ToLower(res.kind),
+				ShortNames: res.shortNames,
+			},
+		},
+	}
+
+	// The schemas of the versions differ, so each version has its own
+	if len(res.versions) > 0 {
+		crd.Spec.Validation = nil
+		crd.Spec.Versions[0].Schema = res.validation
+		for _, v := range res.versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, extv1beta1.CustomResourceDefinitionVersion{
+				Name:   v.name,
+				Served: false,
+				Schema: v.validation,
+			})
+		}
+	}
+
+	return crd
+}
+
+// convertCRD returns the CRD for the apiextensions.k8s.io/v1 API. The schema,
+// printer columns and subresources are moved to the versions.
+func convertCRD(crd *extv1beta1.CustomResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+	internal := &apiextensions.CustomResourceDefinition{}
+	err := crdScheme.Convert(crd, internal, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting CRD '%s' to internal version", crd.Name)
+	}
+
+	out := &extv1.CustomResourceDefinition{}
+	err = crdScheme.Convert(internal, out, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting CRD '%s' to v1", crd.Name)
+	}
+	out.TypeMeta = metav1.TypeMeta{
+		APIVersion: extv1.SchemeGroupVersion.String(),
+		Kind:       "CustomResourceDefinition",
+	}
+
+	return out, nil
+}
+
+// CRDDocs returns the apiextensions.k8s.io/v1 CRDs of the operator as YAML,
+// keyed by their file name in docs/crds. The docs are generated from the same
+// schemas the operator applies, run `make gen-crd-docs` after changing them.
+func CRDDocs() (map[string][]byte, error) {
+	docs := map[string][]byte{}
+	for _, res := range resources() {
+		crd, err := convertCRD(newCRD(res))
+		if err != nil {
+			return nil, err
+		}
+		// The docs show the CRDs like the API server returns them
+		crdScheme.Default(crd)
+
+		doc, err := yaml.Marshal(crdDoc{
+			TypeMeta: crd.TypeMeta,
+			Metadata: crdDocMetadata{Name: crd.Name},
+			Spec:     crd.Spec,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshaling CRD '%s'", crd.Name)
+		}
+
+		name := fmt.Sprintf("%s_%s_%s_crd.yaml", strings.Split(res.groupVersion.Group, ".")[0], res.groupVersion.Version, crd.Spec.Names.Singular)
+		docs[name] = doc
+	}
+	return docs, nil
+}
+
+// crdDoc leaves out the status and the empty fields of the object meta
+type crdDoc struct {
+	metav1.TypeMeta `json:",inline"`
+	Metadata        crdDocMetadata                     `json:"metadata"`
+	Spec            extv1.CustomResourceDefinitionSpec `json:"spec"`
+}
+
+type crdDocMetadata struct {
+	Name string `json:"name"`
+}
+
+// applyCRD creates or updates the CRD. Defaults in the schema are only
+// allowed by the apiextensions.k8s.io/v1 API, so it's used if the cluster
+// serves it. Older clusters get the v1beta1 CRD without defaults.
+func applyCRD(client clientset.Interface, res resource) error {
+	crd := newCRD(res)
+
+	err := keepConversion(client, crd)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Discovery().ServerResourcesForGroupVersion(extv1.SchemeGroupVersion.String())
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "discovering '%s' API", extv1.SchemeGroupVersion)
+		}
+
+		crd.Spec.Validation = withoutDefaults(crd.Spec.Validation)
+		for i := range crd.Spec.Versions {
+			crd.Spec.Versions[i].Schema = withoutDefaults(crd.Spec.Versions[i].Schema)
+		}
+		return applyV1beta1CRD(client, crd)
+	}
+
+	v1CRD, err := convertCRD(crd)
+	if err != nil {
+		return err
+	}
+	return applyV1CRD(client, v1CRD)
+}
+
+// keepConversion keeps the conversion webhook, which was set up by a previous
+// start of the operator, so all versions stay served
+func keepConversion(client clientset.Interface, crd *extv1beta1.CustomResourceDefinition) error {
+	exCRD, err := client.ApiextensionsV1beta1().CustomResourceDefinitions().Get(crd.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "getting CRD '%s'", crd.Name)
+	}
+
+	conversion := exCRD.Spec.Conversion
+	if len(crd.Spec.Versions) < 2 || conversion == nil || conversion.Strategy != extv1beta1.WebhookConverter {
+		return nil
+	}
+
+	crd.Spec.Conversion = conversion.DeepCopy()
+	for i := range crd.Spec.Versions {
+		crd.Spec.Versions[i].Served = true
+	}
+
+	return nil
+}
+
+func applyV1CRD(client clientset.Interface, crd *extv1.CustomResourceDefinition) error {
+	crds := client.ApiextensionsV1().CustomResourceDefinitions()
+
+	exCRD, err := crds.Get(crd.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "getting CRD '%s'", crd.Name)
+		}
+		_, err := crds.Create(crd)
+		if err != nil {
+			return errors.Wrapf(err, "creating CRD '%s'", crd.Name)
+		}
+		return nil
+	}
+
+	if !reflect.DeepEqual(crd.Spec, exCRD.Spec) {
+		crd.ResourceVersion = exCRD.ResourceVersion
+		_, err = crds.Update(crd)
+		if err != nil {
+			return errors.Wrapf(err, "updating CRD '%s'", crd.Name)
+		}
+	}
+
+	return nil
+}
+
+func applyV1beta1CRD(client clientset.Interface, crd *extv1beta1.CustomResourceDefinition) error {
+	crds := client.ApiextensionsV1beta1().CustomResourceDefinitions()
+
+	exCRD, err := crds.Get(crd.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "getting CRD '%s'", crd.Name)
+		}
+		_, err := crds.Create(crd)
+		if err != nil {
+			return errors.Wrapf(err, "creating CRD '%s'", crd.Name)
+		}
+		return nil
+	}
+
+	if !reflect.DeepEqual(crd.Spec, exCRD.Spec) {
+		crd.ResourceVersion = exCRD.ResourceVersion
+		_, err = crds.Update(crd)
+		if err != nil {
+			return errors.Wrapf(err, "updating CRD '%s'", crd.Name)
+		}
+	}
+
+	return nil
+}
+
+// withoutDefaults returns a copy of the validation without the defaults
+func withoutDefaults(validation *extv1beta1.CustomResourceValidation) *extv1beta1.CustomResourceValidation {
+	if validation == nil || validation.OpenAPIV3Schema == nil {
+		return validation
+	}
+
+	v := validation.DeepCopy()
+	removeDefaults(v.OpenAPIV3Schema)
+	return v
+}
+
+func removeDefaults(s *extv1beta1.JSONSchemaProps) {
+	s.Default = nil
+
+	for k, p := range s.Properties {
+		removeDefaults(&p)
+		s.Properties[k] = p
+	}
+	if s.Items != nil {
+		if s.Items.Schema != nil {
+			removeDefaults(s.Items.Schema)
+		}
+		for i := range s.Items.JSONSchemas {
+			removeDefaults(&s.Items.JSONSchemas[i])
+		}
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		removeDefaults(s.AdditionalProperties.Schema)
+	}
+}
@@ -0,0 +1,28 @@
+package operator_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/operator"
+)
+
+var _ = Describe("CRDDocs", func() {
+	It("matches the CRDs in docs/crds", func() {
+		docs, err := operator.CRDDocs()
+		Expect(err).ToNot(HaveOccurred())
+
+		files, err := filepath.Glob("../../../docs/crds/*.yaml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(len(docs)))
+
+		for name, doc := range docs {
+			file, err := ioutil.ReadFile(filepath.Join("../../../docs/crds", name))
+			Expect(err).ToNot(HaveOccurred(), "run `make gen-crd-docs` to add '%s'", name)
+			Expect(string(file)).To(Equal(string(doc)), "run `make gen-crd-docs` to update '%s'", name)
+		}
+	})
+})
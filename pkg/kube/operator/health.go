@@ -0,0 +1,200 @@
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
+)
+
+// healthCheckTimeout is the timeout of a single check
+const healthCheckTimeout = 2 * time.Second
+
+// stuckReconcileFactor multiplied with the context timeout of the API
+// requests is the longest time a single reconcile may take
+const stuckReconcileFactor = 10
+
+// longestRunningProcessorMetric is the work queue gauge of controller-runtime,
+// which tracks how long the oldest running reconcile of a controller takes
+const longestRunningProcessorMetric = "workqueue_longest_running_processor_seconds"
+
+// requiredCRDs have to be established, before the operator is ready
+var requiredCRDs = []string{
+	bdv1.BOSHDeploymentResourceName,
+	qjv1a1.QuarksJobResourceName,
+	qsv1a1.QuarksSecretResourceName,
+	qstsv1a1.QuarksStatefulSetResourceName,
+}
+
+// addHealthChecks adds the liveness and readiness checks, which the manager
+// serves on its health probe address. The operator is alive as long as the
+// manager serves requests and no controller is stuck in a reconcile. It's
+// ready once the cache is synced, the webhook server accepts TLS connections
+// and the CRDs are established.
+func addHealthChecks(mgr manager.Manager, ctxTimeout time.Duration) error {
+	err := mgr.AddHealthzCheck("ping", healthz.Ping)
+	if err != nil {
+		return errors.Wrap(err, "failed to add ping check")
+	}
+
+	err = mgr.AddHealthzCheck("controller-progress", controllerProgressCheck(crmetrics.Registry, stuckReconcileFactor*ctxTimeout))
+	if err != nil {
+		return errors.Wrap(err, "failed to add controller progress check")
+	}
+
+	sync := &cacheSyncCheck{}
+	err = mgr.Add(sync)
+	if err != nil {
+		return errors.Wrap(err, "failed to add cache sync check to manager")
+	}
+
+	for name, check := range map[string]healthz.Checker{
+		"cache-sync":     sync.Check,
+		"webhook-server": webhookServerCheck(mgr.GetWebhookServer()),
+		"crds":           crdCheck(mgr.GetAPIReader(), requiredCRDs),
+	} {
+		err = mgr.AddReadyzCheck(name, check)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add %s check", name)
+		}
+	}
+
+	return nil
+}
+
+// cacheSyncCheck is started by the manager once its cache is synced. It
+// implements the Runnable interface of the controller-runtime manager.
+type cacheSyncCheck struct {
+	synced int32
+}
+
+// Start marks the cache as synced
+func (c *cacheSyncCheck) Start(_ <-chan struct{}) error {
+	atomic.StoreInt32(&c.synced, 1)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, all replicas
+// have to become ready
+func (c *cacheSyncCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// Check fails until the cache is synced
+func (c *cacheSyncCheck) Check(_ *http.Request) error {
+	if atomic.LoadInt32(&c.synced) == 0 {
+		return errors.New("cache is not synced yet")
+	}
+	return nil
+}
+
+// controllerProgressCheck fails if a reconcile runs longer than the timeout.
+// Reconciles only block on API requests, which time out, so a reconcile
+// which runs that long is stuck, e.g. in a deadlock, and its controller does
+// not process any more events. Idle controllers report zero.
+func controllerProgressCheck(gatherer prometheus.Gatherer, timeout time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return errors.Wrap(err, "gathering work queue metrics")
+		}
+
+		for _, family := range families {
+			if family.GetName() != longestRunningProcessorMetric {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				running := time.Duration(metric.GetGauge().GetValue() * float64(time.Second))
+				if running <= timeout {
+					continue
+				}
+
+				controller := ""
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "name" {
+						controller = label.GetValue()
+					}
+				}
+				return errors.Errorf("controller '%s' is stuck in a reconcile for %s", controller, running.Round(time.Second))
+			}
+		}
+		return nil
+	}
+}
+
+// webhookServerCheck fails until the webhook server accepts TLS connections.
+// Only the handshake is checked, the certificate is issued for the address
+// the cluster uses, not for localhost.
+func webhookServerCheck(server *webhook.Server) healthz.Checker {
+	return func(_ *http.Request) error {
+		host := server.Host
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "localhost"
+		}
+		address := net.JoinHostPort(host, strconv.Itoa(server.Port))
+
+		dialer := &net.Dialer{Timeout: healthCheckTimeout}
+		conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return errors.Wrapf(err, "webhook server on '%s' is not serving", address)
+		}
+		return conn.Close()
+	}
+}
+
+// crdCheck fails until all the CRDs are established
+func crdCheck(reader client.Reader, names []string) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), healthCheckTimeout)
+		defer cancel()
+
+		for _, name := range names {
+			crd := &unstructured.Unstructured{}
+			crd.SetGroupVersionKind(extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+			err := reader.Get(ctx, types.NamespacedName{Name: name}, crd)
+			if err != nil {
+				return errors.Wrapf(err, "getting CRD '%s'", name)
+			}
+
+			if !established(crd) {
+				return errors.Errorf("CRD '%s' is not established", name)
+			}
+		}
+		return nil
+	}
+}
+
+// established returns true if the CRD has the Established condition
+func established(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == string(extv1.Established) && condition["status"] == string(extv1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
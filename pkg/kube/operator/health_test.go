@@ -0,0 +1,47 @@
+package operator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+var _ = Describe("Health checks", func() {
+	Describe("controllerProgressCheck", func() {
+		var (
+			registry *prometheus.Registry
+			gauge    prometheus.Gauge
+			check    healthz.Checker
+		)
+
+		BeforeEach(func() {
+			registry = prometheus.NewRegistry()
+			gauge = prometheus.NewGauge(prometheus.GaugeOpts{
+				Name:        longestRunningProcessorMetric,
+				ConstLabels: prometheus.Labels{"name": "boshdeployment-controller"},
+			})
+			registry.MustRegister(gauge)
+
+			check = controllerProgressCheck(registry, time.Minute)
+		})
+
+		It("succeeds for idle controllers", func() {
+			Expect(check(nil)).To(Succeed())
+		})
+
+		It("succeeds while reconciles are shorter than the timeout", func() {
+			gauge.Set(59)
+			Expect(check(nil)).To(Succeed())
+		})
+
+		It("fails if a reconcile runs longer than the timeout", func() {
+			gauge.Set(90)
+			err := check(nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("controller 'boshdeployment-controller' is stuck in a reconcile for 1m30s"))
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package operator
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// LeaderElectionOptions configure the leader election between operator
+// replicas. Only the leader runs the controllers, the webhook server is
+// started on all replicas.
+type LeaderElectionOptions struct {
+	// Enabled turns on leader election
+	Enabled bool
+	// Namespace of the Lease, defaults to the operator namespace
+	Namespace string
+	// ID is the name of the Lease
+	ID string
+	// LeaseDuration is the duration non-leaders wait before they try to
+	// acquire an expired Lease
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration the leader retries to renew the Lease
+	// before it gives up leadership
+	RenewDeadline time.Duration
+	// RetryPeriod is the duration between tries to acquire or renew the Lease
+	RetryPeriod time.Duration
+}
+
+// leaderElector adds the controllers to the manager, once it acquired the
+// Lease. It implements the Runnable interface of the controller-runtime manager.
+type leaderElector struct {
+	ctx     context.Context
+	config  *config.Config
+	mgr     manager.Manager
+	lock    resourcelock.Interface
+	options LeaderElectionOptions
+}
+
+func newLeaderElector(ctx context.Context, config *config.Config, restConfig *rest.Config, mgr manager.Manager, options LeaderElectionOptions) (*leaderElector, error) {
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube client for leader election")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine leader election identity")
+	}
+
+	namespace := options.Namespace
+	if namespace == "" {
+		namespace = config.OperatorNamespace
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		namespace,
+		options.ID,
+		clientSet.CoreV1(),
+		clientSet.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      hostname + "_" + string(uuid.NewUUID()),
+			EventRecorder: mgr.GetEventRecorderFor(options.ID),
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create leader election lock")
+	}
+
+	return &leaderElector{
+		ctx:     ctx,
+		config:  config,
+		mgr:     mgr,
+		lock:    lock,
+		options: options,
+	}, nil
+}
+
+// Start campaigns for the Lease until stop is closed. Leadership can't be
+// handed back without stopping the controllers, so an error is returned when
+// the Lease is lost, which stops the manager.
+func (l *leaderElector) Start(stop <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	addErr := make(chan error, 1)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            l.lock,
+		LeaseDuration:   l.options.LeaseDuration,
+		RenewDeadline:   l.options.RenewDeadline,
+		RetryPeriod:     l.options.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            l.options.ID,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(_ context.Context) {
+				ctxlog.Infof(l.ctx, "Acquired leader election lease '%s', starting controllers", l.lock.Describe())
+				if err := controllers.AddToManager(l.ctx, l.config, l.mgr); err != nil {
+					addErr <- err
+					cancel()
+				}
+			},
+			OnStoppedLeading: func() {
+				ctxlog.Infof(l.ctx, "Stopped leading '%s'", l.lock.Describe())
+			},
+			OnNewLeader: func(identity string) {
+				ctxlog.Infof(l.ctx, "Leader of '%s' is '%s'", l.lock.Describe(), identity)
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create leader elector")
+	}
+
+	ctxlog.Infof(l.ctx, "Waiting for leader election lease '%s'", l.lock.Describe())
+	elector.Run(ctx)
+
+	select {
+	case err := <-addErr:
+		return errors.Wrap(err, "failed to add controllers to manager")
+	case <-stop:
+		return nil
+	default:
+		return errors.New("leader election lost")
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the
+// elector has to run on all replicas.
+func (l *leaderElector) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,284 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// newNamespacedCache returns a cache, which keeps namespaced objects in one
+// cache per namespace and cluster scoped objects, like Namespaces and
+// CertificateSigningRequests, in a cluster wide cache. The operator only
+// needs permissions for namespaced objects in these namespaces.
+func newNamespacedCache(config *rest.Config, opts cache.Options, namespaces []string) (cache.Cache, error) {
+	if opts.Mapper == nil {
+		mapper, err := apiutil.NewDiscoveryRESTMapper(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create REST mapper")
+		}
+		opts.Mapper = mapper
+	}
+	if opts.Scheme == nil {
+		return nil, errors.New("cache options have no scheme")
+	}
+
+	opts.Namespace = ""
+	cluster, err := cache.New(config, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	caches := map[string]cache.Cache{}
+	for _, namespace := range namespaces {
+		opts.Namespace = namespace
+		c, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		caches[namespace] = c
+	}
+
+	return &namespacedCache{
+		cluster:    cluster,
+		namespaces: caches,
+		mapper:     opts.Mapper,
+		scheme:     opts.Scheme,
+	}, nil
+}
+
+type namespacedCache struct {
+	cluster    cache.Cache
+	namespaces map[string]cache.Cache
+	mapper     meta.RESTMapper
+	scheme     *runtime.Scheme
+}
+
+// namespaced returns true if objects of the kind are namespaced
+func (c *namespacedCache) namespaced(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get REST mapping of '%s'", gvk)
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func (c *namespacedCache) namespacedObject(obj runtime.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return false, err
+	}
+	return c.namespaced(gvk)
+}
+
+// GetInformer implements cache.Informers
+func (c *namespacedCache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	namespaced, err := c.namespacedObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return c.cluster.GetInformer(obj)
+	}
+
+	informers := namespacedInformer{}
+	for namespace, nc := range c.namespaces {
+		informer, err := nc.GetInformer(obj)
+		if err != nil {
+			return nil, err
+		}
+		informers[namespace] = informer
+	}
+	return informers, nil
+}
+
+// GetInformerForKind implements cache.Informers
+func (c *namespacedCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	namespaced, err := c.namespaced(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return c.cluster.GetInformerForKind(gvk)
+	}
+
+	informers := namespacedInformer{}
+	for namespace, nc := range c.namespaces {
+		informer, err := nc.GetInformerForKind(gvk)
+		if err != nil {
+			return nil, err
+		}
+		informers[namespace] = informer
+	}
+	return informers, nil
+}
+
+// Start implements cache.Informers, it blocks until all caches stopped or
+// one of them failed
+func (c *namespacedCache) Start(stop <-chan struct{}) error {
+	errs := make(chan error, len(c.namespaces)+1)
+	for namespace, nc := range c.namespaces {
+		go func(namespace string, nc cache.Cache) {
+			errs <- errors.Wrapf(nc.Start(stop), "failed to start cache of namespace '%s'", namespace)
+		}(namespace, nc)
+	}
+	go func() {
+		errs <- errors.Wrap(c.cluster.Start(stop), "failed to start cluster cache")
+	}()
+
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForCacheSync implements cache.Informers
+func (c *namespacedCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	synced := c.cluster.WaitForCacheSync(stop)
+	for _, nc := range c.namespaces {
+		if !nc.WaitForCacheSync(stop) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+// IndexField implements client.FieldIndexer
+func (c *namespacedCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	namespaced, err := c.namespacedObject(obj)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.IndexField(obj, field, extractValue)
+	}
+
+	for _, nc := range c.namespaces {
+		if err := nc.IndexField(obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get implements client.Reader
+func (c *namespacedCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	namespaced, err := c.namespacedObject(obj)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.Get(ctx, key, obj)
+	}
+
+	nc, ok := c.namespaces[key.Namespace]
+	if !ok {
+		return errors.Errorf("unable to get '%s', namespace '%s' is not watched", key, key.Namespace)
+	}
+	return nc.Get(ctx, key, obj)
+}
+
+// List implements client.Reader
+func (c *namespacedCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	namespaced, err := c.namespaced(gvk)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.cluster.List(ctx, list, opts...)
+	}
+
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Namespace != corev1.NamespaceAll {
+		nc, ok := c.namespaces[listOpts.Namespace]
+		if !ok {
+			return errors.Errorf("unable to list, namespace '%s' is not watched", listOpts.Namespace)
+		}
+		return nc.List(ctx, list, opts...)
+	}
+
+	// List all namespaces, like the multi namespace cache of controller-runtime
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return err
+	}
+	var items []runtime.Object
+	var resourceVersion string
+	for _, nc := range c.namespaces {
+		namespaceList := list.DeepCopyObject()
+		err = nc.List(ctx, namespaceList, opts...)
+		if err != nil {
+			return err
+		}
+		namespaceItems, err := meta.ExtractList(namespaceList)
+		if err != nil {
+			return err
+		}
+		accessor, err := meta.ListAccessor(namespaceList)
+		if err != nil {
+			return errors.Errorf("object '%T' must be a list type", list)
+		}
+		items = append(items, namespaceItems...)
+		resourceVersion = accessor.GetResourceVersion()
+	}
+	listAccessor.SetResourceVersion(resourceVersion)
+
+	return meta.SetList(list, items)
+}
+
+// namespacedInformer combines the informers of a kind in all namespaces
+type namespacedInformer map[string]cache.Informer
+
+// AddEventHandler implements cache.Informer
+func (i namespacedInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	for _, informer := range i {
+		informer.AddEventHandler(handler)
+	}
+}
+
+// AddEventHandlerWithResyncPeriod implements cache.Informer
+func (i namespacedInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, informer := range i {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+// AddIndexers implements cache.Informer
+func (i namespacedInformer) AddIndexers(indexers toolscache.Indexers) error {
+	for _, informer := range i {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasSynced implements cache.Informer
+func (i namespacedInformer) HasSynced() bool {
+	for _, informer := range i {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package operator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("namespacedCache", func() {
+	var c cache.Cache
+
+	BeforeEach(func() {
+		mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+
+		var err error
+		c, err = newNamespacedCache(
+			&rest.Config{Host: "http://127.0.0.1:1"},
+			cache.Options{Scheme: scheme.Scheme, Mapper: mapper},
+			[]string{"one", "two"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("uses one informer per namespace for namespaced objects", func() {
+		informer, err := c.GetInformer(&corev1.Secret{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(informer).To(BeAssignableToTypeOf(namespacedInformer{}))
+		Expect(informer).To(HaveKey("one"))
+		Expect(informer).To(HaveKey("two"))
+		Expect(informer).To(HaveLen(2))
+	})
+
+	It("uses a cluster wide informer for cluster scoped objects", func() {
+		informer, err := c.GetInformer(&corev1.Namespace{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(informer).ToNot(BeAssignableToTypeOf(namespacedInformer{}))
+	})
+
+	It("doesn't read objects of other namespaces", func() {
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: "other", Name: "foo"}, &corev1.Secret{})
+		Expect(err).To(MatchError("unable to get 'other/foo', namespace 'other' is not watched"))
+
+		err = c.List(context.Background(), &corev1.SecretList{}, client.InNamespace("other"))
+		Expect(err).To(MatchError("unable to list, namespace 'other' is not watched"))
+	})
+})
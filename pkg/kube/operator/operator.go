@@ -6,15 +6,18 @@ import (
 	"github.com/pkg/errors"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
-	extv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	credsgen "code.cloudfoundry.org/cf-operator/pkg/credsgen/in_memory_generator"
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	bdv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1beta1"
 	qsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1alpha1"
+	qsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarkssecret/v1beta1"
 	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+	qstsv1beta1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1beta1"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/controllers"
 	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
@@ -23,16 +26,26 @@ import (
 )
 
 type resource struct {
-	name         string
-	kind         string
-	plural       string
-	shortNames   []string
-	groupVersion schema.GroupVersion
-	validation   *extv1.CustomResourceValidation
+	name           string
+	kind           string
+	plural         string
+	shortNames     []string
+	groupVersion   schema.GroupVersion
+	validation     *extv1.CustomResourceValidation
+	printerColumns []extv1.CustomResourceColumnDefinition
+	// versions are served in addition to groupVersion, which is the
+	// storage version. They are converted by the conversion webhook.
+	versions []resourceVersion
 }
 
-// NewManager adds schemes, controllers and starts the manager
-func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, options manager.Options) (manager.Manager, error) {
+type resourceVersion struct {
+	name       string
+	validation *extv1.CustomResourceValidation
+}
+
+// NewManager adds schemes, controllers and starts the manager. With leader
+// election, the controllers are only added once the Lease was acquired.
+func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, options manager.Options, leaderElection LeaderElectionOptions) (manager.Manager, error) {
 	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize new manager")
@@ -54,6 +67,35 @@ func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, op
 		return nil, errors.Wrap(err, "failed to setup hooks")
 	}
 
+	err = addHealthChecks(mgr, config.CtxTimeOut)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to setup health checks")
+	}
+
+	watcher, err := newConfigWatcher(ctx, config, cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to setup operator config")
+	}
+
+	err = mgr.Add(watcher)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add operator config to manager")
+	}
+
+	if leaderElection.Enabled {
+		elector, err := newLeaderElector(ctx, config, cfg, mgr, leaderElection)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to setup leader election")
+		}
+
+		err = mgr.Add(elector)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to add leader election to manager")
+		}
+
+		return mgr, nil
+	}
+
 	// Setup all Controllers
 	err = controllers.AddToManager(ctx, config, mgr)
 	if err != nil {
@@ -65,12 +107,28 @@ func NewManager(ctx context.Context, config *config.Config, cfg *rest.Config, op
 
 // ApplyCRDs applies a collection of CRDs into the cluster
 func ApplyCRDs(config *rest.Config) error {
-	exClient, err := extv1client.NewForConfig(config)
+	exClient, err := clientset.NewForConfig(config)
 	if err != nil {
 		return errors.Wrap(err, "Could not get kube client")
 	}
 
-	for _, res := range []resource{
+	for _, res := range resources() {
+		err = applyCRD(exClient, res)
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply CRD '%s'", res.name)
+		}
+		err = crd.WaitForCRDReady(exClient.ApiextensionsV1beta1(), res.name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to wait for CRD '%s' ready", res.name)
+		}
+	}
+
+	return nil
+}
+
+// resources returns the custom resources of the operator
+func resources() []resource {
+	return []resource{
 		{
 			bdv1.BOSHDeploymentResourceName,
 			bdv1.BOSHDeploymentResourceKind,
@@ -78,6 +136,8 @@ func ApplyCRDs(config *rest.Config) error {
 			bdv1.BOSHDeploymentResourceShortNames,
 			bdv1.SchemeGroupVersion,
 			&bdv1.BOSHDeploymentValidation,
+			bdv1.BOSHDeploymentAdditionalPrinterColumns,
+			[]resourceVersion{{bdv1beta1.SchemeGroupVersion.Version, &bdv1beta1.BOSHDeploymentValidation}},
 		},
 		{
 			qjv1a1.QuarksJobResourceName,
@@ -86,6 +146,8 @@ func ApplyCRDs(config *rest.Config) error {
 			qjv1a1.QuarksJobResourceShortNames,
 			qjv1a1.SchemeGroupVersion,
 			&qjv1a1.QuarksJobValidation,
+			nil,
+			nil,
 		},
 		{
 			qsv1a1.QuarksSecretResourceName,
@@ -94,6 +156,8 @@ func ApplyCRDs(config *rest.Config) error {
 			qsv1a1.QuarksSecretResourceShortNames,
 			qsv1a1.SchemeGroupVersion,
 			&qsv1a1.QuarksSecretValidation,
+			qsv1a1.QuarksSecretAdditionalPrinterColumns,
+			[]resourceVersion{{qsv1beta1.SchemeGroupVersion.Version, &qsv1beta1.QuarksSecretValidation}},
 		},
 		{
 			qstsv1a1.QuarksStatefulSetResourceName,
@@ -102,25 +166,8 @@ func ApplyCRDs(config *rest.Config) error {
 			qstsv1a1.QuarksStatefulSetResourceShortNames,
 			qstsv1a1.SchemeGroupVersion,
 			&qstsv1a1.QuarksStatefulSetValidation,
+			qstsv1a1.QuarksStatefulSetAdditionalPrinterColumns,
+			[]resourceVersion{{qstsv1beta1.SchemeGroupVersion.Version, &qstsv1beta1.QuarksStatefulSetValidation}},
 		},
-	} {
-		err = crd.ApplyCRD(
-			exClient,
-			res.name,
-			res.kind,
-			res.plural,
-			res.shortNames,
-			res.groupVersion,
-			res.validation,
-		)
-		if err != nil {
-			return errors.Wrapf(err, "failed to apply CRD '%s'", res.name)
-		}
-		err = crd.WaitForCRDReady(exClient, res.name)
-		if err != nil {
-			return errors.Wrapf(err, "failed to wait for CRD '%s' ready", res.name)
-		}
 	}
-
-	return nil
 }
@@ -0,0 +1,148 @@
+package operator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorimage"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// configWatcher applies the operator config ConfigMaps. It implements the
+// Runnable interface of the controller-runtime manager. The ConfigMaps are
+// watched by informers of their own, as the manager cache doesn't cover the
+// operator namespace.
+type configWatcher struct {
+	ctx               context.Context
+	operatorNamespace string
+	factories         []informers.SharedInformerFactory
+}
+
+func newConfigWatcher(ctx context.Context, config *config.Config, restConfig *rest.Config) (*configWatcher, error) {
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube client for operator config")
+	}
+
+	// With several watched namespaces the operator can list ConfigMaps in
+	// all namespaces, otherwise only in its own and the watched namespace.
+	namespaces := []string{metav1.NamespaceAll}
+	if !watchnamespaces.Enabled() {
+		namespaces = []string{config.OperatorNamespace, config.Namespace}
+	}
+
+	w := &configWatcher{
+		ctx:               ctx,
+		operatorNamespace: config.OperatorNamespace,
+	}
+	for _, namespace := range namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(clientSet, 0,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", operatorconfig.ConfigMapName).String()
+			}),
+		)
+		factory.Core().V1().ConfigMaps().Informer().AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    w.apply,
+			UpdateFunc: func(_, obj interface{}) { w.apply(obj) },
+			DeleteFunc: w.delete,
+		})
+		w.factories = append(w.factories, factory)
+	}
+
+	return w, nil
+}
+
+// Start runs the informers until stop is closed
+func (w *configWatcher) Start(stop <-chan struct{}) error {
+	for _, factory := range w.factories {
+		factory.Start(stop)
+		factory.WaitForCacheSync(stop)
+	}
+
+	<-stop
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the
+// config applies to all replicas.
+func (w *configWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *configWatcher) apply(obj interface{}) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+
+	if configMap.Namespace != w.operatorNamespace {
+		if err := operatorconfig.SetNamespace(configMap.Namespace, configMap.Data); err != nil {
+			ctxlog.Errorf(w.ctx, "Ignoring operator config '%s/%s': %s", configMap.Namespace, configMap.Name, err)
+			return
+		}
+		ctxlog.Infof(w.ctx, "Applied operator config '%s/%s'", configMap.Namespace, configMap.Name)
+		return
+	}
+
+	if err := operatorconfig.SetGlobal(configMap.Data); err != nil {
+		ctxlog.Errorf(w.ctx, "Ignoring operator config '%s/%s': %s", configMap.Namespace, configMap.Name, err)
+		return
+	}
+	w.applyGlobal()
+	ctxlog.Infof(w.ctx, "Applied operator config '%s/%s'", configMap.Namespace, configMap.Name)
+}
+
+func (w *configWatcher) delete(obj interface{}) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if configMap, ok = tombstone.Obj.(*corev1.ConfigMap); !ok {
+			return
+		}
+	}
+
+	if configMap.Namespace != w.operatorNamespace {
+		operatorconfig.DeleteNamespace(configMap.Namespace)
+	} else {
+		// Settings are validated, an empty map always succeeds
+		_ = operatorconfig.SetGlobal(map[string]string{})
+		w.applyGlobal()
+	}
+	ctxlog.Infof(w.ctx, "Removed operator config '%s/%s'", configMap.Namespace, configMap.Name)
+}
+
+// applyGlobal passes the settings to the packages, which don't read the
+// operator config on their own
+func (w *configWatcher) applyGlobal() {
+	err := operatorimage.SetupOperatorDockerImage(
+		operatorconfig.Get("", operatorconfig.KeyDockerImageOrg, ""),
+		operatorconfig.Get("", operatorconfig.KeyDockerImageRepository, ""),
+		operatorconfig.Get("", operatorconfig.KeyDockerImageTag, ""),
+		corev1.PullPolicy(operatorconfig.Get("", operatorconfig.KeyDockerImagePullPolicy, "")),
+	)
+	if err != nil {
+		ctxlog.Errorf(w.ctx, "Failed to apply operator docker image of operator config: %s", err)
+	}
+
+	err = ratelimit.SetOverrides(operatorconfig.Get("", operatorconfig.KeyRateLimits, ""))
+	if err != nil {
+		ctxlog.Errorf(w.ctx, "Failed to apply rate limits of operator config: %s", err)
+	}
+}
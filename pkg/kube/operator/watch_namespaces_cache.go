@@ -0,0 +1,234 @@
+package operator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+)
+
+// NewWatchNamespacesCache returns a cache.NewCacheFunc for watching several
+// namespaces. Its informers only pass events of objects in namespaces with
+// the watch label of the operator to the controllers. Once a namespace gets
+// the label, or is added to the namespace informer, the objects in it are
+// passed to the controllers as new objects.
+//
+// With a list of namespaces, namespaced objects are only cached for these
+// namespaces, so the operator doesn't need cluster wide permissions for
+// them. Otherwise, e.g. for a namespace selector, the cache is cluster wide.
+// The multi namespace cache of controller-runtime isn't used for lists of
+// namespaces, since it can't get cluster scoped objects, like Namespaces and
+// CertificateSigningRequests.
+func NewWatchNamespacesCache(operatorNamespace string, namespaces []string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		var c cache.Cache
+		var err error
+		if len(namespaces) > 0 {
+			c, err = newNamespacedCache(config, opts, namespaces)
+		} else {
+			opts.Namespace = ""
+			c, err = cache.New(config, opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &watchNamespacesCache{
+			Cache:             c,
+			operatorNamespace: operatorNamespace,
+		}, nil
+	}
+}
+
+type watchNamespacesCache struct {
+	cache.Cache
+	operatorNamespace string
+
+	mutex      sync.Mutex
+	namespaces toolscache.SharedIndexInformer
+	handlers   []informerHandler
+}
+
+// informerHandler is an event handler registered by a controller
+type informerHandler struct {
+	informer toolscache.SharedIndexInformer
+	handler  toolscache.ResourceEventHandler
+}
+
+// GetInformer implements cache.Informers
+func (c *watchNamespacesCache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	i, err := c.Cache.GetInformer(obj)
+	if err != nil {
+		return nil, err
+	}
+	return c.filter(i)
+}
+
+// GetInformerForKind implements cache.Informers
+func (c *watchNamespacesCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	i, err := c.Cache.GetInformerForKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	return c.filter(i)
+}
+
+func (c *watchNamespacesCache) filter(i cache.Informer) (cache.Informer, error) {
+	if informers, ok := i.(namespacedInformer); ok {
+		filtered := namespacedInformer{}
+		for namespace, informer := range informers {
+			f, err := c.filter(informer)
+			if err != nil {
+				return nil, err
+			}
+			filtered[namespace] = f
+		}
+		return filtered, nil
+	}
+
+	informer, ok := i.(toolscache.SharedIndexInformer)
+	if !ok {
+		return nil, errors.Errorf("unexpected informer type %T", i)
+	}
+
+	err := c.watchNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	return &filteredInformer{SharedIndexInformer: informer, cache: c}, nil
+}
+
+// watchNamespaces sets up the namespace informer, which is used to look up
+// the labels of namespaces
+func (c *watchNamespacesCache) watchNamespaces() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.namespaces != nil {
+		return nil
+	}
+
+	i, err := c.Cache.GetInformer(&corev1.Namespace{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get namespace informer")
+	}
+	informer, ok := i.(toolscache.SharedIndexInformer)
+	if !ok {
+		return errors.Errorf("unexpected informer type %T", i)
+	}
+
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		// Events of objects, which arrive before their namespace, are
+		// dropped by the filter, e.g. while the informers start.
+		AddFunc: func(obj interface{}) {
+			ns, err := meta.Accessor(obj)
+			if err != nil {
+				return
+			}
+			if watchnamespaces.IsWatched(ns, c.operatorNamespace) {
+				c.replay(ns.GetName())
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, err := meta.Accessor(oldObj)
+			if err != nil {
+				return
+			}
+			newNs, err := meta.Accessor(newObj)
+			if err != nil {
+				return
+			}
+			if !watchnamespaces.IsWatched(oldNs, c.operatorNamespace) && watchnamespaces.IsWatched(newNs, c.operatorNamespace) {
+				c.replay(newNs.GetName())
+			}
+		},
+	})
+	c.namespaces = informer
+
+	return nil
+}
+
+// watched returns true for cluster scoped objects and objects in namespaces
+// with the watch label
+func (c *watchNamespacesCache) watched(obj interface{}) bool {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	if o.GetNamespace() == "" {
+		return true
+	}
+
+	item, exists, err := c.namespaces.GetStore().GetByKey(o.GetNamespace())
+	if err != nil || !exists {
+		return false
+	}
+	ns, err := meta.Accessor(item)
+	if err != nil {
+		return false
+	}
+
+	return watchnamespaces.IsWatched(ns, c.operatorNamespace)
+}
+
+// replay passes all objects of a newly watched namespace to the controllers
+func (c *watchNamespacesCache) replay(namespace string) {
+	c.mutex.Lock()
+	handlers := make([]informerHandler, len(c.handlers))
+	copy(handlers, c.handlers)
+	c.mutex.Unlock()
+
+	for _, h := range handlers {
+		objs, err := h.informer.GetIndexer().ByIndex(toolscache.NamespaceIndex, namespace)
+		if err != nil {
+			continue
+		}
+		for _, obj := range objs {
+			h.handler.OnAdd(obj)
+		}
+	}
+}
+
+func (c *watchNamespacesCache) register(informer toolscache.SharedIndexInformer, handler toolscache.ResourceEventHandler) toolscache.ResourceEventHandler {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.handlers = append(c.handlers, informerHandler{informer: informer, handler: handler})
+
+	return toolscache.FilteringResourceEventHandler{
+		FilterFunc: c.watched,
+		Handler:    handler,
+	}
+}
+
+// filteredInformer only passes events of objects in watched namespaces to
+// its event handlers
+type filteredInformer struct {
+	toolscache.SharedIndexInformer
+	cache *watchNamespacesCache
+}
+
+// AddEventHandler implements cache.Informer
+func (i *filteredInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.SharedIndexInformer.AddEventHandler(i.cache.register(i.SharedIndexInformer, handler))
+}
+
+// AddEventHandlerWithResyncPeriod implements cache.Informer
+func (i *filteredInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.SharedIndexInformer.AddEventHandlerWithResyncPeriod(i.cache.register(i.SharedIndexInformer, handler), resyncPeriod)
+}
@@ -20,6 +20,7 @@ import (
 	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util"
 	"code.cloudfoundry.org/cf-operator/pkg/kube/util/mutate"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
 )
 
 const (
@@ -41,9 +42,10 @@ func SetClusterDomain(domain string) {
 	clusterDomain = domain
 }
 
-// GetClusterDomain returns the package scoped clusterDomain variable.
+// GetClusterDomain returns the cluster domain of the operator config, it
+// defaults to the package scoped clusterDomain variable.
 func GetClusterDomain() string {
-	return clusterDomain
+	return operatorconfig.Get("", operatorconfig.KeyClusterDomain, clusterDomain)
 }
 
 // DomainNameService abstraction.
@@ -130,12 +132,13 @@ func (dns *boshDomainNameService) DNSSetting(namespace string) (corev1.DNSPolicy
 		return corev1.DNSNone, nil, errors.New("BoshDomainNameService: DNSSetting called before Reconcile")
 	}
 	ndots := "5"
+	domain := GetClusterDomain()
 	return corev1.DNSNone, &corev1.PodDNSConfig{
 		Nameservers: []string{dns.LocalDNSIP},
 		Searches: []string{
-			fmt.Sprintf("%s.svc.%s", namespace, clusterDomain),
-			fmt.Sprintf("svc.%s", clusterDomain),
-			clusterDomain,
+			fmt.Sprintf("%s.svc.%s", namespace, domain),
+			fmt.Sprintf("svc.%s", domain),
+			domain,
 			cfDomain,
 		},
 		Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}},
@@ -199,7 +202,7 @@ func (dns *boshDomainNameService) Reconcile(ctx context.Context, namespace strin
 						{
 							Name:  "coredns",
 							Args:  []string{"-conf", "/etc/coredns/Corefile"},
-							Image: boshDNSDockerImage,
+							Image: operatorconfig.Get(namespace, operatorconfig.KeyBoshDNSDockerImage, boshDNSDockerImage),
 							Ports: []corev1.ContainerPort{dnsUDPPort, dnsTCPPort, metricsPort},
 							VolumeMounts: []corev1.VolumeMount{
 								{MountPath: "/etc/coredns", Name: volumeName, ReadOnly: true},
@@ -324,7 +327,7 @@ func (dns *boshDomainNameService) gatherAllRewrites(rewrites []string,
 		}
 	} else {
 		from := alias.Domain
-		to := fmt.Sprintf("%s.%s.svc.%s", dns.HeadlessServiceName(target.InstanceGroup), namespace, clusterDomain)
+		to := fmt.Sprintf("%s.%s.svc.%s", dns.HeadlessServiceName(target.InstanceGroup), namespace, GetClusterDomain())
 		rewrites = append(rewrites, dnsTemplate(from, to, target.Query))
 	}
 
@@ -341,7 +344,7 @@ func (dns *boshDomainNameService) gatherRewritesForInstances(rewrites []string,
 		id := fmt.Sprintf("%s-%d", target.InstanceGroup, i)
 		from := strings.Replace(alias.Domain, "_", id, 1)
 		serviceName := instanceGroup.IndexedServiceName(dns.ManifestName, i, azIndex)
-		to := fmt.Sprintf("%s.%s.svc.%s", serviceName, namespace, clusterDomain)
+		to := fmt.Sprintf("%s.%s.svc.%s", serviceName, namespace, GetClusterDomain())
 		rewrites = append(rewrites, dnsTemplate(from, to, target.Query))
 	}
 
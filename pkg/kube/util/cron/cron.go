@@ -0,0 +1,203 @@
+// Package cron parses the standard five field cron format, which is used to
+// schedule errands.
+//
+// The grammar is kept minimal: fields are numbers, '*', ranges 'a-b', steps
+// '*/n', 'a-b/n' or 'a/n', and comma separated lists of those. Day of week is
+// 0-6 with Sunday as 0 or 7. The macros @yearly, @annually, @monthly,
+// @weekly, @daily, @midnight and @hourly are supported.
+//
+// Not supported are month and weekday names like 'JAN' or 'MON', the Quartz
+// extensions '?', 'L', 'W' and '#', a seconds or year field, time zone
+// prefixes like 'CRON_TZ=' and the macros @every and @reboot. Schedules using
+// them are rejected.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// field is the range of a cron field
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxYears limits the search for the next activation, schedules like
+// '0 0 30 2 *' never match
+const maxYears = 5
+
+// Schedule is a parsed cron schedule
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domRestricted and dowRestricted are set, if the fields are not '*'.
+	// Like in Vixie cron, a day matches either of them, if both are
+	// restricted.
+	domRestricted, dowRestricted bool
+}
+
+// Parse parses a schedule in the standard cron format, e.g. '*/15 2 * * 1-5',
+// or one of the macros, e.g. '@daily'
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := macros[spec]; ok {
+		spec = macro
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		return nil, errors.Errorf("invalid cron schedule '%s': unsupported macro", spec)
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, errors.Errorf("invalid cron schedule '%s': expected %d fields, got %d", spec, len(fields), len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		var err error
+		bits[i], err = parseField(part, fields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron schedule '%s'", spec)
+		}
+	}
+
+	return &Schedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dom:           bits[2],
+		month:         bits[3],
+		dow:           bits[4],
+		domRestricted: parts[2] != "*",
+		dowRestricted: parts[4] != "*",
+	}, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i != -1 {
+			var err error
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return 0, errors.Errorf("invalid step '%s' in %s field", item[i+1:], f.name)
+			}
+			item = item[:i]
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case item == "*":
+		case strings.Contains(item, "-"):
+			bounds := strings.SplitN(item, "-", 2)
+			var err error
+			low, err = parseValue(bounds[0], f)
+			if err != nil {
+				return 0, err
+			}
+			high, err = parseValue(bounds[1], f)
+			if err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, errors.Errorf("invalid range '%s' in %s field", item, f.name)
+			}
+		default:
+			var err error
+			low, err = parseValue(item, f)
+			if err != nil {
+				return 0, err
+			}
+			// 'n/step' means from n to the maximum
+			if step == 1 {
+				high = low
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value '%s' in %s field, only numbers are supported", value, f.name)
+	}
+	// Sunday can be 0 or 7
+	if f.name == "day of week" && v == 7 {
+		v = 0
+	}
+	if v < f.min || v > f.max {
+		return 0, errors.Errorf("value '%d' out of range [%d, %d] in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+// Next returns the first activation of the schedule after t, in t's
+// location. It returns the zero time, if the schedule never activates.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			// Not t.Truncate(time.Hour), which rounds in UTC and is off
+			// in locations with offsets of half hours
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
@@ -0,0 +1,70 @@
+package cron_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/cron"
+)
+
+var _ = Describe("Schedule", func() {
+	// A Wednesday
+	start := time.Date(2020, time.March, 18, 10, 17, 42, 0, time.UTC)
+
+	next := func(spec string, t time.Time) time.Time {
+		schedule, err := cron.Parse(spec)
+		Expect(err).ToNot(HaveOccurred())
+		return schedule.Next(t)
+	}
+
+	DescribeTable("Next",
+		func(spec string, expected time.Time) {
+			Expect(next(spec, start)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2020, time.March, 18, 10, 18, 0, 0, time.UTC)),
+		Entry("steps", "*/15 * * * *", time.Date(2020, time.March, 18, 10, 30, 0, 0, time.UTC)),
+		Entry("ranges with steps", "10-40/20 * * * *", time.Date(2020, time.March, 18, 10, 30, 0, 0, time.UTC)),
+		Entry("lists", "5,50 9,11 * * *", time.Date(2020, time.March, 18, 11, 5, 0, 0, time.UTC)),
+		Entry("daily macro", "@daily", time.Date(2020, time.March, 19, 0, 0, 0, 0, time.UTC)),
+		Entry("next month", "0 3 1 * *", time.Date(2020, time.April, 1, 3, 0, 0, 0, time.UTC)),
+		Entry("next year", "0 0 1 1 *", time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("weekdays", "0 2 * * 1-5", time.Date(2020, time.March, 19, 2, 0, 0, 0, time.UTC)),
+		Entry("sunday as 7", "0 0 * * 7", time.Date(2020, time.March, 22, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 20 * 5", time.Date(2020, time.March, 20, 0, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("never", "0 0 30 2 *", time.Time{}),
+	)
+
+	It("returns an activation strictly after the given time", func() {
+		t := time.Date(2020, time.March, 18, 10, 30, 0, 0, time.UTC)
+		Expect(next("*/15 * * * *", t)).To(Equal(time.Date(2020, time.March, 18, 10, 45, 0, 0, time.UTC)))
+	})
+
+	It("steps hours in locations with offsets of half hours", func() {
+		india := time.FixedZone("IST", 5*60*60+30*60)
+		t := time.Date(2020, time.March, 18, 10, 17, 0, 0, india)
+		Expect(next("0 12 * * *", t)).To(Equal(time.Date(2020, time.March, 18, 12, 0, 0, 0, india)))
+	})
+
+	DescribeTable("Parse errors",
+		func(spec string, msg string) {
+			_, err := cron.Parse(spec)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(msg))
+		},
+		Entry("missing fields", "* * *", "expected 5 fields, got 3"),
+		Entry("out of range", "60 * * * *", "out of range"),
+		Entry("invalid value", "a * * * *", "invalid value 'a'"),
+		Entry("invalid step", "*/0 * * * *", "invalid step"),
+		Entry("inverted range", "* 5-2 * * *", "invalid range"),
+		Entry("names", "0 0 * * MON", "invalid value 'MON' in day of week field, only numbers are supported"),
+		Entry("quartz extensions", "0 0 ? * 1", "invalid value '?' in day of month field, only numbers are supported"),
+		Entry("last day", "0 0 L * *", "invalid value 'L' in day of month field, only numbers are supported"),
+		Entry("nth weekday", "0 0 * * 1#2", "invalid value '1#2' in day of week field, only numbers are supported"),
+		Entry("seconds field", "0 0 0 * * *", "expected 5 fields, got 6"),
+		Entry("every macro", "@every 1h", "unsupported macro"),
+	)
+})
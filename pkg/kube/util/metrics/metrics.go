@@ -0,0 +1,140 @@
+// Package metrics defines the Prometheus metrics of the operator. They are
+// registered with the controller-runtime registry, so the manager serves them
+// next to its reconcile and work queue metrics.
+package metrics
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "cf_operator"
+
+// Phases of a BOSHDeployment, as reported by BOSHDeploymentPhase
+const (
+	// PhaseWaiting means the deployment waits for its dependencies or link providers
+	PhaseWaiting = "Waiting"
+	// PhaseRendering means the QuarksJobs which render the manifest were created
+	PhaseRendering = "Rendering"
+	// PhaseDeploying means the instance groups were applied
+	PhaseDeploying = "Deploying"
+	// PhaseFailed means the last reconcile failed
+	PhaseFailed = "Failed"
+)
+
+var phases = []string{PhaseWaiting, PhaseRendering, PhaseDeploying, PhaseFailed}
+
+var (
+	// RateLimitedRequeues counts reconciles, which were requeued because the
+	// previous reconcile of the resource failed recently
+	RateLimitedRequeues = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requeues_total",
+		Help:      "Number of reconciles requeued because of the backoff after a failed reconcile",
+	}, []string{"controller"})
+
+	// BOSHDeploymentPhase is 1 for the current phase of a BOSHDeployment and 0
+	// for all other phases
+	BOSHDeploymentPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "boshdeployment_phase",
+		Help:      "Current phase of a BOSHDeployment",
+	}, []string{"namespace", "name", "phase"})
+
+	// QuarksSecretGenerationFailures counts failed secret generations
+	QuarksSecretGenerationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quarkssecret_generation_failures_total",
+		Help:      "Number of failed QuarksSecret generations",
+	}, []string{"namespace", "type"})
+
+	// CertificateExpiry is the expiry timestamp of generated certificates
+	CertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "Expiry of certificates generated for QuarksSecrets, as Unix timestamp",
+	}, []string{"namespace", "secret"})
+
+	// StatefulSetRolloutTransitions counts the state transitions of canary
+	// rollouts
+	StatefulSetRolloutTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "statefulset_rollout_transitions_total",
+		Help:      "Number of StatefulSet rollout state transitions",
+	}, []string{"from", "to"})
+
+	// StatefulSetRolloutTimeouts counts canary rollouts, which failed because
+	// the canary or update watch time passed
+	StatefulSetRolloutTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "statefulset_rollout_timeouts_total",
+		Help:      "Number of StatefulSet rollouts failed because the watch time passed",
+	}, []string{"namespace", "watch_time"})
+
+	// ActivePassiveProbes counts the results of active/passive probes
+	ActivePassiveProbes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "active_passive_probes_total",
+		Help:      "Number of active/passive probes executed in pods of QuarksStatefulSets",
+	}, []string{"namespace", "quarks_statefulset", "result"})
+
+	// QuarksLinkRestarts counts restarts of workloads, triggered by changed
+	// link secrets
+	QuarksLinkRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quarkslink_restarts_total",
+		Help:      "Number of workloads restarted because of changed quarks-link secrets",
+	}, []string{"namespace", "kind"})
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		RateLimitedRequeues,
+		BOSHDeploymentPhase,
+		QuarksSecretGenerationFailures,
+		CertificateExpiry,
+		StatefulSetRolloutTransitions,
+		StatefulSetRolloutTimeouts,
+		ActivePassiveProbes,
+		QuarksLinkRestarts,
+	)
+}
+
+// SetBOSHDeploymentPhase sets the current phase of a BOSHDeployment
+func SetBOSHDeploymentPhase(namespace string, name string, phase string) {
+	for _, p := range phases {
+		value := 0.0
+		if p == phase {
+			value = 1
+		}
+		BOSHDeploymentPhase.WithLabelValues(namespace, name, p).Set(value)
+	}
+}
+
+// DeleteBOSHDeploymentPhase removes the phase series of a deleted BOSHDeployment
+func DeleteBOSHDeploymentPhase(namespace string, name string) {
+	for _, p := range phases {
+		BOSHDeploymentPhase.DeleteLabelValues(namespace, name, p)
+	}
+}
+
+// SetCertificateExpiry records the expiry of the PEM encoded certificate
+func SetCertificateExpiry(namespace string, secretName string, certificate []byte) error {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return errors.Errorf("failed to decode certificate of secret '%s/%s'", namespace, secretName)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse certificate of secret '%s/%s'", namespace, secretName)
+	}
+
+	CertificateExpiry.WithLabelValues(namespace, secretName).Set(float64(cert.NotAfter.Unix()))
+	return nil
+}
@@ -0,0 +1,57 @@
+package metrics_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	Describe("SetBOSHDeploymentPhase", func() {
+		It("sets only the current phase", func() {
+			metrics.SetBOSHDeploymentPhase("default", "foo", metrics.PhaseWaiting)
+			metrics.SetBOSHDeploymentPhase("default", "foo", metrics.PhaseRendering)
+
+			Expect(testutil.ToFloat64(metrics.BOSHDeploymentPhase.WithLabelValues("default", "foo", metrics.PhaseRendering))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(metrics.BOSHDeploymentPhase.WithLabelValues("default", "foo", metrics.PhaseWaiting))).To(Equal(0.0))
+			Expect(testutil.ToFloat64(metrics.BOSHDeploymentPhase.WithLabelValues("default", "foo", metrics.PhaseFailed))).To(Equal(0.0))
+		})
+	})
+
+	Describe("SetCertificateExpiry", func() {
+		It("records the expiry of the certificate", func() {
+			notAfter := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "foo"},
+				NotBefore:    time.Now(),
+				NotAfter:     notAfter,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+			Expect(err).ToNot(HaveOccurred())
+			cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+			Expect(metrics.SetCertificateExpiry("default", "foo-cert", cert)).To(Succeed())
+			Expect(testutil.ToFloat64(metrics.CertificateExpiry.WithLabelValues("default", "foo-cert"))).To(Equal(float64(notAfter.Unix())))
+		})
+
+		It("fails for invalid certificates", func() {
+			err := metrics.SetCertificateExpiry("default", "foo-cert", []byte("the-cert"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to decode certificate of secret 'default/foo-cert'"))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+// Package operatorconfig holds the settings of the operator, which can be
+// changed at runtime. They are read from ConfigMaps named ConfigMapName: the
+// one in the operator namespace replaces the flags of the operator, the ones
+// in watched namespaces override some settings for their namespace.
+//
+// Settings which are missing from the ConfigMaps fall back to the defaults,
+// which are set from the flags.
+package operatorconfig
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConfigMapName is the name of the ConfigMaps holding the operator settings
+const ConfigMapName = "cf-operator-config"
+
+const (
+	// KeyCtxTimeOut is the timeout of a reconcile, e.g. '30s'
+	KeyCtxTimeOut = "ctx-timeout"
+	// KeyClusterDomain is the kubernetes cluster domain
+	KeyClusterDomain = "cluster-domain"
+	// KeyBoshDNSDockerImage is the docker image of the BOSH DNS pods
+	KeyBoshDNSDockerImage = "bosh-dns-docker-image"
+	// KeyDockerImageOrg is the docker organization of the operator image
+	KeyDockerImageOrg = "docker-image-org"
+	// KeyDockerImageRepository is the docker repository of the operator image
+	KeyDockerImageRepository = "docker-image-repository"
+	// KeyDockerImageTag is the tag of the operator image
+	KeyDockerImageTag = "docker-image-tag"
+	// KeyDockerImagePullPolicy is the pull policy of the operator image
+	KeyDockerImagePullPolicy = "docker-image-pull-policy"
+	// KeyRateLimits are the rate limits of single controllers, see ratelimit.SetOverrides
+	KeyRateLimits = "rate-limits"
+)
+
+// keys maps all settings to true, if they can be overridden per namespace
+var keys = map[string]bool{
+	KeyCtxTimeOut:            true,
+	KeyClusterDomain:         false,
+	KeyBoshDNSDockerImage:    true,
+	KeyDockerImageOrg:        false,
+	KeyDockerImageRepository: false,
+	KeyDockerImageTag:        false,
+	KeyDockerImagePullPolicy: false,
+	KeyRateLimits:            false,
+}
+
+var (
+	mutex      sync.RWMutex
+	defaults   = map[string]string{}
+	global     = map[string]string{}
+	namespaces = map[string]map[string]string{}
+)
+
+// Validate checks the data of a ConfigMap. Global settings are rejected in
+// the ConfigMaps of watched namespaces.
+func Validate(data map[string]string, namespaced bool) error {
+	for key, value := range data {
+		perNamespace, ok := keys[key]
+		if !ok {
+			return fmt.Errorf("unknown setting '%s'", key)
+		}
+		if namespaced && !perNamespace {
+			return fmt.Errorf("setting '%s' can't be overridden per namespace", key)
+		}
+
+		if key == KeyCtxTimeOut {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.Wrapf(err, "invalid setting '%s'", key)
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("invalid setting '%s', must be positive", key)
+			}
+		}
+	}
+	return nil
+}
+
+// SetDefaults sets the values of settings, which are missing from the
+// ConfigMaps
+func SetDefaults(data map[string]string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	defaults = copyData(data)
+}
+
+// SetGlobal sets the settings of the ConfigMap in the operator namespace
+func SetGlobal(data map[string]string) error {
+	if err := Validate(data, false); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	global = copyData(data)
+	return nil
+}
+
+// SetNamespace sets the settings of the ConfigMap in a watched namespace
+func SetNamespace(namespace string, data map[string]string) error {
+	if err := Validate(data, true); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	namespaces[namespace] = copyData(data)
+	return nil
+}
+
+// DeleteNamespace removes the overrides of a watched namespace
+func DeleteNamespace(namespace string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(namespaces, namespace)
+}
+
+// Get returns the setting for the namespace. The override of the namespace
+// takes precedence over the global setting, followed by the default. If
+// neither is set, def is returned.
+func Get(namespace string, key string, def string) string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if value, ok := namespaces[namespace][key]; ok && keys[key] {
+		return value
+	}
+	if value, ok := global[key]; ok {
+		return value
+	}
+	if value, ok := defaults[key]; ok {
+		return value
+	}
+	return def
+}
+
+// CtxTimeOut returns the reconcile timeout for the namespace, def is
+// returned if it's not configured
+func CtxTimeOut(namespace string, def time.Duration) time.Duration {
+	value := Get(namespace, KeyCtxTimeOut, "")
+	if value == "" {
+		return def
+	}
+
+	// Settings were validated when they were set
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return def
+	}
+	return timeout
+}
+
+func copyData(data map[string]string) map[string]string {
+	c := make(map[string]string, len(data))
+	for key, value := range data {
+		c[key] = value
+	}
+	return c
+}
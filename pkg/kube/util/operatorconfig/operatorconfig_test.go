@@ -0,0 +1,93 @@
+package operatorconfig_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/operatorconfig"
+)
+
+var _ = Describe("Operatorconfig", func() {
+	AfterEach(func() {
+		operatorconfig.SetDefaults(map[string]string{})
+		Expect(operatorconfig.SetGlobal(map[string]string{})).To(Succeed())
+		operatorconfig.DeleteNamespace("staging")
+	})
+
+	Describe("Validate", func() {
+		It("accepts known settings", func() {
+			Expect(operatorconfig.Validate(map[string]string{
+				"ctx-timeout":    "30s",
+				"cluster-domain": "example.com",
+				"rate-limits":    "bpm-controller.qps=1",
+			}, false)).To(Succeed())
+		})
+
+		It("rejects unknown settings", func() {
+			err := operatorconfig.Validate(map[string]string{"max-workers": "1"}, false)
+			Expect(err).To(MatchError("unknown setting 'max-workers'"))
+		})
+
+		It("rejects global settings in namespaces", func() {
+			err := operatorconfig.Validate(map[string]string{"cluster-domain": "example.com"}, true)
+			Expect(err).To(MatchError("setting 'cluster-domain' can't be overridden per namespace"))
+		})
+
+		It("rejects invalid timeouts", func() {
+			Expect(operatorconfig.Validate(map[string]string{"ctx-timeout": "soon"}, false)).ToNot(Succeed())
+			Expect(operatorconfig.Validate(map[string]string{"ctx-timeout": "0s"}, false)).ToNot(Succeed())
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns def, if the setting is not configured", func() {
+			Expect(operatorconfig.Get("staging", "bosh-dns-docker-image", "coredns")).To(Equal("coredns"))
+		})
+
+		It("prefers the default over def", func() {
+			operatorconfig.SetDefaults(map[string]string{"bosh-dns-docker-image": "default"})
+			Expect(operatorconfig.Get("staging", "bosh-dns-docker-image", "coredns")).To(Equal("default"))
+		})
+
+		It("prefers the global setting over the default", func() {
+			operatorconfig.SetDefaults(map[string]string{"bosh-dns-docker-image": "default"})
+			Expect(operatorconfig.SetGlobal(map[string]string{"bosh-dns-docker-image": "global"})).To(Succeed())
+			Expect(operatorconfig.Get("staging", "bosh-dns-docker-image", "coredns")).To(Equal("global"))
+		})
+
+		It("prefers the namespace override over the global setting", func() {
+			Expect(operatorconfig.SetGlobal(map[string]string{"bosh-dns-docker-image": "global"})).To(Succeed())
+			Expect(operatorconfig.SetNamespace("staging", map[string]string{"bosh-dns-docker-image": "staging"})).To(Succeed())
+			Expect(operatorconfig.Get("staging", "bosh-dns-docker-image", "coredns")).To(Equal("staging"))
+			Expect(operatorconfig.Get("production", "bosh-dns-docker-image", "coredns")).To(Equal("global"))
+		})
+
+		It("falls back to the global setting, once the override is deleted", func() {
+			Expect(operatorconfig.SetGlobal(map[string]string{"bosh-dns-docker-image": "global"})).To(Succeed())
+			Expect(operatorconfig.SetNamespace("staging", map[string]string{"bosh-dns-docker-image": "staging"})).To(Succeed())
+			operatorconfig.DeleteNamespace("staging")
+			Expect(operatorconfig.Get("staging", "bosh-dns-docker-image", "coredns")).To(Equal("global"))
+		})
+
+		It("keeps the previous settings, if new ones are invalid", func() {
+			Expect(operatorconfig.SetGlobal(map[string]string{"cluster-domain": "example.com"})).To(Succeed())
+			Expect(operatorconfig.SetGlobal(map[string]string{"cluster-domain": "other.com", "foo": "bar"})).ToNot(Succeed())
+			Expect(operatorconfig.Get("", "cluster-domain", "cluster.local")).To(Equal("example.com"))
+		})
+	})
+
+	Describe("CtxTimeOut", func() {
+		It("returns def, if the timeout is not configured", func() {
+			Expect(operatorconfig.CtxTimeOut("staging", 10*time.Second)).To(Equal(10 * time.Second))
+		})
+
+		It("returns the timeout of the namespace", func() {
+			Expect(operatorconfig.SetGlobal(map[string]string{"ctx-timeout": "1m"})).To(Succeed())
+			Expect(operatorconfig.SetNamespace("staging", map[string]string{"ctx-timeout": "5m"})).To(Succeed())
+			Expect(operatorconfig.CtxTimeOut("staging", 10*time.Second)).To(Equal(5 * time.Minute))
+			Expect(operatorconfig.CtxTimeOut("production", 10*time.Second)).To(Equal(time.Minute))
+		})
+	})
+})
@@ -1,12 +1,18 @@
 package operatorimage
 
 import (
+	"sync"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"code.cloudfoundry.org/quarks-utils/pkg/config"
 	"code.cloudfoundry.org/quarks-utils/pkg/names"
 )
 
+// lock protects the image settings, the operator config watcher changes
+// them while reconcilers read them
+var lock sync.RWMutex
+
 // operatorDockerImage is the location of the operators own docker image
 var operatorDockerImage string
 var operatorImagePullPolicy corev1.PullPolicy
@@ -18,6 +24,9 @@ func SetupOperatorDockerImage(org, repo, tag string, pullPolicy corev1.PullPolic
 		return err
 	}
 
+	lock.Lock()
+	defer lock.Unlock()
+
 	// setup quarks job docker image, too.
 	// will have to change this once the persist command is moved to quarks-job
 	if err := config.SetupOperatorDockerImage(org, repo, tag); err != nil {
@@ -37,10 +46,14 @@ func SetupOperatorDockerImage(org, repo, tag string, pullPolicy corev1.PullPolic
 
 // GetOperatorDockerImage returns the image name of the operator docker image
 func GetOperatorDockerImage() string {
+	lock.RLock()
+	defer lock.RUnlock()
 	return operatorDockerImage
 }
 
 // GetOperatorImagePullPolicy returns the image pull policy to be used for generated pods
 func GetOperatorImagePullPolicy() corev1.PullPolicy {
+	lock.RLock()
+	defer lock.RUnlock()
 	return operatorImagePullPolicy
 }
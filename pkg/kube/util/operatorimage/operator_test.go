@@ -1,6 +1,8 @@
 package operatorimage_test
 
 import (
+	"sync"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -17,5 +19,28 @@ var _ = Describe("operatorimage", func() {
 			Expect(operatorimage.GetOperatorDockerImage()).To(Equal("foo/bar:1.2.3"))
 			Expect(operatorimage.GetOperatorImagePullPolicy()).To(Equal(corev1.PullAlways))
 		})
+
+		It("can be changed while it is read", func() {
+			err := operatorimage.SetupOperatorDockerImage("foo", "bar", "1.2.3", corev1.PullAlways)
+			Expect(err).ToNot(HaveOccurred())
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					defer GinkgoRecover()
+					err := operatorimage.SetupOperatorDockerImage("foo", "bar", "1.2.3", corev1.PullAlways)
+					Expect(err).ToNot(HaveOccurred())
+				}()
+				go func() {
+					defer wg.Done()
+					defer GinkgoRecover()
+					Expect(operatorimage.GetOperatorDockerImage()).To(Equal("foo/bar:1.2.3"))
+					Expect(operatorimage.GetOperatorImagePullPolicy()).To(Equal(corev1.PullAlways))
+				}()
+			}
+			wg.Wait()
+		})
 	})
 })
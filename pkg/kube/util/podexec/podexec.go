@@ -0,0 +1,90 @@
+package podexec
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodExec runs commands in containers of running pods, like `kubectl exec`
+type PodExec struct {
+	restConfig *rest.Config
+	clientSet  kubernetes.Interface
+}
+
+// NewPodExec constructs a PodExec from a rest config
+func NewPodExec(restConfig *rest.Config) (*PodExec, error) {
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube client for pod exec")
+	}
+
+	return &PodExec{
+		restConfig: restConfig,
+		clientSet:  clientSet,
+	}, nil
+}
+
+// Exec runs the command in the container of the pod and returns its stdout
+// and stderr. A non-zero exit code of the command is returned as an error.
+func (e *PodExec) Exec(ctx context.Context, namespace, podName, containerName string, command []string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Stream(namespace, podName, containerName, command, remotecommand.StreamOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+		})
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// The stream keeps writing to the buffers, don't touch them
+		return "", "", errors.Wrapf(ctx.Err(), "exec in '%s/%s' container '%s' timed out", namespace, podName, containerName)
+	}
+	if err != nil {
+		return stdout.String(), stderr.String(), err
+	}
+
+	return stdout.String(), stderr.String(), nil
+}
+
+// Stream runs the command in the container of the pod and connects it to the
+// streams of the options, until the command exits. Stdin and a TTY are only
+// requested if the options contain them, like `kubectl exec -it` does.
+func (e *PodExec) Stream(namespace, podName, containerName string, command []string, options remotecommand.StreamOptions) error {
+	req := e.clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdin:     options.Stdin != nil,
+			Stdout:    options.Stdout != nil,
+			Stderr:    options.Stderr != nil,
+			TTY:       options.Tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.restConfig, "POST", req.URL())
+	if err != nil {
+		return errors.Wrapf(err, "failed to set up exec in '%s/%s' container '%s'", namespace, podName, containerName)
+	}
+
+	err = executor.Stream(options)
+	if err != nil {
+		return errors.Wrapf(err, "failed to exec in '%s/%s' container '%s'", namespace, podName, containerName)
+	}
+
+	return nil
+}
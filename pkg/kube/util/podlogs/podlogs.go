@@ -0,0 +1,55 @@
+package podlogs
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// maxTailBytes limits the size of a log tail, it's stored in the status of
+// resources
+const maxTailBytes = 4096
+
+// PodLogs reads the logs of containers, like `kubectl logs`
+type PodLogs struct {
+	clientSet kubernetes.Interface
+}
+
+// NewPodLogs constructs a PodLogs from a rest config
+func NewPodLogs(restConfig *rest.Config) (*PodLogs, error) {
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube client for pod logs")
+	}
+
+	return &PodLogs{clientSet: clientSet}, nil
+}
+
+// TailLog returns the last lines of the container's log. The log of the
+// previous instance of the container is read, if previous is set.
+func (l *PodLogs) TailLog(ctx context.Context, namespace, podName, containerName string, lines int64, previous bool) (string, error) {
+	limitBytes := int64(maxTailBytes)
+	req := l.clientSet.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  containerName,
+		TailLines:  &lines,
+		LimitBytes: &limitBytes,
+		Previous:   previous,
+	})
+
+	stream, err := req.Context(ctx).Stream()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read log of '%s/%s' container '%s'", namespace, podName, containerName)
+	}
+	defer stream.Close()
+
+	log, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read log of '%s/%s' container '%s'", namespace, podName, containerName)
+	}
+	return string(log), nil
+}
@@ -0,0 +1,33 @@
+package quarkscli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	qjv1a1 "code.cloudfoundry.org/quarks-job/pkg/kube/apis/quarksjob/v1alpha1"
+)
+
+// RunErrand triggers the QuarksJob of an errand, like `bosh run-errand`
+func (c *CLI) RunErrand(deploymentName string, errandName string) error {
+	qJobs := c.quarksJobClientSet.QuarksjobV1alpha1().QuarksJobs(c.namespace)
+
+	qJobName := fmt.Sprintf("%s-%s", deploymentName, errandName)
+	qJob, err := qJobs.Get(qJobName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get QuarksJob of errand '%s'", errandName)
+	}
+
+	if qJob.Spec.Trigger.Strategy == qjv1a1.TriggerNow {
+		return errors.Errorf("errand '%s' is already triggered", errandName)
+	}
+
+	qJob.Spec.Trigger.Strategy = qjv1a1.TriggerNow
+	_, err = qJobs.Update(qJob)
+	if err != nil {
+		return errors.Wrapf(err, "failed to trigger QuarksJob of errand '%s'", errandName)
+	}
+
+	return nil
+}
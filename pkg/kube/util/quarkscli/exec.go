@@ -0,0 +1,112 @@
+package quarkscli
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"code.cloudfoundry.org/cf-operator/pkg/bosh/bpmconverter"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+)
+
+const (
+	// logDir is the directory the BOSH jobs write their logs to
+	logDir = "/var/vcap/sys/log"
+)
+
+// DefaultSSHCommand is run by SSH, if no command is given
+var DefaultSSHCommand = []string{"/bin/bash"}
+
+// SSH runs a command in a job container of the instance, like `bosh ssh`.
+// The first job container is used, unless a job is given.
+func (c *CLI) SSH(deploymentName string, instanceName string, jobName string, command []string, options remotecommand.StreamOptions) error {
+	instance, err := c.Instance(deploymentName, instanceName)
+	if err != nil {
+		return err
+	}
+
+	containerName, err := c.jobContainer(instance.Pod, jobName)
+	if err != nil {
+		return err
+	}
+
+	if len(command) == 0 {
+		command = DefaultSSHCommand
+	}
+
+	return c.podStreamer.Stream(c.namespace, instance.Pod, containerName, command, options)
+}
+
+// Logs writes a gzipped tar archive of the logs of the instance to out, like
+// `bosh logs`. Only the logs of the job are archived, if a job is given.
+func (c *CLI) Logs(deploymentName string, instanceName string, jobName string, out io.Writer, errOut io.Writer) error {
+	instance, err := c.Instance(deploymentName, instanceName)
+	if err != nil {
+		return err
+	}
+
+	containerName, err := c.jobContainer(instance.Pod, jobName)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Base(logDir)
+	if jobName != "" {
+		dir = filepath.Join(dir, jobName)
+	}
+	command := []string{"tar", "-czf", "-", "-C", filepath.Dir(logDir), dir}
+
+	return c.podStreamer.Stream(c.namespace, instance.Pod, containerName, command, remotecommand.StreamOptions{
+		Stdout: out,
+		Stderr: errOut,
+	})
+}
+
+// jobContainer returns the name of the first container running a process of
+// the job, or the first job container if no job is given
+func (c *CLI) jobContainer(podName string, jobName string) (string, error) {
+	pod, err := c.clientSet.CoreV1().Pods(c.namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get pod '%s'", podName)
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name == bpmconverter.LogsContainerName {
+			continue
+		}
+		if jobName == "" || runsJob(container, jobName) {
+			return container.Name, nil
+		}
+	}
+
+	if jobName == "" {
+		return "", errors.Errorf("pod '%s' has no job containers", podName)
+	}
+	return "", errors.Errorf("pod '%s' has no containers for job '%s'", podName, jobName)
+}
+
+// runsJob returns true if the container runs a BPM process of the job. The
+// container name alone is ambiguous, e.g. 'nats-tls-nats' could be the
+// process 'tls-nats' of the job 'nats', so the drain handler, which points to
+// the job directory, has to match, too.
+func runsJob(container corev1.Container, jobName string) bool {
+	if !strings.HasPrefix(container.Name, names.Sanitize(jobName)+"-") {
+		return false
+	}
+	if container.Lifecycle == nil || container.Lifecycle.PreStop == nil || container.Lifecycle.PreStop.Exec == nil {
+		return false
+	}
+
+	drainDir := filepath.Join(bpmconverter.VolumeJobsDirMountPath, jobName, "bin", "drain") + "/"
+	for _, arg := range container.Lifecycle.PreStop.Exec.Command {
+		if strings.Contains(arg, drainDir) {
+			return true
+		}
+	}
+	return false
+}
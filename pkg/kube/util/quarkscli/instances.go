@@ -0,0 +1,211 @@
+package quarkscli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	qstsv1a1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/quarksstatefulset/v1alpha1"
+)
+
+// InstanceGroup is a BOSH instance group of a deployment. Errands don't have
+// instances, until they are run.
+type InstanceGroup struct {
+	Name      string
+	Errand    bool
+	Instances int
+	Ready     int
+}
+
+// Instance is a BOSH instance, i.e. a pod of an instance group
+type Instance struct {
+	InstanceGroup string
+	Index         int
+	AZ            string
+	Bootstrap     bool
+	Ready         bool
+	Pod           string
+	PodUID        string
+	IP            string
+	Created       metav1.Time
+	Phase         corev1.PodPhase
+}
+
+// Name returns the BOSH name of the instance, e.g. 'nats/0'
+func (i Instance) Name() string {
+	return fmt.Sprintf("%s/%d", i.InstanceGroup, i.Index)
+}
+
+// InstanceGroups lists the instance groups and errands of the deployment
+func (c *CLI) InstanceGroups(deploymentName string) ([]InstanceGroup, error) {
+	selector := labels.Set{bdm.LabelDeploymentName: deploymentName}.String()
+
+	qStsList, err := c.versionedClientSet.QuarksstatefulsetV1alpha1().QuarksStatefulSets(c.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list QuarksStatefulSets of deployment '%s'", deploymentName)
+	}
+
+	instances, err := c.Instances(deploymentName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	igs := []InstanceGroup{}
+	for _, qSts := range qStsList.Items {
+		ig := InstanceGroup{
+			Name:      qSts.Labels[bdm.LabelInstanceGroupName],
+			Instances: replicas(qSts) * zoneCount(qSts),
+		}
+		for _, instance := range instances {
+			if instance.InstanceGroup == ig.Name && instance.Ready {
+				ig.Ready++
+			}
+		}
+		igs = append(igs, ig)
+	}
+
+	qJobList, err := c.quarksJobClientSet.QuarksjobV1alpha1().QuarksJobs(c.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list QuarksJobs of deployment '%s'", deploymentName)
+	}
+	for _, qJob := range qJobList.Items {
+		igs = append(igs, InstanceGroup{
+			Name:   qJob.Labels[bdm.LabelInstanceGroupName],
+			Errand: true,
+		})
+	}
+
+	sort.Slice(igs, func(i, j int) bool { return igs[i].Name < igs[j].Name })
+
+	return igs, nil
+}
+
+// Instances lists the instances of the deployment, optionally only those of
+// one instance group. The BOSH index of an instance is calculated from the AZ
+// index and pod ordinal labels of its pod.
+func (c *CLI) Instances(deploymentName string, instanceGroupName string) ([]Instance, error) {
+	selector := labels.Set{bdm.LabelDeploymentName: deploymentName}
+	if instanceGroupName != "" {
+		selector[bdm.LabelInstanceGroupName] = instanceGroupName
+	}
+
+	qStsList, err := c.versionedClientSet.QuarksstatefulsetV1alpha1().QuarksStatefulSets(c.namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list QuarksStatefulSets of deployment '%s'", deploymentName)
+	}
+	igReplicas := map[string]int{}
+	for _, qSts := range qStsList.Items {
+		igReplicas[qSts.Labels[bdm.LabelInstanceGroupName]] = replicas(qSts)
+	}
+
+	pods, err := c.clientSet.CoreV1().Pods(c.namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods of deployment '%s'", deploymentName)
+	}
+
+	instances := []Instance{}
+	for _, pod := range pods.Items {
+		igName := pod.Labels[bdm.LabelInstanceGroupName]
+		replicas, ok := igReplicas[igName]
+		if !ok {
+			// errand pods don't belong to a QuarksStatefulSet
+			continue
+		}
+
+		ordinal, err := strconv.Atoi(pod.Labels[qstsv1a1.LabelPodOrdinal])
+		if err != nil {
+			continue
+		}
+		azIndex, err := strconv.Atoi(pod.Labels[qstsv1a1.LabelAZIndex])
+		if err != nil {
+			azIndex = 0
+		}
+
+		index := azIndex*replicas + ordinal
+		instances = append(instances, Instance{
+			InstanceGroup: igName,
+			Index:         index,
+			AZ:            pod.Labels[qstsv1a1.LabelAZName],
+			Bootstrap:     index == 0,
+			Ready:         podReady(pod),
+			Pod:           pod.Name,
+			PodUID:        string(pod.UID),
+			IP:            pod.Status.PodIP,
+			Created:       pod.CreationTimestamp,
+			Phase:         pod.Status.Phase,
+		})
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].InstanceGroup != instances[j].InstanceGroup {
+			return instances[i].InstanceGroup < instances[j].InstanceGroup
+		}
+		return instances[i].Index < instances[j].Index
+	})
+
+	return instances, nil
+}
+
+// Instance finds an instance by its BOSH name, e.g. 'nats/0'. The index can
+// be omitted, if the instance group has only one instance.
+func (c *CLI) Instance(deploymentName string, name string) (Instance, error) {
+	igName := name
+	index := -1
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		var err error
+		igName = name[:i]
+		index, err = strconv.Atoi(name[i+1:])
+		if err != nil {
+			return Instance{}, errors.Errorf("invalid instance '%s', expected 'instance-group/index'", name)
+		}
+	}
+
+	instances, err := c.Instances(deploymentName, igName)
+	if err != nil {
+		return Instance{}, err
+	}
+
+	if index == -1 {
+		if len(instances) != 1 {
+			return Instance{}, errors.Errorf("instance group '%s' has %d instances, please specify an index", igName, len(instances))
+		}
+		return instances[0], nil
+	}
+
+	for _, instance := range instances {
+		if instance.Index == index {
+			return instance, nil
+		}
+	}
+	return Instance{}, errors.Errorf("instance '%s' not found in deployment '%s'", name, deploymentName)
+}
+
+func replicas(qSts qstsv1a1.QuarksStatefulSet) int {
+	if qSts.Spec.Template.Spec.Replicas == nil {
+		return 1
+	}
+	return int(*qSts.Spec.Template.Spec.Replicas)
+}
+
+func zoneCount(qSts qstsv1a1.QuarksStatefulSet) int {
+	if len(qSts.Spec.Zones) == 0 {
+		return 1
+	}
+	return len(qSts.Spec.Zones)
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
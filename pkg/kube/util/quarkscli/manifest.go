@@ -0,0 +1,145 @@
+package quarkscli
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+	"code.cloudfoundry.org/quarks-utils/pkg/versionedsecretstore"
+)
+
+// Redacted replaces the values of variables in the manifest
+const Redacted = "<redacted>"
+
+// Manifest returns the latest desired manifest of the deployment, like
+// `bosh manifest`. Variables are interpolated in the desired manifest, their
+// values are redacted unless showSecrets is set.
+func (c *CLI) Manifest(ctx context.Context, deploymentName string, showSecrets bool) ([]byte, error) {
+	_, err := c.versionedClientSet.BoshdeploymentV1alpha1().BOSHDeployments(c.namespace).Get(deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get BOSHDeployment '%s'", deploymentName)
+	}
+
+	secretName := names.DesiredManifestName(deploymentName, "")
+	secret, err := versionedsecretstore.NewClientsetVersionedSecretStore(c.clientSet).Latest(ctx, c.namespace, secretName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read latest desired manifest of deployment '%s'", deploymentName)
+	}
+
+	manifest, ok := secret.Data["manifest.yaml"]
+	if !ok {
+		return nil, errors.Errorf("secret '%s' has no manifest.yaml key", secret.Name)
+	}
+	if showSecrets {
+		return manifest, nil
+	}
+
+	withOpsName := names.DeploymentSecretName(names.DeploymentSecretTypeManifestWithOps, deploymentName, "")
+	withOpsSecret, err := c.clientSet.CoreV1().Secrets(c.namespace).Get(withOpsName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get manifest with ops of deployment '%s'", deploymentName)
+	}
+	withOps, ok := withOpsSecret.Data["manifest.yaml"]
+	if !ok {
+		return nil, errors.Errorf("secret '%s' has no manifest.yaml key", withOpsSecret.Name)
+	}
+
+	return redact(manifest, withOps)
+}
+
+// variableRegexp matches variable placeholders, like the variable
+// interpolation does
+var variableRegexp = regexp.MustCompile(`\(\((!?[-/\.\w\pL]+)\)\)`)
+
+// redact replaces the values, which were interpolated for variables, in the
+// desired manifest. The manifest with ops still contains the '((variable))'
+// placeholders, so it tells where the values are. Values of any type are
+// redacted, keeping the order of the keys.
+func redact(manifest []byte, withOps []byte) ([]byte, error) {
+	var doc yaml.MapSlice
+	err := yaml.Unmarshal(manifest, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse desired manifest")
+	}
+
+	var origin yaml.MapSlice
+	err = yaml.Unmarshal(withOps, &origin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse manifest with ops")
+	}
+
+	return yaml.Marshal(redactNode(doc, origin))
+}
+
+// redactNode redacts the node of the desired manifest, origin is the same
+// node in the manifest with ops
+func redactNode(node interface{}, origin interface{}) interface{} {
+	switch origin := origin.(type) {
+	case string:
+		if !variableRegexp.MatchString(origin) {
+			return node
+		}
+		if variableRegexp.FindString(origin) == origin {
+			return Redacted
+		}
+		// Variables within a string are interpolated as strings
+		return variableRegexp.ReplaceAllLiteralString(origin, Redacted)
+	case yaml.MapSlice:
+		n, ok := node.(yaml.MapSlice)
+		if !ok {
+			return node
+		}
+		for i := range n {
+			if o, ok := lookup(origin, n[i].Key); ok {
+				n[i].Value = redactNode(n[i].Value, o)
+			}
+		}
+		return n
+	case []interface{}:
+		n, ok := node.([]interface{})
+		if !ok {
+			return node
+		}
+		for i := range n {
+			if o, ok := element(origin, n[i], i); ok {
+				n[i] = redactNode(n[i], o)
+			}
+		}
+		return n
+	}
+	return node
+}
+
+func lookup(m yaml.MapSlice, key interface{}) (interface{}, bool) {
+	for _, item := range m {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+// element returns the element of the origin list, which corresponds to the
+// node. Elements with a name, like instance groups and jobs, are matched by
+// name, others and elements whose name was a variable by index.
+func element(origin []interface{}, node interface{}, index int) (interface{}, bool) {
+	if n, ok := node.(yaml.MapSlice); ok {
+		if name, ok := lookup(n, "name"); ok {
+			for _, o := range origin {
+				if m, ok := o.(yaml.MapSlice); ok {
+					if oName, ok := lookup(m, "name"); ok && oName == name {
+						return o, true
+					}
+				}
+			}
+		}
+	}
+	if index < len(origin) {
+		return origin[index], true
+	}
+	return nil, false
+}
@@ -0,0 +1,42 @@
+// Package quarkscli implements the `bosh` CLI like workflows of the
+// kubectl-quarks plugin on top of the resources created by the operator.
+package quarkscli
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/client/clientset/versioned"
+	qjversioned "code.cloudfoundry.org/quarks-job/pkg/kube/client/clientset/versioned"
+)
+
+// PodStreamer runs commands in containers of running pods
+type PodStreamer interface {
+	Stream(namespace, podName, containerName string, command []string, options remotecommand.StreamOptions) error
+}
+
+// CLI lists and operates the instances of BOSH deployments in a namespace
+type CLI struct {
+	namespace          string
+	clientSet          kubernetes.Interface
+	versionedClientSet versioned.Interface
+	quarksJobClientSet qjversioned.Interface
+	podStreamer        PodStreamer
+}
+
+// NewCLI constructs a CLI for the namespace
+func NewCLI(
+	namespace string,
+	clientSet kubernetes.Interface,
+	versionedClientSet versioned.Interface,
+	quarksJobClientSet qjversioned.Interface,
+	podStreamer PodStreamer,
+) *CLI {
+	return &CLI{
+		namespace:          namespace,
+		clientSet:          clientSet,
+		versionedClientSet: versionedClientSet,
+		quarksJobClientSet: quarksJobClientSet,
+		podStreamer:        podStreamer,
+	}
+}
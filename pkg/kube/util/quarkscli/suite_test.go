@@ -0,0 +1,13 @@
+package quarkscli_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestQuarksCLI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "QuarksCLI Suite")
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NewController creates a controller, whose reconciler is rate limited. Only
+// retries are held back, the watches of the controller reset the backoff of
+// a request, when an event for it arrives, e.g. because its spec was fixed.
+func NewController(ctx context.Context, name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
+	r := NewReconciler(ctx, name, options.Reconciler)
+	options.Reconciler = r
+
+	c, err := controller.New(name, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+	return &rateLimitedController{Controller: c, reconciler: r}, nil
+}
+
+// rateLimitedController passes the events of its watches to the reconciler
+type rateLimitedController struct {
+	controller.Controller
+	reconciler *Reconciler
+}
+
+// Watch wraps the event handler, so events reset the backoff
+func (c *rateLimitedController) Watch(src source.Source, h handler.EventHandler, predicates ...predicate.Predicate) error {
+	return c.Controller.Watch(src, c.reconciler.Handler(h), predicates...)
+}
+
+// Handler wraps the event handler, requests it enqueues are no longer held
+// back by previous failures
+func (r *Reconciler) Handler(h handler.EventHandler) handler.EventHandler {
+	return &eventHandler{handler: h, reconciler: r}
+}
+
+type eventHandler struct {
+	handler    handler.EventHandler
+	reconciler *Reconciler
+}
+
+func (e *eventHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.handler.Create(evt, e.queue(q))
+}
+
+func (e *eventHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.handler.Update(evt, e.queue(q))
+}
+
+func (e *eventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.handler.Delete(evt, e.queue(q))
+}
+
+func (e *eventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.handler.Generic(evt, e.queue(q))
+}
+
+func (e *eventHandler) queue(q workqueue.RateLimitingInterface) workqueue.RateLimitingInterface {
+	return &resetQueue{RateLimitingInterface: q, reconciler: e.reconciler}
+}
+
+// resetQueue resets the backoff of requests, which event handlers add
+type resetQueue struct {
+	workqueue.RateLimitingInterface
+	reconciler *Reconciler
+}
+
+// Add resets the backoff of the request, before adding it to the queue.
+// Delayed and rate limited adds are retries and keep the backoff.
+func (q *resetQueue) Add(item interface{}) {
+	if request, ok := item.(reconcile.Request); ok {
+		q.reconciler.reset(request)
+	}
+	q.RateLimitingInterface.Add(item)
+}
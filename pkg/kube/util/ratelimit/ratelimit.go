@@ -0,0 +1,233 @@
+// Package ratelimit throttles the reconciles of the controllers. Each
+// controller has its own per-request exponential backoff on failures and a
+// token bucket limiting the rate of retries, like the default rate limiter
+// of controller work queues. The work queue of controller-runtime can't be
+// configured, so the limiter wraps the reconciler and requeues held back
+// requests. Watch events reset the backoff, so only retries are held back.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// Options configure the rate limiting of a controller
+type Options struct {
+	// BaseDelay is the delay after the first failed reconcile of a request,
+	// it doubles with every further failure
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay after a failed reconcile
+	MaxDelay time.Duration
+	// QPS is the rate of retries across all requests of the controller
+	QPS float64
+	// Burst is the number of retries, which can exceed QPS
+	Burst int
+}
+
+// DefaultOptions are used for controllers without overrides
+var DefaultOptions = Options{
+	BaseDelay: 5 * time.Millisecond,
+	MaxDelay:  1000 * time.Second,
+	QPS:       10,
+	Burst:     100,
+}
+
+var (
+	mutex     sync.RWMutex
+	defaults  = DefaultOptions
+	overrides = map[string]map[string]string{}
+)
+
+// SetDefaults sets the options of controllers without overrides
+func SetDefaults(o Options) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	defaults = o
+}
+
+// SetOverrides sets the options of single controllers. The comma separated
+// list contains settings in the form '<controller>.<setting>=<value>', e.g.
+// 'boshdeployment-controller.max-delay=5m'. Settings are 'base-delay',
+// 'max-delay', 'qps' and 'burst'.
+func SetOverrides(list string) error {
+	parsed := map[string]map[string]string{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		dot := strings.LastIndex(parts[0], ".")
+		if len(parts) != 2 || dot < 1 {
+			return errors.Errorf("invalid rate limit '%s', expected '<controller>.<setting>=<value>'", entry)
+		}
+		controller, setting, value := parts[0][:dot], parts[0][dot+1:], parts[1]
+
+		if err := set(&Options{}, setting, value); err != nil {
+			return errors.Wrapf(err, "invalid rate limit '%s'", entry)
+		}
+
+		if parsed[controller] == nil {
+			parsed[controller] = map[string]string{}
+		}
+		parsed[controller][setting] = value
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	overrides = parsed
+	return nil
+}
+
+// For returns the options of the controller
+func For(controller string) Options {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	o := defaults
+	for setting, value := range overrides[controller] {
+		// Settings were validated by SetOverrides
+		_ = set(&o, setting, value)
+	}
+	return o
+}
+
+func set(o *Options, setting string, value string) error {
+	var err error
+	switch setting {
+	case "base-delay":
+		o.BaseDelay, err = time.ParseDuration(value)
+	case "max-delay":
+		o.MaxDelay, err = time.ParseDuration(value)
+	case "qps":
+		o.QPS, err = strconv.ParseFloat(value, 64)
+	case "burst":
+		o.Burst, err = strconv.Atoi(value)
+	default:
+		return fmt.Errorf("unknown setting '%s'", setting)
+	}
+	return err
+}
+
+// Reconciler holds back the requests of a controller, whose last reconcile
+// failed, until the backoff of the request passed
+type Reconciler struct {
+	ctx        context.Context
+	name       string
+	reconciler reconcile.Reconciler
+	options    Options
+	limiter    workqueue.RateLimiter
+	// follow rebuilds the limiter, when the options of the controller change
+	follow bool
+
+	mutex     sync.Mutex
+	notBefore map[reconcile.Request]time.Time
+}
+
+// NewReconciler returns a rate limited reconciler for the named controller,
+// using the options set for it. Changes of the options at runtime apply to
+// the following failures.
+func NewReconciler(ctx context.Context, controller string, r reconcile.Reconciler) *Reconciler {
+	reconciler := New(ctx, controller, r, For(controller))
+	reconciler.follow = true
+	return reconciler
+}
+
+// New returns a rate limited reconciler
+func New(ctx context.Context, controller string, r reconcile.Reconciler, o Options) *Reconciler {
+	return &Reconciler{
+		ctx:        ctx,
+		name:       controller,
+		reconciler: r,
+		options:    o,
+		limiter:    newLimiter(o),
+		notBefore:  map[reconcile.Request]time.Time{},
+	}
+}
+
+func newLimiter(o Options) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(o.BaseDelay, o.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(o.QPS), o.Burst)},
+	)
+}
+
+// Reconcile requeues the request, if it's held back, or passes it to the
+// wrapped reconciler
+func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if wait := r.wait(request); wait > 0 {
+		ctxlog.Debugf(r.ctx, "Reconcile of '%s' is rate limited, requeue after %s", request.NamespacedName, wait)
+		metrics.RateLimitedRequeues.WithLabelValues(r.name).Inc()
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	result, err := r.reconciler.Reconcile(request)
+
+	// The work queue checks RequeueAfter before Requeue
+	if err != nil || (result.Requeue && result.RequeueAfter == 0) {
+		r.backoff(request)
+	} else {
+		r.forget(request)
+	}
+
+	return result, err
+}
+
+func (r *Reconciler) wait(request reconcile.Request) time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	notBefore, ok := r.notBefore[request]
+	if !ok {
+		return 0
+	}
+	return time.Until(notBefore)
+}
+
+func (r *Reconciler) backoff(request reconcile.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.follow {
+		if o := For(r.name); o != r.options {
+			ctxlog.Infof(r.ctx, "Rate limits of '%s' changed to %+v", r.name, o)
+			r.options = o
+			r.limiter = newLimiter(o)
+		}
+	}
+
+	r.notBefore[request] = time.Now().Add(r.limiter.When(request))
+}
+
+// reset stops holding back the request, the failure count stays, so the
+// backoff keeps growing if the request fails again
+func (r *Reconciler) reset(request reconcile.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.notBefore, request)
+}
+
+func (r *Reconciler) forget(request reconcile.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.limiter.Forget(request)
+	delete(r.notBefore, request)
+}
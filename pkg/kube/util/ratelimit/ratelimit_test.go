@@ -0,0 +1,195 @@
+package ratelimit_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/metrics"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/ratelimit"
+)
+
+type fakeReconciler struct {
+	calls  int
+	result reconcile.Result
+	err    error
+}
+
+func (r *fakeReconciler) Reconcile(_ reconcile.Request) (reconcile.Result, error) {
+	r.calls++
+	return r.result, r.err
+}
+
+var _ = Describe("Ratelimit", func() {
+	Describe("For", func() {
+		AfterEach(func() {
+			ratelimit.SetDefaults(ratelimit.DefaultOptions)
+			Expect(ratelimit.SetOverrides("")).To(Succeed())
+		})
+
+		It("returns the defaults for controllers without overrides", func() {
+			defaults := ratelimit.Options{BaseDelay: time.Second, MaxDelay: time.Minute, QPS: 1, Burst: 2}
+			ratelimit.SetDefaults(defaults)
+			Expect(ratelimit.For("bpm-controller")).To(Equal(defaults))
+		})
+
+		It("applies the overrides of the controller", func() {
+			err := ratelimit.SetOverrides("boshdeployment-controller.max-delay=5m, boshdeployment-controller.qps=2.5,bpm-controller.burst=7")
+			Expect(err).ToNot(HaveOccurred())
+
+			o := ratelimit.For("boshdeployment-controller")
+			Expect(o.MaxDelay).To(Equal(5 * time.Minute))
+			Expect(o.QPS).To(Equal(2.5))
+			Expect(o.BaseDelay).To(Equal(ratelimit.DefaultOptions.BaseDelay))
+			Expect(o.Burst).To(Equal(ratelimit.DefaultOptions.Burst))
+
+			Expect(ratelimit.For("bpm-controller").Burst).To(Equal(7))
+		})
+
+		It("fails for invalid overrides", func() {
+			for _, list := range []string{
+				"boshdeployment-controller",
+				"max-delay=5m",
+				"boshdeployment-controller.delay=5m",
+				"boshdeployment-controller.max-delay=five",
+			} {
+				Expect(ratelimit.SetOverrides(list)).ToNot(Succeed(), list)
+			}
+		})
+	})
+
+	Describe("Reconciler", func() {
+		var (
+			inner      *fakeReconciler
+			reconciler *ratelimit.Reconciler
+			request    reconcile.Request
+		)
+
+		BeforeEach(func() {
+			inner = &fakeReconciler{}
+			reconciler = ratelimit.New(context.Background(), "test-controller", inner, ratelimit.Options{
+				BaseDelay: time.Minute,
+				MaxDelay:  time.Hour,
+				QPS:       100,
+				Burst:     100,
+			})
+			request = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+		})
+
+		It("passes successful reconciles through", func() {
+			for i := 0; i < 3; i++ {
+				result, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+			}
+			Expect(inner.calls).To(Equal(3))
+		})
+
+		It("holds back requests after a failed reconcile", func() {
+			inner.err = errors.New("fake-error")
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(HaveOccurred())
+
+			requeues := testutil.ToFloat64(metrics.RateLimitedRequeues.WithLabelValues("test-controller"))
+			result, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+			Expect(inner.calls).To(Equal(1))
+			Expect(testutil.ToFloat64(metrics.RateLimitedRequeues.WithLabelValues("test-controller"))).To(Equal(requeues + 1))
+
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "bar"}}
+			_, err = reconciler.Reconcile(other)
+			Expect(err).To(HaveOccurred())
+			Expect(inner.calls).To(Equal(2))
+		})
+
+		It("doesn't hold back requests after a watch event", func() {
+			inner.err = errors.New("fake-error")
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(HaveOccurred())
+
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+			meta := &metav1.ObjectMeta{Namespace: "default", Name: "foo"}
+			reconciler.Handler(&handler.EnqueueRequestForObject{}).Update(event.UpdateEvent{MetaOld: meta, MetaNew: meta}, queue)
+			Expect(queue.Len()).To(Equal(1))
+
+			inner.err = nil
+			result, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(inner.calls).To(Equal(2))
+		})
+
+		It("keeps holding back retries, which are added rate limited", func() {
+			inner.err = errors.New("fake-error")
+			_, err := reconciler.Reconcile(request)
+			Expect(err).To(HaveOccurred())
+
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+			reconciler.Handler(&handler.Funcs{
+				GenericFunc: func(_ event.GenericEvent, q workqueue.RateLimitingInterface) {
+					q.AddRateLimited(request)
+				},
+			}).Generic(event.GenericEvent{}, queue)
+
+			result, _ := reconciler.Reconcile(request)
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+			Expect(inner.calls).To(Equal(1))
+		})
+
+		It("holds back requests, which asked to be requeued", func() {
+			inner.result = reconcile.Result{Requeue: true}
+			_, err := reconciler.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			result, _ := reconciler.Reconcile(request)
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(inner.calls).To(Equal(1))
+		})
+
+		It("doesn't hold back requests, which are requeued after a delay", func() {
+			inner.result = reconcile.Result{RequeueAfter: time.Second}
+			for i := 0; i < 2; i++ {
+				result, err := reconciler.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(inner.result))
+			}
+			Expect(inner.calls).To(Equal(2))
+		})
+
+		Context("when the options of the controller change", func() {
+			AfterEach(func() {
+				Expect(ratelimit.SetOverrides("")).To(Succeed())
+			})
+
+			It("applies them to the following failures", func() {
+				Expect(ratelimit.SetOverrides("test-controller.base-delay=1m")).To(Succeed())
+				reconciler = ratelimit.NewReconciler(context.Background(), "test-controller", inner)
+				inner.err = errors.New("fake-error")
+
+				_, _ = reconciler.Reconcile(request)
+				result, _ := reconciler.Reconcile(request)
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+
+				Expect(ratelimit.SetOverrides("test-controller.base-delay=10m")).To(Succeed())
+				other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "bar"}}
+				_, _ = reconciler.Reconcile(other)
+				result, _ = reconciler.Reconcile(other)
+				Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Minute, time.Second))
+			})
+		})
+	})
+})
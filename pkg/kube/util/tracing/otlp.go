@@ -0,0 +1,230 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+const (
+	// otlpTracesPath is appended to the endpoint, like the OpenTelemetry SDKs
+	// do for OTEL_EXPORTER_OTLP_ENDPOINT
+	otlpTracesPath = "/v1/traces"
+	// otlpBatchSize is the maximum number of spans per request
+	otlpBatchSize = 256
+	// otlpQueueSize is the number of spans which are buffered, more spans
+	// are dropped until the queue is sent
+	otlpQueueSize = 4096
+	// otlpInterval is the time spans are collected before they are sent
+	otlpInterval = 5 * time.Second
+
+	serviceName = "cf-operator"
+)
+
+// OTLPExporter sends spans to an OpenTelemetry collector via OTLP/HTTP, in the
+// JSON encoding. It's started by the manager and sends the spans in batches.
+type OTLPExporter struct {
+	ctx    context.Context
+	url    string
+	client *http.Client
+	spans  chan *Span
+}
+
+// NewOTLPExporter returns an exporter for the collector at the endpoint, e.g.
+// http://otel-collector:4318
+func NewOTLPExporter(ctx context.Context, endpoint string) *OTLPExporter {
+	return &OTLPExporter{
+		ctx:    ctx,
+		url:    strings.TrimSuffix(endpoint, "/") + otlpTracesPath,
+		client: &http.Client{Timeout: 10 * time.Second},
+		spans:  make(chan *Span, otlpQueueSize),
+	}
+}
+
+// Export queues the span, it's dropped if the queue is full
+func (e *OTLPExporter) Export(span *Span) {
+	select {
+	case e.spans <- span:
+	default:
+		ctxlog.Debugf(e.ctx, "Dropping span '%s', the OTLP export queue is full", span.Name)
+	}
+}
+
+// Start sends the queued spans until the stop channel is closed, it
+// implements manager.Runnable
+func (e *OTLPExporter) Start(stop <-chan struct{}) error {
+	ticker := time.NewTicker(otlpInterval)
+	defer ticker.Stop()
+
+	batch := []*Span{}
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			ctxlog.Errorf(e.ctx, "Failed to export %d spans: %v", len(batch), err)
+		}
+		batch = []*Span{}
+	}
+
+	for {
+		select {
+		case span := <-e.spans:
+			batch = append(batch, span)
+			if len(batch) >= otlpBatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case <-stop:
+			for len(e.spans) > 0 {
+				batch = append(batch, <-e.spans)
+			}
+			send()
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, all replicas
+// export their spans
+func (e *OTLPExporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *OTLPExporter) send(spans []*Span) error {
+	body, err := json.Marshal(newOTLPRequest(spans))
+	if err != nil {
+		return errors.Wrap(err, "marshaling OTLP request")
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "sending spans to '%s'", e.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("sending spans to '%s' failed with status %s", e.url, resp.Status)
+	}
+	return nil
+}
+
+// The types below are the JSON encoding of the OTLP
+// ExportTraceServiceRequest. IDs are hex encoded and 64 bit integers are
+// strings, as the OTLP/JSON specification requires.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusCodeError  = 2
+)
+
+func newOTLPRequest(spans []*Span) otlpRequest {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.SpanContext.TraceID[:]),
+			SpanID:            hex.EncodeToString(s.SpanContext.SpanID[:]),
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+		}
+		if s.Parent != (SpanID{}) {
+			span.ParentSpanID = hex.EncodeToString(s.Parent[:])
+		}
+		if s.Error != "" {
+			span.Status = otlpStatus{Code: otlpStatusCodeError, Message: s.Error}
+		}
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: otlpAttributes(map[string]string{"service.name": serviceName}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: serviceName},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, otlpAttribute{Key: k, Value: otlpValue{StringValue: attributes[k]}})
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
+)
+
+var _ = Describe("OTLPExporter", func() {
+	var (
+		server   *httptest.Server
+		requests chan map[string]interface{}
+		paths    chan string
+	)
+
+	BeforeEach(func() {
+		requests = make(chan map[string]interface{}, 10)
+		paths = make(chan string, 10)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			request := map[string]interface{}{}
+			Expect(json.Unmarshal(body, &request)).To(Succeed())
+			paths <- r.URL.Path
+			requests <- request
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends the queued spans as OTLP/JSON, when stopped", func() {
+		exporter := tracing.NewOTLPExporter(context.Background(), server.URL+"/")
+		span := &tracing.Span{
+			Name:        "test",
+			SpanContext: tracing.SpanContext{TraceID: tracing.TraceID{1}, SpanID: tracing.SpanID{2}},
+			Parent:      tracing.SpanID{3},
+			StartTime:   time.Unix(1, 0),
+			EndTime:     time.Unix(2, 0),
+			Attributes:  map[string]string{"k8s.object.name": "foo"},
+			Error:       "fake-error",
+		}
+		exporter.Export(span)
+
+		stop := make(chan struct{})
+		close(stop)
+		Expect(exporter.Start(stop)).To(Succeed())
+
+		Expect(<-paths).To(Equal("/v1/traces"))
+		request := <-requests
+		Expect(request).To(HaveKey("resourceSpans"))
+		resourceSpans := request["resourceSpans"].([]interface{})[0].(map[string]interface{})
+		spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0]).To(Equal(map[string]interface{}{
+			"traceId":           "01000000000000000000000000000000",
+			"spanId":            "0200000000000000",
+			"parentSpanId":      "0300000000000000",
+			"name":              "test",
+			"kind":              float64(1),
+			"startTimeUnixNano": "1000000000",
+			"endTimeUnixNano":   "2000000000",
+			"attributes": []interface{}{
+				map[string]interface{}{"key": "k8s.object.name", "value": map[string]interface{}{"stringValue": "foo"}},
+			},
+			"status": map[string]interface{}{"code": float64(2), "message": "fake-error"},
+		}))
+	})
+
+	It("doesn't send empty requests", func() {
+		exporter := tracing.NewOTLPExporter(context.Background(), server.URL)
+
+		stop := make(chan struct{})
+		close(stop)
+		Expect(exporter.Start(stop)).To(Succeed())
+		Consistently(requests).ShouldNot(Receive())
+	})
+})
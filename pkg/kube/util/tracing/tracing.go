@@ -0,0 +1,255 @@
+// Package tracing propagates the trace context of a BOSHDeployment. One
+// deployment fans out through several controllers, the trace context is
+// propagated between them via the AnnotationTraceParent annotation on the
+// generated objects, in the W3C trace context format.
+//
+// The trace of a deployment is derived from the UID and generation of the
+// BOSHDeployment, so the annotations only change with the generation and
+// don't cause updates on every reconcile. Each reconcile of an object, which
+// carries the trace context, records a span. Its parent is the root span of
+// the deployment, so all reconciles of one generation form one trace. Spans
+// are passed to the exporter, see SetExporter, and logged on the debug level.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"code.cloudfoundry.org/quarks-utils/pkg/ctxlog"
+)
+
+// AnnotationTraceParent is the annotation, which holds the W3C traceparent
+// of the deployment on generated objects. Output secrets of QuarksJobs carry
+// it as a label, it's a valid label value.
+const AnnotationTraceParent = "quarks.cloudfoundry.org/traceparent"
+
+var (
+	enabled bool
+
+	exporterLock sync.RWMutex
+	exporter     Exporter
+)
+
+// Exporter sends finished spans to a tracing backend. Export is called at the
+// end of each reconcile and must not block.
+type Exporter interface {
+	Export(span *Span)
+}
+
+// SetExporter sets the exporter for the recorded spans, spans are only logged
+// if it's nil
+func SetExporter(e Exporter) {
+	exporterLock.Lock()
+	defer exporterLock.Unlock()
+	exporter = e
+}
+
+// SetEnabled turns the propagation of the trace context on or off
+func SetEnabled(e bool) {
+	enabled = e
+}
+
+// Enabled returns true, if the trace context is propagated
+func Enabled() bool {
+	return enabled
+}
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// SpanID identifies a span
+type SpanID [8]byte
+
+// SpanContext is the part of a span, which is propagated to other controllers
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid returns true, if the trace and span IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// String returns the W3C traceparent of the span context
+func (sc SpanContext) String() string {
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]))
+}
+
+// ParseTraceParent parses a W3C traceparent
+func ParseTraceParent(traceParent string) (SpanContext, bool) {
+	sc := SpanContext{}
+
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return sc, false
+	}
+
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, false
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, false
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+
+	return sc, sc.IsValid()
+}
+
+// DeploymentSpanContext returns the root span context of the current
+// generation of a BOSHDeployment
+func DeploymentSpanContext(deployment metav1.Object) SpanContext {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", deployment.GetUID(), deployment.GetGeneration())))
+
+	sc := SpanContext{}
+	copy(sc.TraceID[:], sum[:16])
+	copy(sc.SpanID[:], sum[16:24])
+	return sc
+}
+
+// FromObject returns the span context propagated to the object, via
+// annotation or label
+func FromObject(object metav1.Object) (SpanContext, bool) {
+	if traceParent, ok := object.GetAnnotations()[AnnotationTraceParent]; ok {
+		return ParseTraceParent(traceParent)
+	}
+	if traceParent, ok := object.GetLabels()[AnnotationTraceParent]; ok {
+		return ParseTraceParent(traceParent)
+	}
+	return SpanContext{}, false
+}
+
+type spanContextKey struct{}
+
+// Span is the record of one reconcile
+type Span struct {
+	Name        string
+	SpanContext SpanContext
+	Parent      SpanID
+	StartTime   time.Time
+	EndTime     time.Time
+	// Attributes identify the reconciled object
+	Attributes map[string]string
+	// Error is the error the reconcile failed with
+	Error string
+
+	ctx context.Context
+}
+
+// SetError marks the span as failed. It does nothing for a nil span.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Error = err.Error()
+}
+
+// End finishes the span and passes it to the exporter. It does nothing for a
+// nil span.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.EndTime = time.Now()
+
+	ctxlog.Debugf(s.ctx, "Span '%s' %s of trace %s took %s",
+		s.Name,
+		hex.EncodeToString(s.SpanContext.SpanID[:]),
+		hex.EncodeToString(s.SpanContext.TraceID[:]),
+		s.EndTime.Sub(s.StartTime),
+	)
+
+	exporterLock.RLock()
+	defer exporterLock.RUnlock()
+	if exporter != nil {
+		exporter.Export(s)
+	}
+}
+
+// StartForDeployment starts the span of a reconcile of the BOSHDeployment.
+// The returned context carries the root span context of the deployment's
+// current generation. The span is nil, if tracing is disabled.
+func StartForDeployment(ctx context.Context, name string, deployment metav1.Object) (context.Context, *Span) {
+	if !enabled {
+		return ctx, nil
+	}
+	return start(ctx, name, deployment, DeploymentSpanContext(deployment))
+}
+
+// StartForObject starts the span of a reconcile of the object. The returned
+// context carries the span context propagated to the object. The span is
+// nil, if tracing is disabled or the object has no trace context.
+func StartForObject(ctx context.Context, name string, object metav1.Object) (context.Context, *Span) {
+	if !enabled {
+		return ctx, nil
+	}
+	sc, ok := FromObject(object)
+	if !ok {
+		return ctx, nil
+	}
+	return start(ctx, name, object, sc)
+}
+
+// start returns a context with the parent span context and a child span of
+// it. Generated objects are annotated with the parent, not with the span of
+// the reconcile, so their annotations don't change with every reconcile.
+func start(ctx context.Context, name string, object metav1.Object, parent SpanContext) (context.Context, *Span) {
+	ctxlog.Debugf(ctx, "Reconciling '%s/%s' in trace %s", object.GetNamespace(), object.GetName(), hex.EncodeToString(parent.TraceID[:]))
+	ctx = context.WithValue(ctx, spanContextKey{}, parent)
+
+	span := &Span{
+		Name:        name,
+		SpanContext: SpanContext{TraceID: parent.TraceID, SpanID: newSpanID()},
+		Parent:      parent.SpanID,
+		StartTime:   time.Now(),
+		Attributes: map[string]string{
+			"k8s.namespace.name": object.GetNamespace(),
+			"k8s.object.name":    object.GetName(),
+		},
+		ctx: ctx,
+	}
+	return ctx, span
+}
+
+func newSpanID() SpanID {
+	id := SpanID{}
+	// crypto/rand doesn't fail on supported platforms
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+// Annotate propagates the span context in the context to the object
+func Annotate(ctx context.Context, object metav1.Object) {
+	traceParent := TraceParent(ctx)
+	if traceParent == "" {
+		return
+	}
+
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationTraceParent] = traceParent
+	object.SetAnnotations(annotations)
+}
+
+// TraceParent returns the W3C traceparent of the span context in the
+// context, it's empty if there is none
+func TraceParent(ctx context.Context) string {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	if !ok {
+		return ""
+	}
+	return sc.String()
+}
@@ -0,0 +1,153 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/tracing"
+)
+
+type fakeExporter struct {
+	spans []*tracing.Span
+}
+
+func (e *fakeExporter) Export(span *tracing.Span) {
+	e.spans = append(e.spans, span)
+}
+
+var _ = Describe("Tracing", func() {
+	var (
+		ctx      context.Context
+		secret   *corev1.Secret
+		exporter *fakeExporter
+	)
+
+	deployment := func(generation int64) *metav1.ObjectMeta {
+		return &metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "a1b2c3", Generation: generation}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		tracing.SetEnabled(true)
+		exporter = &fakeExporter{}
+		tracing.SetExporter(exporter)
+		secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo-secret", Namespace: "default"}}
+	})
+
+	AfterEach(func() {
+		tracing.SetEnabled(false)
+		tracing.SetExporter(nil)
+	})
+
+	Describe("ParseTraceParent", func() {
+		It("parses a W3C traceparent", func() {
+			sc, ok := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			Expect(ok).To(BeTrue())
+			Expect(sc.String()).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+		})
+
+		It("fails for invalid traceparents", func() {
+			for _, traceParent := range []string{
+				"",
+				"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+				"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902zz-01",
+				"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			} {
+				_, ok := tracing.ParseTraceParent(traceParent)
+				Expect(ok).To(BeFalse(), traceParent)
+			}
+		})
+	})
+
+	Describe("DeploymentSpanContext", func() {
+		It("is the same for a generation", func() {
+			sc := tracing.DeploymentSpanContext(deployment(1))
+			Expect(sc.IsValid()).To(BeTrue())
+			Expect(tracing.DeploymentSpanContext(deployment(1))).To(Equal(sc))
+			Expect(tracing.DeploymentSpanContext(deployment(2))).ToNot(Equal(sc))
+		})
+	})
+
+	Describe("StartForDeployment", func() {
+		It("propagates the root span context of the deployment", func() {
+			ctx, _ = tracing.StartForDeployment(ctx, "test", deployment(1))
+			tracing.Annotate(ctx, secret)
+
+			root := tracing.DeploymentSpanContext(deployment(1))
+			Expect(secret.Annotations).To(HaveKeyWithValue(tracing.AnnotationTraceParent, root.String()))
+			Expect(tracing.TraceParent(ctx)).To(Equal(root.String()))
+		})
+
+		It("exports a child span of the root span", func() {
+			_, span := tracing.StartForDeployment(ctx, "test", deployment(1))
+			span.SetError(errors.New("fake-error"))
+			span.End()
+
+			root := tracing.DeploymentSpanContext(deployment(1))
+			Expect(exporter.spans).To(HaveLen(1))
+			exported := exporter.spans[0]
+			Expect(exported.Name).To(Equal("test"))
+			Expect(exported.SpanContext.TraceID).To(Equal(root.TraceID))
+			Expect(exported.SpanContext.SpanID).ToNot(Equal(root.SpanID))
+			Expect(exported.Parent).To(Equal(root.SpanID))
+			Expect(exported.EndTime).ToNot(BeTemporally("<", exported.StartTime))
+			Expect(exported.Attributes).To(HaveKeyWithValue("k8s.object.name", "foo"))
+			Expect(exported.Error).To(Equal("fake-error"))
+		})
+	})
+
+	Describe("StartForObject", func() {
+		It("continues the trace propagated via annotation", func() {
+			root := tracing.DeploymentSpanContext(deployment(1))
+			secret.Annotations = map[string]string{tracing.AnnotationTraceParent: root.String()}
+
+			ctx, _ = tracing.StartForObject(ctx, "test", secret)
+			Expect(tracing.TraceParent(ctx)).To(Equal(root.String()))
+		})
+
+		It("continues the trace propagated via label", func() {
+			root := tracing.DeploymentSpanContext(deployment(1))
+			secret.Labels = map[string]string{tracing.AnnotationTraceParent: root.String()}
+
+			ctx, _ = tracing.StartForObject(ctx, "test", secret)
+			Expect(tracing.TraceParent(ctx)).To(Equal(root.String()))
+		})
+
+		It("doesn't start a trace without trace context", func() {
+			var span *tracing.Span
+			ctx, span = tracing.StartForObject(ctx, "test", secret)
+			Expect(span).To(BeNil())
+			span.End()
+			Expect(exporter.spans).To(BeEmpty())
+
+			other := &corev1.Secret{}
+			tracing.Annotate(ctx, other)
+
+			Expect(tracing.TraceParent(ctx)).To(BeEmpty())
+			Expect(other.Annotations).To(BeEmpty())
+		})
+	})
+
+	Context("when tracing is disabled", func() {
+		BeforeEach(func() {
+			tracing.SetEnabled(false)
+		})
+
+		It("doesn't record spans or annotate objects", func() {
+			var span *tracing.Span
+			ctx, span = tracing.StartForDeployment(ctx, "test", deployment(1))
+			Expect(span).To(BeNil())
+			tracing.Annotate(ctx, secret)
+
+			Expect(secret.Annotations).To(BeEmpty())
+			Expect(tracing.TraceParent(ctx)).To(BeEmpty())
+		})
+	})
+})
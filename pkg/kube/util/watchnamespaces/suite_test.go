@@ -0,0 +1,13 @@
+package watchnamespaces_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWatchNamespaces(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Watch Namespaces Suite")
+}
@@ -0,0 +1,101 @@
+// Package watchnamespaces configures the namespaces watched by the operator.
+// By default the operator watches a single namespace. With a list of
+// namespaces or a namespace label selector, it watches all selected
+// namespaces instead. The operator marks the namespaces it watches with the
+// webhook.LabelWatchNamespace label.
+package watchnamespaces
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
+	"code.cloudfoundry.org/quarks-utils/pkg/config"
+)
+
+var (
+	namespaces = []string{}
+	selector   labels.Selector
+)
+
+// SetNamespaces initializes the package scoped list of watched namespaces.
+func SetNamespaces(names []string) {
+	namespaces = []string{}
+	for _, name := range names {
+		if name != "" {
+			namespaces = append(namespaces, name)
+		}
+	}
+}
+
+// SetSelector initializes the package scoped namespace label selector.
+func SetSelector(s string) error {
+	if s == "" {
+		selector = nil
+		return nil
+	}
+
+	sel, err := labels.Parse(s)
+	if err != nil {
+		return errors.Wrapf(err, "invalid namespace selector '%s'", s)
+	}
+	selector = sel
+	return nil
+}
+
+// Namespaces returns the package scoped list of watched namespaces.
+func Namespaces() []string {
+	return namespaces
+}
+
+// Enabled returns true, if the operator watches several namespaces instead
+// of config.Namespace.
+func Enabled() bool {
+	return len(namespaces) > 0 || selector != nil
+}
+
+// Selected returns true, if the namespace is in the list of watched
+// namespaces or matches the namespace selector. The operator namespace is
+// never selected.
+func Selected(ns metav1.Object, operatorNamespace string) bool {
+	if ns.GetName() == operatorNamespace {
+		return false
+	}
+
+	for _, name := range namespaces {
+		if ns.GetName() == name {
+			return true
+		}
+	}
+
+	return selector != nil && selector.Matches(labels.Set(ns.GetLabels()))
+}
+
+// IsWatched returns true, if the namespace carries the watch label of the
+// operator in operatorNamespace.
+func IsWatched(ns metav1.Object, operatorNamespace string) bool {
+	return ns.GetLabels()[webhook.LabelWatchNamespace] == operatorNamespace
+}
+
+// IsWatchedNamespace returns true, if the operator watches the namespace
+// with the given name.
+func IsWatchedNamespace(ctx context.Context, c client.Client, config *config.Config, name string) (bool, error) {
+	if !Enabled() {
+		return name == config.Namespace, nil
+	}
+
+	ns := &corev1.Namespace{}
+	err := c.Get(ctx, types.NamespacedName{Name: name}, ns)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get namespace '%s'", name)
+	}
+
+	return IsWatched(ns, config.OperatorNamespace), nil
+}
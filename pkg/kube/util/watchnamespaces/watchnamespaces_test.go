@@ -0,0 +1,70 @@
+package watchnamespaces_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/watchnamespaces"
+	"code.cloudfoundry.org/cf-operator/pkg/kube/util/webhook"
+)
+
+var _ = Describe("WatchNamespaces", func() {
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	AfterEach(func() {
+		watchnamespaces.SetNamespaces([]string{})
+		Expect(watchnamespaces.SetSelector("")).To(Succeed())
+	})
+
+	It("is disabled by default", func() {
+		Expect(watchnamespaces.Enabled()).To(BeFalse())
+	})
+
+	Context("with a list of namespaces", func() {
+		BeforeEach(func() {
+			watchnamespaces.SetNamespaces([]string{"cf1", "", "cf2"})
+		})
+
+		It("selects the listed namespaces", func() {
+			Expect(watchnamespaces.Enabled()).To(BeTrue())
+			Expect(watchnamespaces.Namespaces()).To(Equal([]string{"cf1", "cf2"}))
+			Expect(watchnamespaces.Selected(namespace("cf2", nil), "cf-operator")).To(BeTrue())
+			Expect(watchnamespaces.Selected(namespace("cf3", nil), "cf-operator")).To(BeFalse())
+		})
+	})
+
+	Context("with a namespace selector", func() {
+		BeforeEach(func() {
+			Expect(watchnamespaces.SetSelector("cf-operator/watch=true")).To(Succeed())
+		})
+
+		It("selects the matching namespaces", func() {
+			Expect(watchnamespaces.Enabled()).To(BeTrue())
+			Expect(watchnamespaces.Selected(namespace("cf1", map[string]string{"cf-operator/watch": "true"}), "cf-operator")).To(BeTrue())
+			Expect(watchnamespaces.Selected(namespace("cf2", map[string]string{"cf-operator/watch": "false"}), "cf-operator")).To(BeFalse())
+		})
+
+		It("never selects the operator namespace", func() {
+			Expect(watchnamespaces.Selected(namespace("cf-operator", map[string]string{"cf-operator/watch": "true"}), "cf-operator")).To(BeFalse())
+		})
+
+		It("fails for invalid selectors", func() {
+			err := watchnamespaces.SetSelector("cf-operator/watch in true")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid namespace selector"))
+		})
+	})
+
+	Describe("IsWatched", func() {
+		It("checks the watch label of the operator", func() {
+			Expect(watchnamespaces.IsWatched(namespace("cf1", map[string]string{webhook.LabelWatchNamespace: "cf-operator"}), "cf-operator")).To(BeTrue())
+			Expect(watchnamespaces.IsWatched(namespace("cf1", map[string]string{webhook.LabelWatchNamespace: "other-operator"}), "cf-operator")).To(BeFalse())
+			Expect(watchnamespaces.IsWatched(namespace("cf1", nil), "cf-operator")).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package withops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
+	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
+	"code.cloudfoundry.org/quarks-utils/pkg/names"
+)
+
+// interpolateImplicitVariables replaces the implicit variables of the
+// manifest with the values from their secrets. It returns the names of the
+// secrets and an error for each variable, which couldn't be loaded.
+func (r *Resolver) interpolateImplicitVariables(namespace string, deploymentName string, manifest *bdm.Manifest) (*bdm.Manifest, []string, []error) {
+	vars, err := manifest.ImplicitVariables()
+	if err != nil {
+		return nil, []string{}, []error{errors.Wrapf(err, "failed to list implicit variables")}
+	}
+
+	errs := []error{}
+	varSecrets := make([]string, len(vars))
+	for i, v := range vars {
+		varSecretName, value, err := r.implicitVariableValue(namespace, deploymentName, v)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to load secret for variable '%s'", v))
+			continue
+		}
+
+		varSecrets[i] = varSecretName
+		manifest = r.replaceVar(manifest, v, value)
+	}
+
+	return manifest, varSecrets, errs
+}
+
+// implicitVariableValue loads the value of an implicit variable from its
+// secret. Variables are referenced by:
+//
+//   - '((name))', the 'value' key of the secret
+//   - '((name/key))', any key of the secret
+//   - '((name.key.nested))', BOSH's dot syntax, either a key of the secret or
+//     a path into the YAML document of the 'value' key
+//
+// Keys can be followed by a path into their YAML document, e.g. '((name/key.nested))'.
+func (r *Resolver) implicitVariableValue(namespace string, deploymentName string, reference string) (string, interface{}, error) {
+	name, rest, sep := splitVariableReference(reference)
+	if sep == "/" && strings.Contains(rest, "/") {
+		return "", nil, fmt.Errorf("expected one / separator for implicit variable/key name, have %d", strings.Count(reference, "/")+1)
+	}
+
+	secretName := names.DeploymentSecretName(names.DeploymentSecretTypeVariable, deploymentName, name)
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+	if err != nil {
+		return secretName, nil, errors.Wrapf(err, "failed to retrieve secret '%s/%s' via client.Get", namespace, secretName)
+	}
+
+	key := bdv1.ImplicitVariableKeyName
+	path := []string{}
+	if rest != "" {
+		var ok bool
+		key, path, ok = findSecretKey(secret.Data, rest)
+		if !ok {
+			if sep == "/" {
+				return secretName, nil, fmt.Errorf("secret '%s/%s' doesn't contain key %s", namespace, secretName, rest)
+			}
+			key = bdv1.ImplicitVariableKeyName
+			path = strings.Split(rest, ".")
+		}
+	}
+
+	data, ok := secret.Data[key]
+	if !ok {
+		return secretName, nil, fmt.Errorf("secret '%s/%s' doesn't contain key %s", namespace, secretName, key)
+	}
+
+	value := typedValue(data)
+	for i, field := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return secretName, nil, fmt.Errorf("value of key %s in secret '%s/%s' has no field '%s'", key, namespace, secretName, strings.Join(path[:i+1], "."))
+		}
+		if value, ok = m[field]; !ok {
+			return secretName, nil, fmt.Errorf("value of key %s in secret '%s/%s' has no field '%s'", key, namespace, secretName, strings.Join(path[:i+1], "."))
+		}
+	}
+
+	return secretName, value, nil
+}
+
+// splitVariableReference splits a reference at the first '/' or '.' into
+// the variable name and the rest
+func splitVariableReference(reference string) (string, string, string) {
+	i := strings.IndexAny(reference, "/.")
+	if i < 0 {
+		return reference, "", ""
+	}
+	return reference[:i], reference[i+1:], reference[i : i+1]
+}
+
+// findSecretKey finds the longest key of the secret, which prefixes the
+// dotted path. Secret keys can contain dots themselves, e.g. 'tls.crt'.
+func findSecretKey(data map[string][]byte, path string) (string, []string, bool) {
+	fields := strings.Split(path, ".")
+	for i := len(fields); i > 0; i-- {
+		key := strings.Join(fields[:i], ".")
+		if _, ok := data[key]; ok {
+			return key, fields[i:], true
+		}
+	}
+	return "", nil, false
+}
+
+// typedValue parses the YAML value of a secret key, so maps and numbers are
+// not interpolated as strings. Strings are kept as they are, unless they are
+// quoted.
+func typedValue(data []byte) interface{} {
+	var value interface{}
+	err := yaml.Unmarshal(data, &value, func(d *json.Decoder) *json.Decoder {
+		d.UseNumber()
+		return d
+	})
+	if err != nil || value == nil {
+		return string(data)
+	}
+
+	if s, ok := value.(string); ok {
+		trimmed := strings.TrimSpace(string(data))
+		if strings.HasPrefix(trimmed, `"`) || strings.HasPrefix(trimmed, `'`) {
+			return s
+		}
+		return string(data)
+	}
+
+	return value
+}
+
+// stringValue returns the value for embedding it into a string
+func stringValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -16,7 +17,6 @@ import (
 
 	bdm "code.cloudfoundry.org/cf-operator/pkg/bosh/manifest"
 	bdv1 "code.cloudfoundry.org/cf-operator/pkg/kube/apis/boshdeployment/v1alpha1"
-	"code.cloudfoundry.org/quarks-utils/pkg/names"
 	"code.cloudfoundry.org/quarks-utils/pkg/versionedsecretstore"
 )
 
@@ -94,39 +94,20 @@ func (r *Resolver) Manifest(bdpl *bdv1.BOSHDeployment, namespace string) (*bdm.M
 		return nil, []string{}, errors.Wrapf(err, "Loading yaml failed in interpolation task after applying ops %#v", m)
 	}
 
-	// Interpolate implicit variables
-	vars, err := manifest.ImplicitVariables()
+	// Merge the runtime configs of the namespace
+	err = r.applyRuntimeConfigs(namespace, bdpl.GetName(), manifest)
 	if err != nil {
-		return nil, []string{}, errors.Wrapf(err, "failed to list implicit variables")
+		return nil, []string{}, errors.Wrapf(err, "failed to apply runtime configs for bosh deployment %s", bdpl.GetName())
 	}
 
-	varSecrets := make([]string, len(vars))
-	for i, v := range vars {
-		varKeyName := ""
-		varSecretName := ""
-		if strings.Contains(v, "/") {
-			parts := strings.Split(v, "/")
-			if len(parts) != 2 {
-				return nil, []string{}, fmt.Errorf("expected one / separator for implicit variable/key name, have %d", len(parts))
-			}
-
-			varSecretName = names.DeploymentSecretName(names.DeploymentSecretTypeVariable, bdpl.GetName(), parts[0])
-			varKeyName = parts[1]
-		} else {
-			varKeyName = bdv1.ImplicitVariableKeyName
-			varSecretName = names.DeploymentSecretName(names.DeploymentSecretTypeVariable, bdpl.GetName(), v)
-		}
-
-		varData, err := r.resourceData(namespace, bdv1.SecretReference, varSecretName, varKeyName)
-		if err != nil {
-			return nil, varSecrets, errors.Wrapf(err, "failed to load secret for variable '%s'", v)
-		}
-
-		varSecrets[i] = varSecretName
-		manifest = r.replaceVar(manifest, v, varData)
+	// Interpolate implicit variables
+	manifest, varSecrets, errs := r.interpolateImplicitVariables(namespace, bdpl.GetName(), manifest)
+	if len(errs) > 0 {
+		return nil, varSecrets, errs[0]
 	}
 
 	// Apply addons
+	manifest.ApplyAddOnDeploymentRules(bdpl.GetName())
 	err = manifest.ApplyAddons()
 	if err != nil {
 		return nil, varSecrets, errors.Wrapf(err, "failed to apply addons")
@@ -184,39 +165,25 @@ func (r *Resolver) ManifestDetailed(bdpl *bdv1.BOSHDeployment, namespace string)
 		return nil, []string{}, errors.Wrapf(err, "Loading yaml failed in interpolation task after applying ops %#v", m)
 	}
 
-	// Interpolate implicit variables
-	vars, err := manifest.ImplicitVariables()
+	// Merge the runtime configs of the namespace
+	err = r.applyRuntimeConfigs(namespace, bdpl.GetName(), manifest)
 	if err != nil {
-		return nil, []string{}, errors.Wrapf(err, "failed to list implicit variables")
+		return nil, []string{}, errors.Wrapf(err, "failed to apply runtime configs for bosh deployment %s", bdpl.GetName())
 	}
 
-	varSecrets := make([]string, len(vars))
-	for i, v := range vars {
-		varKeyName := ""
-		varSecretName := ""
-		if strings.Contains(v, "/") {
-			parts := strings.Split(v, "/")
-			if len(parts) != 2 {
-				return nil, []string{}, fmt.Errorf("expected one / separator for implicit variable/key name, have %d", len(parts))
-			}
-
-			varSecretName = names.DeploymentSecretName(names.DeploymentSecretTypeVariable, bdpl.GetName(), parts[0])
-			varKeyName = parts[1]
-		} else {
-			varKeyName = bdv1.ImplicitVariableKeyName
-			varSecretName = names.DeploymentSecretName(names.DeploymentSecretTypeVariable, bdpl.GetName(), v)
+	// Interpolate implicit variables, reporting all missing variables at once for more helpful validation errors
+	manifest, varSecrets, errs := r.interpolateImplicitVariables(namespace, bdpl.GetName(), manifest)
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
 		}
-
-		varData, err := r.resourceData(namespace, bdv1.SecretReference, varSecretName, varKeyName)
-		if err != nil {
-			return nil, varSecrets, errors.Wrapf(err, "failed to load secret for variable '%s'", v)
-		}
-
-		varSecrets[i] = varSecretName
-		manifest = r.replaceVar(manifest, v, varData)
+		sort.Strings(msgs)
+		return nil, varSecrets, errors.New(strings.Join(msgs, "; "))
 	}
 
 	// Apply addons
+	manifest.ApplyAddOnDeploymentRules(bdpl.GetName())
 	err = manifest.ApplyAddons()
 	if err != nil {
 		return nil, varSecrets, errors.Wrapf(err, "failed to apply addons")
@@ -231,7 +198,7 @@ func (r *Resolver) ManifestDetailed(bdpl *bdv1.BOSHDeployment, namespace string)
 	return manifest, varSecrets, err
 }
 
-func (r *Resolver) replaceVar(manifest *bdm.Manifest, name, value string) *bdm.Manifest {
+func (r *Resolver) replaceVar(manifest *bdm.Manifest, name string, value interface{}) *bdm.Manifest {
 	original := reflect.ValueOf(manifest)
 	replaced := reflect.New(original.Type()).Elem()
 
@@ -239,7 +206,11 @@ func (r *Resolver) replaceVar(manifest *bdm.Manifest, name, value string) *bdm.M
 
 	return replaced.Interface().(*bdm.Manifest)
 }
-func (r *Resolver) replaceVarRecursive(copy, v reflect.Value, varName, varValue string) {
+
+// replaceVarRecursive copies v and replaces the variable in all strings.
+// Strings, which consist of the variable only, are replaced by its typed value
+// if possible.
+func (r *Resolver) replaceVarRecursive(copy, v reflect.Value, varName string, varValue interface{}) {
 	switch v.Kind() {
 	case reflect.Ptr:
 		if !v.Elem().IsValid() {
@@ -253,6 +224,10 @@ func (r *Resolver) replaceVarRecursive(copy, v reflect.Value, varName, varValue
 		if !originalValue.IsValid() {
 			return
 		}
+		if originalValue.Kind() == reflect.String && originalValue.String() == fmt.Sprintf("((%s))", varName) && varValue != nil {
+			copy.Set(reflect.ValueOf(varValue))
+			return
+		}
 		copyValue := reflect.New(originalValue.Type()).Elem()
 		r.replaceVarRecursive(copyValue, originalValue, varName, varValue)
 		copy.Set(copyValue)
@@ -284,7 +259,7 @@ func (r *Resolver) replaceVarRecursive(copy, v reflect.Value, varName, varValue
 
 	case reflect.String:
 		if copy.CanSet() {
-			replaced := strings.Replace(v.String(), fmt.Sprintf("((%s))", varName), varValue, -1)
+			replaced := strings.Replace(v.String(), fmt.Sprintf("((%s))", varName), stringValue(varValue), -1)
 			copy.SetString(replaced)
 		}
 	default:
@@ -294,6 +269,37 @@ func (r *Resolver) replaceVarRecursive(copy, v reflect.Value, varName, varValue
 	}
 }
 
+// applyRuntimeConfigs merges all runtime configs of the namespace into the manifest, ordered by name
+func (r *Resolver) applyRuntimeConfigs(namespace string, deploymentName string, manifest *bdm.Manifest) error {
+	configs := &corev1.ConfigMapList{}
+	err := r.client.List(context.TODO(), configs,
+		client.InNamespace(namespace),
+		client.MatchingLabels{bdv1.LabelRuntimeConfig: "true"},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list runtime configs in namespace '%s'", namespace)
+	}
+
+	sort.Slice(configs.Items, func(i, j int) bool {
+		return configs.Items[i].Name < configs.Items[j].Name
+	})
+
+	for _, config := range configs.Items {
+		data, ok := config.Data[bdv1.RuntimeConfigSpecName]
+		if !ok {
+			return fmt.Errorf("configMap '%s/%s' doesn't contain key %s", namespace, config.Name, bdv1.RuntimeConfigSpecName)
+		}
+
+		rc, err := bdm.LoadRuntimeConfigYAML([]byte(data))
+		if err != nil {
+			return errors.Wrapf(err, "failed to load runtime config '%s/%s'", namespace, config.Name)
+		}
+		manifest.ApplyRuntimeConfig(deploymentName, rc)
+	}
+
+	return nil
+}
+
 // resourceData resolves different manifest reference types and returns the resource's data
 func (r *Resolver) resourceData(namespace string, resType bdv1.ReferenceType, name string, key string) (string, error) {
 	var (
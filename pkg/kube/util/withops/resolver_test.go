@@ -1,6 +1,8 @@
 package withops_test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 
 	. "github.com/onsi/ginkgo"
@@ -222,6 +224,63 @@ instance_groups:
 				},
 				Data: map[string][]byte{"value": []byte("complicated\n'multiline'\nstring")},
 			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "manifest-with-typed-implicit-vars",
+					Namespace: "default",
+				},
+				Data: map[string]string{bdc.ManifestSpecName: `---
+name: foo
+instance_groups:
+  - name: component1
+    instances: 1
+    properties:
+      port: ((nats.port))
+      tls: ((nats.tls))
+      cert: ((nats.tls.cert))
+      url: 'nats://((nats.host)):((nats.port))'
+      router_cert: ((router/tls.crt))
+      router_key: ((router.tls.key))
+      password: ((password))
+      replicas: ((replicas))
+`},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-deployment.var-nats",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{"value": []byte(`host: nats.example.com
+port: 4222
+tls:
+  cert: the-cert
+  key: the-key
+`)},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-deployment.var-router",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"tls.crt": []byte("the-router-cert"),
+					"tls.key": []byte("the-router-key"),
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-deployment.var-password",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{"value": []byte(`"123456"`)},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-deployment.var-replicas",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{"value": []byte("3")},
+			},
 			&corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo-deployment.var-ssl",
@@ -823,6 +882,89 @@ instance_groups:
 			Expect(implicitVars[0]).To(Equal("foo-deployment.var-system-domain"))
 		})
 
+		It("applies runtime configs of the namespace", func() {
+			runtimeConfig := func(name, namespace, data string) *corev1.ConfigMap {
+				return &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: namespace,
+						Labels:    map[string]string{bdc.LabelRuntimeConfig: "true"},
+					},
+					Data: map[string]string{bdc.RuntimeConfigSpecName: data},
+				}
+			}
+			Expect(client.Create(context.Background(), runtimeConfig("os-conf", "default", `---
+releases:
+- name: os-conf
+  version: 20.0.0
+addons:
+- name: syslog
+  include:
+    deployments: [foo]
+  jobs:
+  - name: syslog_forwarder
+    release: os-conf
+`))).To(Succeed())
+			Expect(client.Create(context.Background(), runtimeConfig("other", "default", `---
+addons:
+- name: other
+  include:
+    deployments: [bar]
+  jobs:
+  - name: other
+    release: os-conf
+`))).To(Succeed())
+			Expect(client.Create(context.Background(), runtimeConfig("other-namespace", "other", `---
+variables:
+- name: other
+  type: password
+`))).To(Succeed())
+
+			deployment := &bdc.BOSHDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: bdc.BOSHDeploymentSpec{
+					Manifest: bdc.ResourceReference{
+						Type: bdc.ConfigMapReference,
+						Name: "base-manifest",
+					},
+				},
+			}
+
+			manifest, _, err := resolver.Manifest(deployment, "default")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest.Releases).To(HaveLen(1))
+			Expect(manifest.Releases[0].Name).To(Equal("os-conf"))
+			Expect(manifest.Variables).To(BeEmpty())
+			for _, ig := range manifest.InstanceGroups {
+				Expect(ig.Jobs).To(HaveLen(1))
+				Expect(ig.Jobs[0].Name).To(Equal("syslog_forwarder"))
+			}
+		})
+
+		It("throws an error if a runtime config is invalid", func() {
+			Expect(client.Create(context.Background(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "broken",
+					Namespace: "default",
+					Labels:    map[string]string{bdc.LabelRuntimeConfig: "true"},
+				},
+				Data: map[string]string{"manifest": "---"},
+			})).To(Succeed())
+
+			deployment := &bdc.BOSHDeployment{
+				Spec: bdc.BOSHDeploymentSpec{
+					Manifest: bdc.ResourceReference{
+						Type: bdc.ConfigMapReference,
+						Name: "base-manifest",
+					},
+				},
+			}
+
+			_, _, err := resolver.Manifest(deployment, "default")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("doesn't contain key runtime-config"))
+		})
+
 		It("loads dns from addons", func() {
 			deploymentName := "scf"
 			var dns withops.DomainNameService
@@ -919,5 +1061,54 @@ instance_groups:
 			Expect(sslProps["cert"]).To(Equal("the-cert"))
 			Expect(sslProps["key"]).To(Equal("the-key"))
 		})
+
+		Context("when implicit variables use the dot syntax", func() {
+			var deployment *bdc.BOSHDeployment
+
+			BeforeEach(func() {
+				deployment = &bdc.BOSHDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo-deployment",
+					},
+					Spec: bdc.BOSHDeploymentSpec{
+						Manifest: bdc.ResourceReference{
+							Type: bdc.ConfigMapReference,
+							Name: "manifest-with-typed-implicit-vars",
+						},
+					},
+				}
+			})
+
+			It("replaces nested and typed values", func() {
+				m, implicitVars, err := resolver.Manifest(deployment, "default")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(implicitVars).To(ContainElement("foo-deployment.var-nats"))
+
+				props := m.InstanceGroups[0].Properties.Properties
+				Expect(props["port"]).To(Equal(json.Number("4222")))
+				Expect(props["tls"]).To(Equal(map[string]interface{}{"cert": "the-cert", "key": "the-key"}))
+				Expect(props["cert"]).To(Equal("the-cert"))
+				Expect(props["url"]).To(Equal("nats://nats.example.com:4222"))
+				Expect(props["router_cert"]).To(Equal("the-router-cert"))
+				Expect(props["router_key"]).To(Equal("the-router-key"))
+				Expect(props["password"]).To(Equal("123456"))
+				Expect(props["replicas"]).To(Equal(json.Number("3")))
+			})
+
+			It("reports missing fields", func() {
+				Expect(client.Delete(context.Background(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo-deployment.var-nats", Namespace: "default"},
+				})).To(Succeed())
+				Expect(client.Create(context.Background(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo-deployment.var-nats", Namespace: "default"},
+					Data:       map[string][]byte{"value": []byte("host: nats.example.com")},
+				})).To(Succeed())
+
+				_, _, err := resolver.ManifestDetailed(deployment, "default")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("failed to load secret for variable 'nats.port': value of key value in secret 'default/foo-deployment.var-nats' has no field 'port'"))
+				Expect(err.Error()).To(ContainSubstring("failed to load secret for variable 'nats.tls.cert': value of key value in secret 'default/foo-deployment.var-nats' has no field 'tls'"))
+			})
+		})
 	})
 })